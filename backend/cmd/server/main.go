@@ -14,6 +14,9 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/telegram-task-manager/backend/internal/config"
 	"github.com/telegram-task-manager/backend/internal/handler"
+	"github.com/telegram-task-manager/backend/internal/importer"
+	"github.com/telegram-task-manager/backend/internal/importer/todoist"
+	"github.com/telegram-task-manager/backend/internal/importer/trello"
 	"github.com/telegram-task-manager/backend/internal/repository/postgres"
 	"github.com/telegram-task-manager/backend/internal/scheduler"
 	"github.com/telegram-task-manager/backend/internal/service"
@@ -65,6 +68,15 @@ func main() {
 	reminderRepo := postgres.NewReminderRepository(dbPool)
 	activityRepo := postgres.NewActivityLogRepository(dbPool)
 	habitRepo := postgres.NewHabitCompletionRepository(dbPool)
+	calendarFeedRepo := postgres.NewCalendarFeedRepository(dbPool)
+	botListingRepo := postgres.NewBotListingRepository(dbPool)
+	updateOffsetRepo := postgres.NewUpdateOffsetRepository(dbPool)
+	outboxRepo := postgres.NewOutboxRepository(dbPool)
+	jobLocker := postgres.NewJobLocker(dbPool)
+	dueSoonRepo := postgres.NewDueSoonMarkerRepository(dbPool)
+	exportRepo := postgres.NewExportRepository(dbPool)
+	importRepo := postgres.NewImportRepository(dbPool)
+	searchRepo := postgres.NewSearchRepository(dbPool)
 
 	// Initialize Telegram components
 	telegramBot := telegram.NewBot(cfg.Telegram.BotToken)
@@ -78,27 +90,66 @@ func main() {
 	}
 	logger.Info("telegram bot verified", "username", botInfo.Username)
 
+	// Register webhook
+	if cfg.Telegram.Mode == config.TelegramModeWebhook && cfg.Telegram.WebhookURL != "" {
+		webhookInfo, err := telegramBot.RegisterWebhook(telegram.SetWebhookRequest{
+			URL:            cfg.Telegram.WebhookURL,
+			SecretToken:    cfg.Telegram.WebhookSecret,
+			MaxConnections: cfg.Telegram.WebhookMaxConnections,
+		})
+		switch {
+		case err != nil:
+			logger.Error("failed to register telegram webhook", "url", cfg.Telegram.WebhookURL, "error", err)
+		case webhookInfo.LastErrorDate != 0:
+			logger.Warn("telegram webhook reports delivery errors",
+				"url", webhookInfo.URL,
+				"last_error", webhookInfo.LastErrorMessage,
+				"last_error_at", time.Unix(webhookInfo.LastErrorDate, 0),
+				"pending_updates", webhookInfo.PendingUpdateCount,
+			)
+		default:
+			logger.Info("telegram webhook registered", "url", webhookInfo.URL, "pending_updates", webhookInfo.PendingUpdateCount)
+		}
+	}
+
 	// Initialize services
 	authService := service.NewAuthService(userRepo, initDataValidator, cfg.JWT.Secret, cfg.JWT.ExpirationHours)
 	folderService := service.NewFolderService(folderRepo, activityRepo)
-	boardService := service.NewBoardService(boardRepo, folderRepo, activityRepo)
-	itemService := service.NewItemService(itemRepo, boardRepo, reminderRepo, activityRepo, habitRepo)
+	itemService := service.NewItemService(itemRepo, boardRepo, reminderRepo, activityRepo, habitRepo, userRepo)
+	boardService := service.NewBoardService(boardRepo, folderRepo, activityRepo, itemService)
+	botService := service.NewBotService(itemService, itemRepo, boardRepo, userRepo, botListingRepo)
+	calendarService := service.NewCalendarService(itemRepo, boardRepo, userRepo, activityRepo)
+	calendarFeedService := service.NewCalendarFeedService(calendarFeedRepo, boardRepo, itemRepo, userRepo, cfg.Server.PublicURL)
 	analyticsService := service.NewAnalyticsService(userRepo, folderRepo, boardRepo, itemRepo)
+	digestService := service.NewDigestService(folderRepo, itemRepo, habitRepo)
+	weeklyReportService := service.NewWeeklyReportService(folderRepo, itemRepo, habitRepo, activityRepo)
+	exportService := service.NewExportService(userRepo, exportRepo, folderRepo, boardRepo, itemRepo)
+	importService := service.NewImportService(userRepo, importRepo, importer.Registry{
+		"trello":  trello.New(),
+		"todoist": todoist.New(),
+	})
+	searchService := service.NewSearchService(searchRepo)
 	notificationService := service.NewNotificationService(
 		telegramBot,
 		userRepo,
 		itemRepo,
 		reminderRepo,
+		outboxRepo,
 		cfg.Telegram.AppURL,
+		cfg.Telegram.MiniAppLink,
 		logger,
 	)
 
 	// Initialize scheduler
 	reminderScheduler := scheduler.NewReminderScheduler(
 		notificationService,
+		digestService,
+		weeklyReportService,
 		userRepo,
 		itemRepo,
 		reminderRepo,
+		dueSoonRepo,
+		jobLocker,
 		logger,
 	)
 
@@ -114,7 +165,13 @@ func main() {
 	folderHandler := handler.NewFolderHandler(folderService)
 	boardHandler := handler.NewBoardHandler(boardService)
 	itemHandler := handler.NewItemHandler(itemService, analyticsService)
-	webhookHandler := handler.NewWebhookHandler(telegramBot, cfg.Telegram.AppURL, logger)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(calendarFeedService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	exportHandler := handler.NewExportHandler(exportService, logger)
+	importHandler := handler.NewImportHandler(importService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	webhookHandler := handler.NewWebhookHandler(telegramBot, itemService, botService, cfg.Telegram.AppURL, cfg.Telegram.WebhookSecret, logger)
 
 	// Setup Gin
 	gin.SetMode(cfg.Server.Mode)
@@ -144,14 +201,19 @@ func main() {
 	router.GET("/health", healthHandler)
 	router.GET("/api/health", healthHandler)
 
+	// iCalendar subscription feeds (public - protected by the secret token in the URL)
+	router.GET("/ical/:token", calendarFeedHandler.GetFeed)
+
 	// Rate limiter for auth endpoints (10 requests per minute)
 	authRateLimiter := middleware.RateLimit(10, time.Minute)
 
 	// API routes
 	api := router.Group("/api")
 	{
-		// Telegram webhook (public - called by Telegram servers)
-		api.POST("/telegram/webhook", webhookHandler.HandleWebhook)
+		// Telegram webhook (public - called by Telegram servers, verified by the secret token header)
+		if cfg.Telegram.Mode == config.TelegramModeWebhook {
+			api.POST("/telegram/webhook", webhookHandler.HandleWebhook)
+		}
 
 		// Auth routes (public, with rate limiting)
 		auth := api.Group("/auth")
@@ -165,6 +227,11 @@ func main() {
 				authProtected.GET("/me", authHandler.GetCurrentUser)
 				authProtected.PUT("/settings", authHandler.UpdateSettings)
 				authProtected.POST("/refresh", authHandler.RefreshToken)
+
+				// Calendar feed tokens
+				authProtected.GET("/settings/ical-feeds", calendarFeedHandler.ListFeeds)
+				authProtected.POST("/settings/ical-feeds", calendarFeedHandler.CreateFeed)
+				authProtected.DELETE("/settings/ical-feeds/:feedId", calendarFeedHandler.DeleteFeed)
 			}
 		}
 
@@ -186,6 +253,9 @@ func main() {
 				folders.GET("/:folderId/boards", boardHandler.ListBoards)
 				folders.POST("/:folderId/boards", boardHandler.CreateBoard)
 				folders.PUT("/:folderId/boards/reorder", boardHandler.ReorderBoards)
+
+				// Notes as Markdown files
+				folders.GET("/:folderId/export/markdown", exportHandler.ExportFolderMarkdown)
 			}
 
 			// Boards
@@ -198,7 +268,17 @@ func main() {
 				// Items within board
 				boards.GET("/:boardId/items", itemHandler.ListItems)
 				boards.POST("/:boardId/items", itemHandler.CreateItem)
+				boards.POST("/:boardId/items/quick", itemHandler.QuickAddItem)
 				boards.PUT("/:boardId/items/reorder", itemHandler.ReorderItems)
+
+				// Items as CSV, for editing in spreadsheets
+				boards.GET("/:boardId/export.csv", itemHandler.ExportItemsCSV)
+				boards.POST("/:boardId/import.csv", itemHandler.ImportItemsCSV)
+				boards.GET("/:boardId/export/markdown", exportHandler.ExportBoardMarkdown)
+
+				// Calendar view with expanded recurring events
+				boards.GET("/:boardId/calendar", calendarHandler.GetBoardCalendar)
+				boards.POST("/:boardId/calendar/import", calendarHandler.ImportCalendar)
 			}
 
 			// Items
@@ -215,6 +295,10 @@ func main() {
 				items.POST("/:id/habit/complete", itemHandler.CompleteHabit)
 				items.DELETE("/:id/habit/complete", itemHandler.UncompleteHabit)
 				items.GET("/:id/habit/completions", itemHandler.GetHabitCompletions)
+
+				// Recurring occurrence exceptions
+				items.POST("/:id/exceptions", calendarHandler.SetException)
+				items.DELETE("/:id/exceptions", calendarHandler.RemoveException)
 			}
 
 			// Analytics
@@ -223,6 +307,19 @@ func main() {
 				analytics.GET("/overview", itemHandler.GetAnalyticsOverview)
 				analytics.GET("/completion", itemHandler.GetCompletionStats)
 			}
+
+			// Notifications
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("/failed", notificationHandler.ListFailed)
+			}
+
+			// Account export and import
+			protected.GET("/export", exportHandler.Export)
+			protected.POST("/import", importHandler.Import)
+
+			// Full-text search over all items
+			protected.GET("/search", searchHandler.Search)
 		}
 	}
 
@@ -243,6 +340,21 @@ func main() {
 		}
 	}()
 
+	// Receive bot updates with getUpdates instead of the webhook
+	pollCtx, stopPolling := context.WithCancel(context.Background())
+	defer stopPolling()
+	pollDone := make(chan struct{})
+
+	if cfg.Telegram.Mode == config.TelegramModePolling {
+		poller := telegram.NewPoller(telegramBot, botInfo.ID, updateOffsetRepo, webhookHandler.HandleUpdate, logger)
+		go func() {
+			defer close(pollDone)
+			poller.Run(pollCtx)
+		}()
+	} else {
+		close(pollDone)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -250,6 +362,10 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	// Stop polling and let the update in progress finish
+	stopPolling()
+	<-pollDone
+
 	// Graceful shutdown with timeout
 	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -21,6 +21,7 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	Mode         string // "debug", "release", "test"
+	PublicURL    string // Base URL for links served outside the Mini App (e.g. iCal feeds)
 }
 
 type DatabaseConfig struct {
@@ -36,9 +37,26 @@ type DatabaseConfig struct {
 	MaxConnIdleTime time.Duration
 }
 
+// Telegram update delivery modes
+const (
+	TelegramModeWebhook = "webhook"
+	TelegramModePolling = "polling"
+)
+
 type TelegramConfig struct {
 	BotToken string
 	AppURL   string
+	Mode     string // "webhook" or "polling" (getUpdates, for local development and hosts without public HTTPS)
+
+	// MiniAppLink is the t.me link of the Mini App (https://t.me/<bot>/<app>),
+	// used for deep links in message text. Optional.
+	MiniAppLink string
+
+	// WebhookURL is registered with setWebhook at startup when set
+	WebhookURL string
+	// WebhookSecret is sent by Telegram in the X-Telegram-Bot-Api-Secret-Token header
+	WebhookSecret         string
+	WebhookMaxConnections int
 }
 
 type JWTConfig struct {
@@ -59,17 +77,40 @@ func Load() (*Config, error) {
 		jwtSecret = "dev-only-secret-do-not-use-in-production"
 	}
 
+	telegramMode := getEnv("TELEGRAM_MODE", TelegramModeWebhook)
+	if telegramMode != TelegramModeWebhook && telegramMode != TelegramModePolling {
+		return nil, fmt.Errorf("TELEGRAM_MODE must be %q or %q", TelegramModeWebhook, TelegramModePolling)
+	}
+
+	// TELEGRAM_WEBHOOK_SECRET is required for webhooks in production, otherwise
+	// anyone who knows the webhook URL can post updates
+	webhookSecret := os.Getenv("TELEGRAM_WEBHOOK_SECRET")
+	if webhookSecret == "" && telegramMode == TelegramModeWebhook && getEnv("GIN_MODE", "debug") != "debug" {
+		return nil, fmt.Errorf("TELEGRAM_WEBHOOK_SECRET environment variable is required in production")
+	}
+	if !validWebhookSecret(webhookSecret) {
+		return nil, fmt.Errorf("TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+
+	appURL := getEnv("TELEGRAM_MINI_APP_URL", "")
+
 	return &Config{
 		Server: ServerConfig{
 			Port:         getEnv("API_PORT", "8080"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			Mode:         getEnv("GIN_MODE", "debug"),
+			PublicURL:    getEnv("PUBLIC_URL", appURL),
 		},
 		Database: dbConfig,
 		Telegram: TelegramConfig{
-			BotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
-			AppURL:   getEnv("TELEGRAM_MINI_APP_URL", ""),
+			BotToken:              getEnv("TELEGRAM_BOT_TOKEN", ""),
+			AppURL:                appURL,
+			MiniAppLink:           strings.TrimSuffix(getEnv("TELEGRAM_MINI_APP_LINK", ""), "/"),
+			Mode:                  telegramMode,
+			WebhookURL:            getEnv("TELEGRAM_WEBHOOK_URL", ""),
+			WebhookSecret:         webhookSecret,
+			WebhookMaxConnections: getIntEnv("TELEGRAM_WEBHOOK_MAX_CONNECTIONS", 40),
 		},
 		JWT: JWTConfig{
 			Secret:          jwtSecret,
@@ -117,6 +158,21 @@ func loadDatabaseConfig() DatabaseConfig {
 	return config
 }
 
+// validWebhookSecret checks the character set Telegram allows for secret_token
+func validWebhookSecret(secret string) bool {
+	if len(secret) > 256 {
+		return false
+	}
+	for _, r := range secret {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func (c *DatabaseConfig) ConnectionString() string {
 	return "postgres://" + c.User + ":" + c.Password + "@" + c.Host + ":" + c.Port + "/" + c.DBName + "?sslmode=" + c.SSLMode
 }
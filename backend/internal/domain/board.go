@@ -51,6 +51,19 @@ type BoardSettings struct {
 
 	// Habit tracker specific
 	TrackingPeriod string `json:"tracking_period,omitempty"` // "daily", "weekly"
+
+	// Relative reminders of items that do not set their own
+	ReminderOffsets []ReminderOffset `json:"reminder_offsets,omitempty"`
+}
+
+// ParseSettings decodes the raw board settings. Empty settings yield a zero value.
+func (b *Board) ParseSettings() (BoardSettings, error) {
+	var settings BoardSettings
+	if len(b.Settings) == 0 {
+		return settings, nil
+	}
+	err := json.Unmarshal(b.Settings, &settings)
+	return settings, err
 }
 
 type KanbanColumn struct {
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurException overrides a single occurrence of a recurring item:
+// the occurrence starting at OriginalStart is either skipped or moved to Start
+type RecurException struct {
+	OriginalStart time.Time  `json:"original_start" binding:"required"`
+	Skip          bool       `json:"skip,omitempty"`
+	Start         *time.Time `json:"start,omitempty"`
+}
+
+// CalendarOccurrence is a concrete instance of an item on the calendar
+type CalendarOccurrence struct {
+	ItemID        uuid.UUID  `json:"item_id"`
+	BoardID       uuid.UUID  `json:"board_id"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content,omitempty"`
+	Status        ItemStatus `json:"status"`
+	Start         time.Time  `json:"start"`
+	Date          string     `json:"date"` // YYYY-MM-DD in the user's timezone
+	AllDay        bool       `json:"all_day"`
+	Recurring     bool       `json:"recurring"`
+	OriginalStart *time.Time `json:"original_start,omitempty"` // Set for occurrences of recurring items
+	Moved         bool       `json:"moved,omitempty"`
+	Location      string     `json:"location,omitempty"`
+	EventColor    string     `json:"event_color,omitempty"`
+}
+
+// CalendarRange is the response of the calendar range endpoint
+type CalendarRange struct {
+	From     string               `json:"from"`
+	To       string               `json:"to"`
+	Timezone string               `json:"timezone"`
+	Events   []CalendarOccurrence `json:"events"`
+}
+
+// CalendarFeed is a secret .ics subscription URL for a calendar board.
+// Only a hash of the token is stored; the token is returned once on creation.
+type CalendarFeed struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         int64      `json:"user_id"`
+	BoardID        uuid.UUID  `json:"board_id"`
+	TokenHash      string     `json:"-"`
+	IncludeTasks   bool       `json:"include_tasks"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Set only in the creation response
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+type CreateCalendarFeedRequest struct {
+	BoardID      uuid.UUID `json:"board_id" binding:"required"`
+	IncludeTasks bool      `json:"include_tasks"` // Also export dated tasks from other boards as VTODOs
+}
+
+// CalendarImportResult summarizes an .ics import
+type CalendarImportResult struct {
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Skipped int                   `json:"skipped"`
+	Errors  []CalendarImportError `json:"errors"`
+}
+
+// CalendarImportError describes an event that could not be imported as-is
+type CalendarImportError struct {
+	UID     string `json:"uid,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error"`
+}
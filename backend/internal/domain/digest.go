@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest is the agenda of a user's day, sent as the morning digest
+type Digest struct {
+	Date    time.Time            // Start of the day in the user's timezone
+	Boards  []DigestBoard        // Open items due today, grouped by board
+	Overdue []Item               // Open items due before today
+	Habits  []Item               // Daily habits not checked today
+	Events  []CalendarOccurrence // Today's calendar events
+}
+
+// DigestBoard lists the items of one board due today
+type DigestBoard struct {
+	BoardID uuid.UUID
+	Name    string
+	Items   []Item
+}
+
+// IsEmpty reports whether there is nothing on the agenda
+func (d *Digest) IsEmpty() bool {
+	return len(d.Boards) == 0 && len(d.Overdue) == 0 && len(d.Habits) == 0 && len(d.Events) == 0
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat identifies account export documents
+const ExportFormat = "telegram-task-manager"
+
+// ExportVersion is the version of the export document. It changes when a field
+// is removed or changes its meaning; adding fields keeps the version.
+const ExportVersion = 1
+
+// ExportDocument is a user's account as exported by GET /api/export and read
+// by the import. Rows refer to each other by their original IDs and are listed
+// parents first: folders, boards, items (parent items before their children),
+// then reminders and habit completions.
+type ExportDocument struct {
+	Format           string            `json:"format"`
+	Version          int               `json:"version"`
+	ExportedAt       time.Time         `json:"exported_at"`
+	User             ExportUser        `json:"user"`
+	Settings         ExportSettings    `json:"settings"`
+	Folders          []Folder          `json:"folders"`
+	Boards           []Board           `json:"boards"`
+	Items            []Item            `json:"items"`
+	Reminders        []Reminder        `json:"reminders"`
+	HabitCompletions []HabitCompletion `json:"habit_completions"`
+}
+
+// ExportUser describes whose account was exported
+type ExportUser struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// ExportSettings are the user's settings in an export document
+type ExportSettings struct {
+	LanguageCode        string      `json:"language_code"`
+	Timezone            string      `json:"timezone"`
+	NotificationEnabled bool        `json:"notification_enabled"`
+	ReminderHours       []int       `json:"reminder_hours"`
+	DueSoonMinutes      int         `json:"due_soon_minutes"`
+	QuietHours          *QuietHours `json:"quiet_hours,omitempty"`
+	DigestTime          *string     `json:"digest_time,omitempty"`
+	WeeklyReport        bool        `json:"weekly_report"`
+	InboxBoardID        *uuid.UUID  `json:"inbox_board_id,omitempty"`
+}
+
+// NewExportUser returns the export description of the user
+func NewExportUser(user *User) ExportUser {
+	return ExportUser{
+		ID:        user.ID,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
+
+// NewExportSettings returns the exported settings of the user
+func NewExportSettings(user *User) ExportSettings {
+	return ExportSettings{
+		LanguageCode:        user.LanguageCode,
+		Timezone:            user.Timezone,
+		NotificationEnabled: user.NotificationEnabled,
+		ReminderHours:       user.ReminderHours,
+		DueSoonMinutes:      user.DueSoonMinutes,
+		QuietHours:          user.QuietHours,
+		DigestTime:          user.DigestTime,
+		WeeklyReport:        user.WeeklyReport,
+		InboxBoardID:        user.InboxBoardID,
+	}
+}
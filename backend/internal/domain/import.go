@@ -0,0 +1,38 @@
+package domain
+
+// ImportMode decides what happens to imported folders and boards whose name
+// matches existing ones
+type ImportMode string
+
+const (
+	ImportModeCopy  ImportMode = "copy"  // Create all folders and boards anew (default)
+	ImportModeMerge ImportMode = "merge" // Add to the existing folder or board of the same name and type
+)
+
+func (m ImportMode) IsValid() bool {
+	switch m {
+	case ImportModeCopy, ImportModeMerge:
+		return true
+	}
+	return false
+}
+
+// ImportOptions control an account import
+type ImportOptions struct {
+	Mode ImportMode
+	// DryRun validates and writes the document, then rolls everything back
+	DryRun bool
+}
+
+// ImportResult summarizes an account import, or what it would do for a dry run
+type ImportResult struct {
+	Mode                    ImportMode `json:"mode"`
+	DryRun                  bool       `json:"dry_run"`
+	FoldersCreated          int        `json:"folders_created"`
+	FoldersMerged           int        `json:"folders_merged"`
+	BoardsCreated           int        `json:"boards_created"`
+	BoardsMerged            int        `json:"boards_merged"`
+	ItemsCreated            int        `json:"items_created"`
+	RemindersCreated        int        `json:"reminders_created"`
+	HabitCompletionsCreated int        `json:"habit_completions_created"`
+}
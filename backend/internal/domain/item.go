@@ -16,6 +16,14 @@ const (
 	ItemStatusArchived   ItemStatus = "archived"
 )
 
+func (s ItemStatus) IsValid() bool {
+	switch s {
+	case ItemStatusPending, ItemStatusInProgress, ItemStatusCompleted, ItemStatusArchived:
+		return true
+	}
+	return false
+}
+
 type Item struct {
 	ID          uuid.UUID       `json:"id"`
 	BoardID     uuid.UUID       `json:"board_id"`
@@ -30,6 +38,9 @@ type Item struct {
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	Children    []Item          `json:"children,omitempty"`
+
+	// NextOccurrence is set when completing a recurring item spawned its next instance
+	NextOccurrence *Item `json:"next_occurrence,omitempty"`
 }
 
 // ItemMetadata contains type-specific metadata
@@ -47,10 +58,16 @@ type ItemMetadata struct {
 	TimeSlot  string `json:"time_slot,omitempty"`
 
 	// Calendar
-	AllDay     bool   `json:"all_day,omitempty"`
-	RecurRule  string `json:"recur_rule,omitempty"` // iCal RRULE format
-	Location   string `json:"location,omitempty"`
-	EventColor string `json:"event_color,omitempty"`
+	AllDay          bool             `json:"all_day,omitempty"`
+	RecurRule       string           `json:"recur_rule,omitempty"` // iCal RRULE format
+	RecurExceptions []RecurException `json:"recur_exceptions,omitempty"`
+	Location        string           `json:"location,omitempty"`
+	EventColor      string           `json:"event_color,omitempty"`
+	ICalUID         string           `json:"ical_uid,omitempty"` // UID of the imported iCalendar event
+
+	// NextOccurrenceID is set once completing a recurring item created its next
+	// occurrence, so completing it again does not create another one
+	NextOccurrenceID string `json:"next_occurrence_id,omitempty"`
 
 	// Habit tracker
 	Frequency    string `json:"frequency,omitempty"` // "daily", "weekly"
@@ -60,6 +77,42 @@ type ItemMetadata struct {
 
 	// Checklist
 	Priority string `json:"priority,omitempty"` // "low", "medium", "high"
+
+	// Relative reminders. When the key is absent (nil) the board's defaults apply;
+	// an empty list turns them off for the item.
+	ReminderOffsets []ReminderOffset `json:"reminder_offsets,omitempty"`
+}
+
+// ParseMetadata decodes the raw item metadata. Empty metadata yields a zero value.
+func (i *Item) ParseMetadata() (ItemMetadata, error) {
+	var meta ItemMetadata
+	if len(i.Metadata) == 0 {
+		return meta, nil
+	}
+	err := json.Unmarshal(i.Metadata, &meta)
+	return meta, err
+}
+
+// MergeMetadata sets the given keys in raw metadata while keeping any other keys
+// (the frontend stores fields that ItemMetadata does not know about).
+// A nil value removes the key.
+func MergeMetadata(raw json.RawMessage, fields map[string]interface{}) (json.RawMessage, error) {
+	values := make(map[string]interface{})
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range fields {
+		if value == nil {
+			delete(values, key)
+			continue
+		}
+		values[key] = value
+	}
+
+	return json.Marshal(values)
 }
 
 type CreateItemRequest struct {
@@ -72,6 +125,11 @@ type CreateItemRequest struct {
 	Metadata json.RawMessage `json:"metadata"`
 }
 
+// QuickAddItemRequest creates an item from free-form text such as "Позвонить маме завтра в 15:00"
+type QuickAddItemRequest struct {
+	Text string `json:"text" binding:"required,min=1,max=1000"`
+}
+
 type UpdateItemRequest struct {
 	Title       *string          `json:"title" binding:"omitempty,min=1,max=500"`
 	Content     *string          `json:"content" binding:"omitempty,max=10000"`
@@ -91,8 +149,9 @@ type ReorderItemsRequest struct {
 }
 
 type MoveItemRequest struct {
-	BoardID  uuid.UUID `json:"board_id" binding:"required"`
-	Position int       `json:"position"`
+	BoardID  uuid.UUID  `json:"board_id" binding:"required"`
+	Position int        `json:"position"`
+	DueDate  *time.Time `json:"due_date"` // Optional, e.g. when dragged to another day
 }
 
 type ItemFilter struct {
@@ -0,0 +1,16 @@
+package domain
+
+// ItemCSVImportResult summarizes a CSV import of board items
+type ItemCSVImportResult struct {
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Skipped int                  `json:"skipped"`
+	Errors  []ItemCSVImportError `json:"errors"`
+}
+
+// ItemCSVImportError describes a row that was skipped
+type ItemCSVImportError struct {
+	Row   int    `json:"row"` // Line of the row in the file, the header being line 1
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind string
+
+const (
+	NotificationReminder   NotificationKind = "reminder"
+	NotificationOverdue    NotificationKind = "overdue"
+	NotificationInactivity NotificationKind = "inactivity"
+	NotificationDueSoon    NotificationKind = "due_soon"
+	NotificationDigest     NotificationKind = "digest"
+	NotificationReport     NotificationKind = "weekly_report"
+)
+
+// Urgent reports whether the notification is delivered during quiet hours
+// (silently) instead of being held back: reminders were scheduled by the user
+// and due-soon notices would be useless after the due date
+func (k NotificationKind) Urgent() bool {
+	return k == NotificationReminder || k == NotificationDueSoon
+}
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusDead    OutboxStatus = "dead" // Gave up after too many attempts or a permanent error
+)
+
+// OutboxEntry is a queued Telegram notification
+type OutboxEntry struct {
+	ID            uuid.UUID        `json:"id"`
+	UserID        int64            `json:"user_id"`
+	Kind          NotificationKind `json:"kind"`
+	ReminderID    *uuid.UUID       `json:"reminder_id,omitempty"`
+	ItemID        *uuid.UUID       `json:"item_id,omitempty"`
+	Payload       json.RawMessage  `json:"payload"` // Telegram sendMessage request
+	Status        OutboxStatus     `json:"status"`
+	Attempts      int              `json:"attempts"`
+	LastError     string           `json:"last_error,omitempty"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	SentAt        *time.Time       `json:"sent_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
@@ -16,11 +16,101 @@ type Reminder struct {
 	SentAt    *time.Time `json:"sent_at,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	Item      *Item      `json:"item,omitempty"`
+
+	// Offset is set on reminders relative to the item's due date. They are
+	// recomputed when the due date changes and removed when the item is completed.
+	Offset *ReminderOffset `json:"offset,omitempty"`
 }
 
+// CreateReminderRequest sets either an absolute RemindAt or an Offset from the item's due date
 type CreateReminderRequest struct {
-	RemindAt time.Time `json:"remind_at" binding:"required"`
-	Message  string    `json:"message" binding:"max=1000"`
+	RemindAt *time.Time      `json:"remind_at"`
+	Offset   *ReminderOffset `json:"offset"`
+	Message  string          `json:"message" binding:"max=1000"`
+}
+
+// MaxReminderOffsets limits the relative reminders of an item or board
+const MaxReminderOffsets = 10
+
+// maxReminderOffsetDays bounds how long before the due date a reminder may fire
+const maxReminderOffsetDays = 365
+
+// ReminderOffset defines a reminder relative to a due date: either Minutes
+// before it ({"minutes": 30}) or at a local time DaysBefore days earlier
+// ({"days_before": 1, "at": "09:00"})
+type ReminderOffset struct {
+	Minutes    int    `json:"minutes,omitempty"`
+	DaysBefore int    `json:"days_before,omitempty"`
+	At         string `json:"at,omitempty"` // "HH:MM" in the user's timezone
+}
+
+// Validate returns ErrInvalidInput unless exactly one of the two forms is used
+func (o ReminderOffset) Validate() error {
+	if o.At == "" {
+		if o.DaysBefore != 0 || o.Minutes <= 0 || o.Minutes > maxReminderOffsetDays*24*60 {
+			return ErrInvalidInput
+		}
+		return nil
+	}
+
+	if o.Minutes != 0 || o.DaysBefore < 0 || o.DaysBefore > maxReminderOffsetDays {
+		return ErrInvalidInput
+	}
+	if _, err := time.Parse("15:04", o.At); err != nil {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// RemindAt returns when the reminder fires for an item due at due. The day and
+// local time of the At form are taken in loc.
+func (o ReminderOffset) RemindAt(due time.Time, loc *time.Location) time.Time {
+	if o.At == "" {
+		return due.Add(-time.Duration(o.Minutes) * time.Minute)
+	}
+
+	at, _ := time.Parse("15:04", o.At)
+	local := due.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day()-o.DaysBefore, at.Hour(), at.Minute(), 0, 0, loc)
+}
+
+// ValidateReminderOffsets checks a list of offsets of an item or board
+func ValidateReminderOffsets(offsets []ReminderOffset) error {
+	if len(offsets) > MaxReminderOffsets {
+		return ErrInvalidInput
+	}
+	for _, offset := range offsets {
+		if err := offset.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SnoozeOption is a preset for postponing a reminder from the chat
+type SnoozeOption string
+
+const (
+	Snooze15Minutes SnoozeOption = "15m"
+	Snooze1Hour     SnoozeOption = "1h"
+	SnoozeTomorrow  SnoozeOption = "tomorrow" // 09:00 the next day in the user's timezone
+)
+
+// SnoozeTomorrowHour is the local hour a reminder snoozed until tomorrow fires at
+const SnoozeTomorrowHour = 9
+
+// Until returns when a reminder snoozed at now should fire again, in loc
+func (o SnoozeOption) Until(now time.Time, loc *time.Location) (time.Time, bool) {
+	local := now.In(loc)
+	switch o {
+	case Snooze15Minutes:
+		return local.Add(15 * time.Minute), true
+	case Snooze1Hour:
+		return local.Add(time.Hour), true
+	case SnoozeTomorrow:
+		return time.Date(local.Year(), local.Month(), local.Day()+1, SnoozeTomorrowHour, 0, 0, 0, loc), true
+	}
+	return time.Time{}, false
 }
 
 type UpdateReminderRequest struct {
@@ -88,3 +178,13 @@ type OverdueTask struct {
 	DueDate   time.Time `json:"due_date"`
 	BoardName string    `json:"board_name"`
 }
+
+// DueSoonTask is an open item due within its owner's due-soon window
+type DueSoonTask struct {
+	UserID    int64     `json:"user_id"`
+	Timezone  string    `json:"timezone"`
+	ItemID    uuid.UUID `json:"item_id"`
+	Title     string    `json:"title"`
+	DueDate   time.Time `json:"due_date"`
+	BoardName string    `json:"board_name"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WeeklyReport summarizes the user's last seven days, sent on Sunday evenings
+type WeeklyReport struct {
+	From time.Time // Start of the first day in the user's timezone
+	To   time.Time // End of the last day (exclusive)
+
+	Days      []CompletionStats
+	Completed int
+	Created   int
+	BestDay   *CompletionStats // Day with the most completions, nil when nothing was completed
+
+	Habits []HabitStreakChange
+
+	Overdue         int // Overdue items now
+	OverdueLastWeek int // Overdue items a week ago
+
+	TopBoards []BoardActivity
+}
+
+// HabitStreakChange compares a habit's streak with the one a week earlier
+type HabitStreakChange struct {
+	ItemID   uuid.UUID
+	Title    string
+	Streak   int
+	Previous int
+}
+
+// BoardActivity counts the item activity on a board
+type BoardActivity struct {
+	BoardID uuid.UUID
+	Name    string
+	Count   int
+}
+
+// IsEmpty reports whether nothing happened during the week
+func (r *WeeklyReport) IsEmpty() bool {
+	return r.Completed == 0 && r.Created == 0 && len(r.Habits) == 0 &&
+		r.Overdue == 0 && r.OverdueLastWeek == 0 && len(r.TopBoards) == 0
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Matched words in the highlights read from the database are wrapped in these
+// private use characters, which cannot clash with markup in the text
+const (
+	SearchMatchStart = "\uE000"
+	SearchMatchStop  = "\uE001"
+)
+
+// SearchQuery is a full-text search over the user's items
+type SearchQuery struct {
+	Text       string       // Words to look for, in web search syntax ("quoted phrase", -excluded, or)
+	BoardTypes []BoardType  // Only items of boards of these types, any when empty
+	Statuses   []ItemStatus // Only items with these statuses, any when empty
+	Limit      int
+	Offset     int
+}
+
+// SearchHit is an item matching a search, with its board and folder
+type SearchHit struct {
+	Item       Item      `json:"item"`
+	BoardName  string    `json:"board_name"`
+	BoardType  BoardType `json:"board_type"`
+	FolderID   uuid.UUID `json:"folder_id"`
+	FolderName string    `json:"folder_name"`
+	Rank       float64   `json:"rank"`
+
+	// TitleHighlight and Snippet are HTML-escaped, with the matched words in <mark> tags.
+	// The snippet is an excerpt of the content, empty when only the title matched.
+	TitleHighlight string `json:"title_highlight"`
+	Snippet        string `json:"snippet"`
+}
+
+// SearchResult is a page of search hits, best matches first
+type SearchResult struct {
+	Hits   []SearchHit `json:"hits"`
+	Total  int         `json:"total"` // Matches across all pages
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
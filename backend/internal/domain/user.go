@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type User struct {
@@ -16,17 +18,138 @@ type User struct {
 	Timezone            string        `json:"timezone"` // IANA timezone (e.g., "Europe/Moscow")
 	NotificationEnabled bool          `json:"notification_enabled"`
 	ReminderHours       []int         `json:"reminder_hours"`
+	DueSoonMinutes      int           `json:"due_soon_minutes"` // Window of due-soon notifications, 0 turns them off
+	QuietHours          *QuietHours   `json:"quiet_hours,omitempty"`
+	DigestTime          *string       `json:"digest_time,omitempty"` // Local "HH:MM" of the daily digest, nil when off
+	WeeklyReport        bool          `json:"weekly_report"`
+	InboxBoardID        *uuid.UUID    `json:"inbox_board_id,omitempty"`
 	Settings            *UserSettings `json:"settings,omitempty"`
 	LastActiveAt        *time.Time    `json:"last_active_at,omitempty"`
 	CreatedAt           time.Time     `json:"created_at"`
 	UpdatedAt           time.Time     `json:"updated_at"`
 }
 
+// Location returns the user's IANA timezone, falling back to UTC when it is unset or unknown
+func (u *User) Location() *time.Location {
+	return LoadLocation(u.Timezone)
+}
+
+// LoadLocation returns the IANA timezone, falling back to UTC when it is empty or unknown
+func LoadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 type UserSettings struct {
 	NotificationEnabled bool   `json:"notification_enabled"`
 	ReminderHours       []int  `json:"reminder_hours"`
 	LanguageCode        string `json:"language_code"`
 	Timezone            string `json:"timezone"`
+
+	// InboxBoardID is the board for items added from the bot.
+	// Omit to keep the current value; the nil UUID clears it.
+	InboxBoardID *uuid.UUID `json:"inbox_board_id,omitempty"`
+
+	// DueSoonMinutes is how long before the due date items are announced,
+	// 0 turns the notification off. Omit to keep the current value.
+	DueSoonMinutes *int `json:"due_soon_minutes,omitempty"`
+
+	// QuietHours holds back notifications at night. Omit to keep the current
+	// value; an empty start and end turn quiet hours off.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+
+	// DigestTime is the local time ("HH:MM") of the daily digest. Omit to keep
+	// the current value; an empty string turns the digest off.
+	DigestTime *string `json:"digest_time,omitempty"`
+
+	// WeeklyReport opts in to the productivity report on Sunday evenings.
+	// Omit to keep the current value.
+	WeeklyReport *bool `json:"weekly_report,omitempty"`
+}
+
+// ValidateClock returns ErrInvalidInput unless s is a time of day in "HH:MM" format
+func ValidateClock(s string) error {
+	if _, err := time.Parse("15:04", s); err != nil || len(s) != 5 {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// MaxDueSoonMinutes bounds the due-soon window to one week
+const MaxDueSoonMinutes = 7 * 24 * 60
+
+// QuietHoursMode decides what happens to non-urgent notifications during quiet hours
+type QuietHoursMode string
+
+const (
+	QuietHoursHold   QuietHoursMode = "hold"   // Deliver when quiet hours end (default)
+	QuietHoursSilent QuietHoursMode = "silent" // Deliver right away without sound
+)
+
+// QuietHours is a daily do-not-disturb window in the user's timezone, which may
+// span midnight (e.g. 23:00-08:00). Urgent notifications are always delivered on
+// time, silently while the window lasts.
+type QuietHours struct {
+	Start string         `json:"start"` // "HH:MM"
+	End   string         `json:"end"`   // "HH:MM"
+	Mode  QuietHoursMode `json:"mode,omitempty"`
+}
+
+// IsOff reports whether the settings turn quiet hours off
+func (q *QuietHours) IsOff() bool {
+	return q.Start == "" && q.End == ""
+}
+
+// Validate returns ErrInvalidInput for malformed or empty windows and unknown modes
+func (q *QuietHours) Validate() error {
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return ErrInvalidInput
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil || start.Equal(end) {
+		return ErrInvalidInput
+	}
+
+	switch q.Mode {
+	case "", QuietHoursHold, QuietHoursSilent:
+		return nil
+	}
+	return ErrInvalidInput
+}
+
+// Until reports whether t falls into quiet hours and, if so, when they end
+func (q *QuietHours) Until(t time.Time, loc *time.Location) (time.Time, bool) {
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	endsToday := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	switch {
+	case from < to && now >= from && now < to:
+		return endsToday, true
+	case from > to && now >= from:
+		return endsToday.AddDate(0, 0, 1), true
+	case from > to && now < to:
+		return endsToday, true
+	}
+	return time.Time{}, false
 }
 
 type TelegramUser struct {
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -133,6 +134,11 @@ func (h *AuthHandler) UpdateSettings(c *gin.Context) {
 	}
 
 	if err := h.authService.UpdateUserSettings(c.Request.Context(), userID, &req); err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update settings"})
 		return
 	}
@@ -147,6 +147,8 @@ func (h *BoardHandler) CreateBoard(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
 		case domain.ErrInvalidBoardType:
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board type"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reminder offsets"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create board"})
 		}
@@ -197,6 +199,8 @@ func (h *BoardHandler) UpdateBoard(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
 		case domain.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reminder offsets"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update board"})
 		}
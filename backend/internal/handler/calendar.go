@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetBoardCalendar handles GET /api/boards/:boardId/calendar
+// @Summary Get calendar occurrences
+// @Description Returns board items expanded into occurrences (including recurring events) in the user's timezone
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date (YYYY-MM-DD), inclusive"
+// @Success 200 {object} domain.CalendarRange
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/boards/{boardId}/calendar [get]
+func (h *CalendarHandler) GetBoardCalendar(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, expected YYYY-MM-DD"})
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, expected YYYY-MM-DD"})
+		return
+	}
+
+	calendar, err := h.calendarService.GetBoardCalendar(c.Request.Context(), userID, boardID, from, to)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date range"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get calendar"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
+
+// SetException handles POST /api/items/:id/exceptions
+// @Summary Skip or move an occurrence
+// @Description Skips or moves a single occurrence of a recurring item
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param request body domain.RecurException true "Occurrence exception"
+// @Success 200 {object} domain.Item
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/items/{id}/exceptions [post]
+func (h *CalendarHandler) SetException(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	var req domain.RecurException
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	item, err := h.calendarService.SetException(c.Request.Context(), userID, itemID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "item is not recurring or exception has neither skip nor start"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set exception"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// RemoveException handles DELETE /api/items/:id/exceptions
+// @Summary Restore an occurrence
+// @Description Removes the exception of a single occurrence of a recurring item
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Item ID"
+// @Param original_start query string true "Original occurrence start (RFC 3339)"
+// @Success 200 {object} domain.Item
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/items/{id}/exceptions [delete]
+func (h *CalendarHandler) RemoveException(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	originalStart, err := time.Parse(time.RFC3339, c.Query("original_start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid original_start, expected RFC 3339"})
+		return
+	}
+
+	item, err := h.calendarService.RemoveException(c.Request.Context(), userID, itemID, originalStart)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "exception not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "item is not recurring"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove exception"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// ImportCalendar handles POST /api/boards/:boardId/calendar/import
+// @Summary Import an .ics file
+// @Description Creates items from the events of an iCalendar file. Events are matched by UID, so re-importing updates existing items.
+// @Tags calendar
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Param file formData file true "iCalendar file (.ics)"
+// @Success 200 {object} domain.CalendarImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/boards/{boardId}/calendar/import [post]
+func (h *CalendarHandler) ImportCalendar(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxCalendarImportSize+64*1024)
+
+	// Accept both a multipart upload and a raw text/calendar body
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+
+		if fileHeader.Size > service.MaxCalendarImportSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.calendarService.ImportICS(c.Request.Context(), userID, boardID, body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		case err == domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case err == domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case err == domain.ErrInvalidBoardType:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "events can only be imported into calendar boards"})
+		case err == domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid iCalendar file or too many events"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import calendar"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+type CalendarFeedHandler struct {
+	feedService *service.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(feedService *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetFeed handles GET /ical/:token.ics
+// @Summary iCalendar subscription feed
+// @Description Public feed for calendar apps; the secret token in the URL is the only credential
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally with .ics suffix"
+// @Success 200 {string} string "iCalendar document"
+// @Failure 404 {object} map[string]string
+// @Router /ical/{token}.ics [get]
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	data, err := h.feedService.RenderFeed(c.Request.Context(), token)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render feed"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// ListFeeds handles GET /api/auth/settings/ical-feeds
+// @Summary List calendar feeds
+// @Description Returns the user's iCalendar feeds (tokens are not included)
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.CalendarFeed
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/settings/ical-feeds [get]
+func (h *CalendarFeedHandler) ListFeeds(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	feeds, err := h.feedService.GetUserFeeds(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get feeds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feeds)
+}
+
+// CreateFeed handles POST /api/auth/settings/ical-feeds
+// @Summary Create a calendar feed
+// @Description Issues a secret .ics subscription URL for a calendar board. The token is shown only once.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.CreateCalendarFeedRequest true "Feed data"
+// @Success 201 {object} domain.CalendarFeed
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/auth/settings/ical-feeds [post]
+func (h *CalendarFeedHandler) CreateFeed(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.CreateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	feed, err := h.feedService.CreateFeed(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidBoardType:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "feeds are only available for calendar boards"})
+		case domain.ErrConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": "too many calendar feeds"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create feed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, feed)
+}
+
+// DeleteFeed handles DELETE /api/auth/settings/ical-feeds/:feedId
+// @Summary Revoke a calendar feed
+// @Description Deletes a feed so its URL stops working
+// @Tags calendar
+// @Security BearerAuth
+// @Param feedId path string true "Feed ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/auth/settings/ical-feeds/{feedId} [delete]
+func (h *CalendarFeedHandler) DeleteFeed(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	feedID, err := uuid.Parse(c.Param("feedId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feed ID"})
+		return
+	}
+
+	if err := h.feedService.DeleteFeed(c.Request.Context(), userID, feedID); err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete feed"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
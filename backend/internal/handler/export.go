@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+// exportWriteTimeout replaces the server's write timeout for exports, which
+// may take longer to stream for large accounts
+const exportWriteTimeout = 5 * time.Minute
+
+type ExportHandler struct {
+	exportService *service.ExportService
+	logger        *slog.Logger
+}
+
+func NewExportHandler(exportService *service.ExportService, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// Export handles GET /api/export
+// @Summary Export account
+// @Description Streams all of the user's folders, boards, items, reminders, habit completions and settings as a versioned JSON document, the format read by the import. A document cut off mid-stream is invalid JSON.
+// @Tags export
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.ExportDocument
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/export [get]
+func (h *ExportHandler) Export(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		h.logger.Warn("failed to extend export write deadline", "user_id", userID, "error", err)
+	}
+
+	filename := fmt.Sprintf("task-manager-export-%s.json", time.Now().UTC().Format("2006-01-02"))
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := h.exportService.Export(c.Request.Context(), userID, c.Writer); err != nil {
+		// Once streaming started the status can't change; the client sees
+		// a truncated document
+		if c.Writer.Written() {
+			h.logger.Error("export failed mid-stream", "user_id", userID, "error", err)
+			return
+		}
+
+		h.logger.Error("export failed", "user_id", userID, "error", err)
+		c.Header("Content-Disposition", "")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export account"})
+	}
+}
+
+// ExportBoardMarkdown handles GET /api/boards/:boardId/export/markdown
+// @Summary Export notes board as Markdown
+// @Description Returns a zip with a Markdown file per note. Each file has YAML front matter with the title, status, color, labels and dates; child items follow the content as nested bullet lists.
+// @Tags export
+// @Produce application/zip
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Success 200 {file} file "Zip of Markdown files"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/boards/{boardId}/export/markdown [get]
+func (h *ExportHandler) ExportBoardMarkdown(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	archive, err := h.exportService.BoardMarkdown(c.Request.Context(), userID, boardID)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidBoardType:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "only notes boards can be exported as Markdown"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export board"})
+		}
+		return
+	}
+
+	h.writeMarkdownArchive(c, userID, archive)
+}
+
+// ExportFolderMarkdown handles GET /api/folders/:folderId/export/markdown
+// @Summary Export folder notes as Markdown
+// @Description Returns a zip with a directory per notes board of the folder, holding a Markdown file per note as in the board export. Boards of other types are left out.
+// @Tags export
+// @Produce application/zip
+// @Security BearerAuth
+// @Param folderId path string true "Folder ID"
+// @Success 200 {file} file "Zip of Markdown files"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/folders/{folderId}/export/markdown [get]
+func (h *ExportHandler) ExportFolderMarkdown(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder ID"})
+		return
+	}
+
+	archive, err := h.exportService.FolderMarkdown(c.Request.Context(), userID, folderID)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "folder not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidBoardType:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the folder has no notes boards"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export folder"})
+		}
+		return
+	}
+
+	h.writeMarkdownArchive(c, userID, archive)
+}
+
+func (h *ExportHandler) writeMarkdownArchive(c *gin.Context, userID int64, archive *service.MarkdownArchive) {
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", attachment(archive.Name+".zip"))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := archive.Write(c.Writer); err != nil {
+		// The notes are loaded already, so this is the connection failing
+		h.logger.Error("markdown export failed", "user_id", userID, "error", err)
+	}
+}
+
+// attachment returns a Content-Disposition value for downloading a file.
+// Path separators in filename are replaced; non-ASCII names are encoded.
+func attachment(filename string) string {
+	filename = strings.NewReplacer("/", "_", `\`, "_").Replace(filename)
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// Import handles POST /api/import
+// @Summary Import account
+// @Description Recreates the folders, boards, items, reminders and habit completions of an export document under new IDs, in one transaction. Settings are not imported. With a source, the export of another app is converted first: a Trello board JSON becomes a kanban board, a Todoist JSON, CSV or backup zip becomes a checklist board per project.
+// @Tags export
+// @Accept json,multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param source query string false "App the file was exported from, this app when empty" Enums(trello, todoist)
+// @Param mode query string false "copy (default) creates all folders and boards anew; merge adds to existing folders and boards of the same name" Enums(copy, merge)
+// @Param dry_run query bool false "Validate and report what would be created without saving anything"
+// @Param file formData file false "Export file, unless sent as the request body"
+// @Success 200 {object} domain.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/import [post]
+func (h *ImportHandler) Import(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	source := c.Query("source")
+	if source != "" && !h.importService.HasSource(source) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown import source"})
+		return
+	}
+
+	opts := domain.ImportOptions{Mode: domain.ImportMode(c.Query("mode"))}
+	if opts.Mode != "" && !opts.Mode.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import mode"})
+		return
+	}
+	if s := c.Query("dry_run"); s != "" {
+		if opts.DryRun, err = strconv.ParseBool(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+			return
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxImportSize+64*1024)
+
+	// Accept both a multipart upload and a raw body
+	var body io.Reader = c.Request.Body
+	var filename string
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+
+		if fileHeader.Size > service.MaxImportSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		defer file.Close()
+		body = file
+		filename = fileHeader.Filename
+	}
+
+	var result *domain.ImportResult
+	if source == "" {
+		result, err = h.importService.Import(c.Request.Context(), userID, body, opts)
+	} else {
+		result, err = h.importService.ImportFrom(c.Request.Context(), userID, source, body, filename, opts)
+	}
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		var appErr *domain.AppError
+		switch {
+		case errors.As(err, &maxErr):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		case errors.As(err, &appErr):
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message})
+		case err == domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import request"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import account"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
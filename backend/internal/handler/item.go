@@ -187,6 +187,59 @@ func (h *ItemHandler) CreateItem(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
 		case domain.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reminder offsets"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create item"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// QuickAddItem handles POST /api/boards/:boardId/items/quick
+// @Summary Quick add item
+// @Description Creates an item from free-form text. Date phrases like "завтра в 15:00", "next monday 9am" or "каждый вторник" set the due date and recurrence.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Param request body domain.QuickAddItemRequest true "Item text"
+// @Success 201 {object} domain.Item
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/boards/{boardId}/items/quick [post]
+func (h *ItemHandler) QuickAddItem(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	var req domain.QuickAddItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.QuickAddItem(c.Request.Context(), userID, boardID, req.Text)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create item"})
 		}
@@ -237,6 +290,8 @@ func (h *ItemHandler) UpdateItem(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
 		case domain.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reminder offsets"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update item"})
 		}
@@ -339,7 +394,7 @@ func (h *ItemHandler) CompleteItem(c *gin.Context) {
 
 // SetReminder handles POST /api/items/:id/reminder
 // @Summary Set reminder
-// @Description Creates a reminder for an item
+// @Description Creates a reminder for an item at remind_at, or relative to its due date with offset ({"minutes": 30} or {"days_before": 1, "at": "09:00"}). Relative reminders follow due date changes and are removed when the item is completed.
 // @Tags items
 // @Accept json
 // @Produce json
@@ -378,6 +433,8 @@ func (h *ItemHandler) SetReminder(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
 		case domain.ErrForbidden:
 			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		case domain.ErrInvalidInput:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "set either a future remind_at or a valid offset; offsets need a due date"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set reminder"})
 		}
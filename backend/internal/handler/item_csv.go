@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+// ExportItemsCSV handles GET /api/boards/:boardId/export.csv
+// @Summary Export board items as CSV
+// @Description Returns all items of the board, nested ones right after their parent, with the columns id, parent_id, title, content, status, due_date and position followed by the metadata keys of the board type. Due dates are in the user's timezone.
+// @Tags items
+// @Produce text/csv
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/boards/{boardId}/export.csv [get]
+func (h *ItemHandler) ExportItemsCSV(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	board, data, err := h.itemService.ExportItemsCSV(c.Request.Context(), userID, boardID)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export items"})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", attachment(board.Name+".csv"))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// ImportItemsCSV handles POST /api/boards/:boardId/import.csv
+// @Summary Import board items from CSV
+// @Description Creates and updates items from a CSV file in the export format. Rows with an id update that item, rows without one create an item, and columns left out of the file keep their values. Invalid rows are skipped and reported with their line number.
+// @Tags items
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param boardId path string true "Board ID"
+// @Param file formData file false "CSV file, unless sent as the request body"
+// @Success 200 {object} domain.ItemCSVImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/boards/{boardId}/import.csv [post]
+func (h *ItemHandler) ImportItemsCSV(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	boardID, err := uuid.Parse(c.Param("boardId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid board ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxItemCSVImportSize+64*1024)
+
+	// Accept both a multipart upload and a raw text/csv body
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+
+		if fileHeader.Size > service.MaxItemCSVImportSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.itemService.ImportItemsCSV(c.Request.Context(), userID, boardID, body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		var appErr *domain.AppError
+		switch {
+		case errors.As(err, &maxErr):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		case errors.As(err, &appErr):
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message})
+		case err == domain.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "board not found"})
+		case err == domain.ErrForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import items"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
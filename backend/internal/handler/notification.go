@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListFailed handles GET /api/notifications/failed
+// @Summary List failed notifications
+// @Description Returns the user's notifications that failed at least once and are awaiting a retry (status "pending") or were given up on (status "dead"), newest first
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.OutboxEntry
+// @Failure 401 {object} map[string]string
+// @Router /api/notifications/failed [get]
+func (h *NotificationHandler) ListFailed(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	entries, err := h.notificationService.GetFailedNotifications(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search handles GET /api/search
+// @Summary Search items
+// @Description Full-text search over the titles and content of all of the user's items, in Russian and English, best matches first. The query supports "quoted phrases", -excluded words and or. Highlights are HTML-escaped with the matched words in <mark> tags.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param type query []string false "Board types to search in, repeated or comma-separated" collectionFormat(multi)
+// @Param status query []string false "Item statuses to include, repeated or comma-separated" collectionFormat(multi)
+// @Param limit query int false "Hits per page (default 20, at most 100)"
+// @Param offset query int false "Hits to skip"
+// @Success 200 {object} domain.SearchResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	query := domain.SearchQuery{Text: c.Query("q")}
+	for _, t := range queryList(c, "type") {
+		query.BoardTypes = append(query.BoardTypes, domain.BoardType(t))
+	}
+	for _, status := range queryList(c, "status") {
+		query.Statuses = append(query.Statuses, domain.ItemStatus(status))
+	}
+
+	if s := c.Query("limit"); s != "" {
+		if query.Limit, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	if s := c.Query("offset"); s != "" {
+		if query.Offset, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return
+		}
+	}
+
+	result, err := h.searchService.Search(c.Request.Context(), userID, query)
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			c.JSON(appErr.Code, gin.H{"error": appErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// queryList returns the values of a query parameter given repeatedly
+// (?type=a&type=b) or comma-separated (?type=a,b)
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
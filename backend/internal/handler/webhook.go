@@ -1,91 +1,93 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/service"
+	"github.com/telegram-task-manager/backend/pkg/datefmt"
+	"github.com/telegram-task-manager/backend/pkg/i18n"
 	"github.com/telegram-task-manager/backend/pkg/telegram"
 )
 
 type WebhookHandler struct {
-	bot    *telegram.Bot
-	appURL string
-	logger *slog.Logger
+	bot         *telegram.Bot
+	itemService *service.ItemService
+	botService  *service.BotService
+	appURL      string
+	secretToken string
+	logger      *slog.Logger
 }
 
 func NewWebhookHandler(
 	bot *telegram.Bot,
+	itemService *service.ItemService,
+	botService *service.BotService,
 	appURL string,
+	secretToken string,
 	logger *slog.Logger,
 ) *WebhookHandler {
 	return &WebhookHandler{
-		bot:    bot,
-		appURL: appURL,
-		logger: logger,
+		bot:         bot,
+		itemService: itemService,
+		botService:  botService,
+		appURL:      appURL,
+		secretToken: secretToken,
+		logger:      logger,
 	}
 }
 
-// TelegramUpdate represents incoming Telegram webhook update
-type TelegramUpdate struct {
-	UpdateID int64            `json:"update_id"`
-	Message  *TelegramMessage `json:"message,omitempty"`
-}
-
-// TelegramMessage represents a Telegram message
-type TelegramMessage struct {
-	MessageID int64         `json:"message_id"`
-	From      *TelegramFrom `json:"from,omitempty"`
-	Chat      *TelegramChat `json:"chat"`
-	Text      string        `json:"text,omitempty"`
-	Date      int64         `json:"date"`
-}
-
-// TelegramFrom represents sender info
-type TelegramFrom struct {
-	ID           int64  `json:"id"`
-	IsBot        bool   `json:"is_bot"`
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name,omitempty"`
-	Username     string `json:"username,omitempty"`
-	LanguageCode string `json:"language_code,omitempty"`
-}
-
-// TelegramChat represents chat info
-type TelegramChat struct {
-	ID        int64  `json:"id"`
-	Type      string `json:"type"`
-	FirstName string `json:"first_name,omitempty"`
-	LastName  string `json:"last_name,omitempty"`
-	Username  string `json:"username,omitempty"`
-}
-
 // HandleWebhook handles POST /api/telegram/webhook
 func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
-	var update TelegramUpdate
+	// Only Telegram knows the secret token passed to setWebhook
+	if h.secretToken != "" && !telegram.VerifySecretToken(c.GetHeader(telegram.SecretTokenHeader), h.secretToken) {
+		h.logger.Warn("rejected webhook request with invalid secret token", "ip", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var update telegram.Update
 	if err := c.ShouldBindJSON(&update); err != nil {
 		h.logger.Error("failed to parse webhook update", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
 		return
 	}
 
+	h.HandleUpdate(c.Request.Context(), &update)
+
+	// Always return 200 to Telegram
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// HandleUpdate dispatches an update received by webhook or long polling
+func (h *WebhookHandler) HandleUpdate(ctx context.Context, update *telegram.Update) {
 	// Log incoming update
 	updateBytes, _ := json.Marshal(update)
-	h.logger.Info("received telegram webhook", "update", string(updateBytes))
+	h.logger.Info("received telegram update", "update", string(updateBytes))
 
 	// Process message
 	if update.Message != nil {
-		h.handleMessage(c, update.Message)
+		h.handleMessage(ctx, update.Message)
 	}
 
-	// Always return 200 to Telegram
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+	// Process inline button press
+	if update.CallbackQuery != nil {
+		h.handleCallbackQuery(ctx, update.CallbackQuery)
+	}
 }
 
 // handleMessage processes incoming message
-func (h *WebhookHandler) handleMessage(c *gin.Context, msg *TelegramMessage) {
+func (h *WebhookHandler) handleMessage(ctx context.Context, msg *telegram.Message) {
 	if msg.Text == "" || msg.From == nil {
 		return
 	}
@@ -93,44 +95,190 @@ func (h *WebhookHandler) handleMessage(c *gin.Context, msg *TelegramMessage) {
 	chatID := msg.Chat.ID
 	username := msg.From.Username
 	firstName := msg.From.FirstName
+	l := h.locale(ctx, msg.From)
 
 	// Handle /start command
 	if msg.Text == "/start" || (len(msg.Text) >= 6 && msg.Text[:6] == "/start") {
-		h.handleStartCommand(c, chatID, username, firstName)
+		h.handleStartCommand(chatID, username, firstName, l)
+		return
+	}
+
+	command, args := parseCommand(msg.Text)
+	userID := msg.From.ID
+
+	switch command {
+	case "/help":
+		h.handleHelpCommand(chatID, l)
+	case "/add":
+		h.handleAddCommand(ctx, chatID, userID, args, l)
+	case "/today":
+		items, err := h.botService.GetTodayItems(ctx, userID)
+		h.sendItemList(chatID, items, err, l, "bot.today.title", "bot.today.empty")
+	case "/overdue":
+		items, err := h.botService.GetOverdueItems(ctx, userID)
+		h.sendItemList(chatID, items, err, l, "bot.overdue.title", "bot.overdue.empty")
+	case "/done":
+		h.handleDoneCommand(ctx, chatID, userID, args, l)
+	}
+}
+
+// locale is the timezone and language of the replies to an update
+type locale struct {
+	loc  *time.Location
+	lang i18n.Lang
+}
+
+// locale returns the settings of the user who sent an update, falling back
+// to the language of their Telegram client
+func (h *WebhookHandler) locale(ctx context.Context, from *telegram.User) locale {
+	loc, lang := h.botService.Locale(ctx, from.ID, from.LanguageCode)
+	return locale{loc: loc, lang: lang}
+}
+
+// t formats the message key in the user's language
+func (l locale) t(key string, args ...any) string {
+	return i18n.T(l.lang, key, args...)
+}
+
+// dateTime renders a date in the user's timezone and language
+func (l locale) dateTime(t time.Time) string {
+	return datefmt.DateTime(t.In(l.loc), time.Now(), l.lang)
+}
+
+// parseCommand splits "/cmd@botname args" into the command and its arguments
+func parseCommand(text string) (command, args string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+
+	command, args, _ = strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+
+	return strings.ToLower(command), strings.TrimSpace(args)
+}
+
+// handleAddCommand creates an item in the user's inbox board
+func (h *WebhookHandler) handleAddCommand(ctx context.Context, chatID, userID int64, text string, l locale) {
+	if text == "" {
+		h.reply(chatID, l.t("bot.add.usage"))
+		return
+	}
+
+	item, board, err := h.botService.AddItem(ctx, userID, text)
+	if err != nil {
+		h.replyError(chatID, "add", err, l)
+		return
+	}
+
+	reply := l.t("bot.add.added", html.EscapeString(board.Name), html.EscapeString(item.Title))
+	if item.DueDate != nil {
+		meta, _ := item.ParseMetadata()
+		if meta.AllDay {
+			reply += "\n📅 " + datefmt.Date(item.DueDate.In(l.loc), time.Now(), l.lang)
+		} else {
+			reply += "\n📅 " + l.dateTime(*item.DueDate)
+		}
+		if meta.RecurRule != "" {
+			reply += " 🔁"
+		}
+	}
+	h.reply(chatID, reply)
+}
+
+// handleDoneCommand completes the n-th item of the last /today or /overdue listing
+func (h *WebhookHandler) handleDoneCommand(ctx context.Context, chatID, userID int64, args string, l locale) {
+	n, err := strconv.Atoi(args)
+	if err != nil {
+		h.reply(chatID, l.t("bot.done.usage"))
+		return
+	}
+
+	item, err := h.botService.CompleteListed(ctx, userID, n)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			h.reply(chatID, l.t("bot.done.no_listing"))
+		case domain.ErrInvalidInput:
+			h.reply(chatID, l.t("bot.done.no_item"))
+		default:
+			h.replyError(chatID, "done", err, l)
+		}
+		return
+	}
+
+	text := l.t("bot.done.completed", html.EscapeString(item.Title))
+	if next := item.NextOccurrence; next != nil && next.DueDate != nil {
+		text += l.t("bot.next", l.dateTime(*next.DueDate))
+	}
+	h.reply(chatID, text)
+}
+
+// sendItemList sends a numbered list that /done refers to, titled with the
+// message key title or the key empty when there are no items
+func (h *WebhookHandler) sendItemList(chatID int64, items []domain.Item, err error, l locale, title, empty string) {
+	if err != nil {
+		h.replyError(chatID, "list", err, l)
 		return
 	}
 
-	// Handle /help command
-	if msg.Text == "/help" {
-		h.handleHelpCommand(chatID)
+	if len(items) == 0 {
+		h.reply(chatID, l.t(empty))
 		return
 	}
+
+	var b strings.Builder
+	b.WriteString(l.t(title) + "\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, html.EscapeString(item.Title))
+		if item.DueDate != nil {
+			b.WriteString(" — " + l.dateTime(*item.DueDate))
+		}
+	}
+	b.WriteString(l.t("bot.list.hint"))
+
+	h.reply(chatID, b.String())
+}
+
+func (h *WebhookHandler) replyError(chatID int64, command string, err error, l locale) {
+	switch err {
+	case domain.ErrNotFound:
+		h.reply(chatID, l.t("bot.error.no_board"))
+	case domain.ErrForbidden:
+		h.reply(chatID, l.t("bot.error.not_found"))
+	case domain.ErrInvalidInput:
+		h.reply(chatID, l.t("bot.error.invalid"))
+	default:
+		h.logger.Error("failed to handle bot command", "chat_id", chatID, "command", command, "error", err)
+		h.reply(chatID, l.t("bot.error.generic"))
+	}
+}
+
+func (h *WebhookHandler) reply(chatID int64, text string) {
+	if _, err := h.bot.SendMessage(chatID, text); err != nil {
+		h.logger.Error("failed to send reply", "chat_id", chatID, "error", err)
+	}
 }
 
 // handleStartCommand sends welcome message with mini app link
-func (h *WebhookHandler) handleStartCommand(c *gin.Context, chatID int64, username, firstName string) {
+func (h *WebhookHandler) handleStartCommand(chatID int64, username, firstName string, l locale) {
 	// Build welcome message
 	displayName := firstName
 	if displayName == "" && username != "" {
 		displayName = "@" + username
 	}
 	if displayName == "" {
-		displayName = "пользователь"
+		displayName = l.t("bot.start.anonymous")
 	}
 
-	welcomeText := fmt.Sprintf(
-		"Привет, %s! 👋\n\n"+
-			"Я помогу тебе организовать задачи и напоминания.\n\n"+
-			"📱 Открой мини-приложение для управления задачами:",
-		displayName,
-	)
+	welcomeText := l.t("bot.start.welcome", html.EscapeString(displayName))
 
 	// Send message with Mini App button
 	keyboard := telegram.InlineKeyboardMarkup{
 		InlineKeyboard: [][]telegram.InlineKeyboardButton{
 			{
 				{
-					Text: "📋 Открыть Task Manager",
+					Text: l.t("bot.start.button"),
 					WebApp: &telegram.WebAppInfo{
 						URL: h.appURL,
 					},
@@ -154,23 +302,120 @@ func (h *WebhookHandler) handleStartCommand(c *gin.Context, chatID int64, userna
 }
 
 // handleHelpCommand sends help message
-func (h *WebhookHandler) handleHelpCommand(chatID int64) {
-	helpText := `📖 <b>Помощь</b>
+func (h *WebhookHandler) handleHelpCommand(chatID int64, l locale) {
+	helpText := l.t("bot.help")
 
-<b>Команды:</b>
-/start - Начать работу и открыть мини-приложение
-/help - Показать это сообщение
+	_, err := h.bot.SendMessage(chatID, helpText)
+	if err != nil {
+		h.logger.Error("failed to send help message", "chat_id", chatID, "error", err)
+	}
+}
+
+// handleCallbackQuery processes the Done / Snooze buttons of reminder messages
+func (h *WebhookHandler) handleCallbackQuery(ctx context.Context, query *telegram.CallbackQuery) {
+	if query.From == nil {
+		return
+	}
 
-<b>Возможности:</b>
-• 📁 Создавай папки для организации
-• 📋 Добавляй доски разных типов (Kanban, чек-листы, заметки и др.)
-• ⏰ Устанавливай напоминания
-• 📊 Отслеживай прогресс
+	l := h.locale(ctx, query.From)
 
-Открой мини-приложение через кнопку меню или команду /start!`
+	parts := strings.Split(query.Data, ":")
+	if len(parts) < 2 {
+		h.answerCallback(query, l.t("callback.unknown"), false)
+		return
+	}
 
-	_, err := h.bot.SendMessage(chatID, helpText)
+	itemID, err := uuid.Parse(parts[1])
 	if err != nil {
-		h.logger.Error("failed to send help message", "chat_id", chatID, "error", err)
+		h.answerCallback(query, l.t("callback.unknown"), false)
+		return
+	}
+
+	userID := query.From.ID
+
+	switch parts[0] {
+	case telegram.CallbackDone:
+		item, err := h.itemService.CompleteItem(ctx, userID, itemID, true)
+		if err != nil {
+			h.answerCallbackError(query, itemID, err, l)
+			return
+		}
+
+		status := l.t("callback.done")
+		if next := item.NextOccurrence; next != nil && next.DueDate != nil {
+			status += l.t("bot.next", l.dateTime(*next.DueDate))
+		}
+
+		h.updateReminderMessage(query, status, telegram.InlineKeyboardMarkup{
+			InlineKeyboard: [][]telegram.InlineKeyboardButton{
+				telegram.OpenTaskRow(h.appURL, itemID.String(), l.lang),
+			},
+		})
+		h.answerCallback(query, l.t("callback.marked"), false)
+
+	case telegram.CallbackSnooze:
+		if len(parts) != 3 {
+			h.answerCallback(query, l.t("callback.unknown"), false)
+			return
+		}
+
+		reminder, err := h.itemService.SnoozeItem(ctx, userID, itemID, domain.SnoozeOption(parts[2]))
+		if err != nil {
+			h.answerCallbackError(query, itemID, err, l)
+			return
+		}
+
+		until := l.dateTime(reminder.RemindAt)
+		h.updateReminderMessage(query, l.t("callback.snoozed", until), telegram.ReminderKeyboard(h.appURL, itemID.String(), l.lang))
+		h.answerCallback(query, l.t("callback.until", until), false)
+
+	default:
+		h.answerCallback(query, l.t("callback.unknown"), false)
+	}
+}
+
+// updateReminderMessage rewrites the reminder with its new state below the original text
+func (h *WebhookHandler) updateReminderMessage(query *telegram.CallbackQuery, status string, keyboard telegram.InlineKeyboardMarkup) {
+	if query.Message == nil {
+		return
+	}
+
+	// Callback messages carry plain text, so the original formatting is lost;
+	// drop the status line of a previous action before appending the new one
+	text, _, _ := strings.Cut(query.Message.Text, "\n\n✅")
+	text, _, _ = strings.Cut(text, "\n\n⏰")
+
+	err := h.bot.EditMessageText(telegram.EditMessageTextRequest{
+		ChatID:      query.Message.Chat.ID,
+		MessageID:   query.Message.MessageID,
+		Text:        html.EscapeString(text) + "\n\n" + status,
+		ParseMode:   "HTML",
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		h.logger.Error("failed to edit reminder message", "chat_id", query.Message.Chat.ID, "error", err)
+	}
+}
+
+func (h *WebhookHandler) answerCallbackError(query *telegram.CallbackQuery, itemID uuid.UUID, err error, l locale) {
+	switch err {
+	case domain.ErrNotFound, domain.ErrForbidden:
+		h.answerCallback(query, l.t("bot.error.not_found"), true)
+	case domain.ErrInvalidInput:
+		h.answerCallback(query, l.t("callback.unknown"), false)
+	default:
+		h.logger.Error("failed to handle reminder action", "item_id", itemID, "data", query.Data, "error", err)
+		h.answerCallback(query, l.t("bot.error.generic"), true)
+	}
+}
+
+func (h *WebhookHandler) answerCallback(query *telegram.CallbackQuery, text string, alert bool) {
+	err := h.bot.AnswerCallbackQuery(telegram.AnswerCallbackQueryRequest{
+		CallbackQueryID: query.ID,
+		Text:            text,
+		ShowAlert:       alert,
+	})
+	if err != nil {
+		h.logger.Error("failed to answer callback query", "error", err)
 	}
 }
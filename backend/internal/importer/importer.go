@@ -0,0 +1,120 @@
+// Package importer converts exports of other apps into the app's own export
+// document (domain.ExportDocument), which the import service then writes to
+// the account like a native export. Each source lives in a sub-package that
+// implements Importer; register it with the Registry passed to the service.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+// ErrInvalidExport is returned, possibly wrapped with details, for files
+// that are not an export of the importer's source
+var ErrInvalidExport = errors.New("invalid export")
+
+// Limits of the item columns, longer texts are cut
+const (
+	MaxTitleLength   = 500
+	MaxContentLength = 10000
+)
+
+// Importer converts the export of another app
+type Importer interface {
+	Convert(data []byte, opts Options) (*domain.ExportDocument, error)
+}
+
+// Options describe the upload and the importing user
+type Options struct {
+	// Filename is the name of the uploaded file, empty for a raw request body
+	Filename string
+	// Location is the user's timezone, used for dates without one
+	Location *time.Location
+	// Now is the time of the import, for relative dates
+	Now time.Time
+	// MaxSize bounds the total uncompressed size of archives in bytes, and
+	// MaxRows the rows converted; zero means no limit
+	MaxSize int64
+	MaxRows int
+}
+
+// CheckRows returns an error once the rows of doc, together with pending
+// rows not added to it yet, exceed MaxRows, so converters can stop early
+func (o Options) CheckRows(doc *domain.ExportDocument, pending int) error {
+	if o.MaxRows <= 0 {
+		return nil
+	}
+	rows := len(doc.Folders) + len(doc.Boards) + len(doc.Items) + len(doc.Reminders) + len(doc.HabitCompletions) + pending
+	if rows > o.MaxRows {
+		return fmt.Errorf("%w: too many rows, at most %d can be imported at once", ErrInvalidExport, o.MaxRows)
+	}
+	return nil
+}
+
+// Registry maps source names, as given in the import request, to importers
+type Registry map[string]Importer
+
+// NewDocument returns an empty export document of the current version
+func NewDocument(now time.Time) *domain.ExportDocument {
+	return &domain.ExportDocument{
+		Format:     domain.ExportFormat,
+		Version:    domain.ExportVersion,
+		ExportedAt: now,
+	}
+}
+
+// AddFolder appends a folder to doc and returns its ID
+func AddFolder(doc *domain.ExportDocument, name string) uuid.UUID {
+	folder := domain.Folder{
+		ID:       uuid.New(),
+		Name:     Truncate(name, 255),
+		Position: len(doc.Folders),
+	}
+	doc.Folders = append(doc.Folders, folder)
+	return folder.ID
+}
+
+// AddBoard appends a board with the given settings to doc and returns its ID
+func AddBoard(doc *domain.ExportDocument, folderID uuid.UUID, name string, boardType domain.BoardType, settings domain.BoardSettings) uuid.UUID {
+	data, _ := json.Marshal(settings)
+	board := domain.Board{
+		ID:       uuid.New(),
+		FolderID: folderID,
+		Name:     Truncate(name, 255),
+		Type:     boardType,
+		Settings: data,
+		Position: len(doc.Boards),
+	}
+	doc.Boards = append(doc.Boards, board)
+	return board.ID
+}
+
+// Metadata encodes item metadata
+func Metadata(meta domain.ItemMetadata) json.RawMessage {
+	data, _ := json.Marshal(meta)
+	return data
+}
+
+// Title returns s cut to the item title length, or fallback when s is blank
+func Title(s, fallback string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		s = fallback
+	}
+	return Truncate(s, MaxTitleLength)
+}
+
+// Truncate cuts s to at most max characters
+func Truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
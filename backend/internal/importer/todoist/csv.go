@@ -0,0 +1,228 @@
+package todoist
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+	"github.com/telegram-task-manager/backend/pkg/naturaldate"
+)
+
+// csvPriorities maps CSV priorities, where 1 is Todoist's "p1"
+var csvPriorities = map[string]string{"1": "high", "2": "medium", "3": "low"}
+
+// convertZip reads a backup, a zip with a CSV file per project
+func (c *converter) convertZip(data []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%w: not a Todoist backup", importer.ErrInvalidExport)
+	}
+
+	files := make([]*zip.File, 0, len(archive.File))
+	for _, file := range archive.File {
+		if strings.EqualFold(path.Ext(file.Name), ".csv") {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w: no projects in the Todoist backup", importer.ErrInvalidExport)
+	}
+	sort.Slice(files, func(x, y int) bool { return files[x].Name < files[y].Name })
+
+	// All files share one budget, the declared sizes of the entries cannot
+	// be trusted
+	budget := c.opts.MaxSize
+	if budget <= 0 {
+		budget = int64(len(data)) * 10
+	}
+	for _, file := range files {
+		f, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", importer.ErrInvalidExport, file.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(f, budget+1))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", importer.ErrInvalidExport, file.Name, err)
+		}
+		if int64(len(content)) > budget {
+			return fmt.Errorf("%w: the backup is too large when uncompressed", importer.ErrInvalidExport)
+		}
+		budget -= int64(len(content))
+
+		content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+		if err := c.convertCSV(content, boardName(file.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertCSV reads the CSV of one project. Rows have a TYPE: "task" rows
+// nest by their INDENT, "section" rows label the tasks after them and
+// "note" rows (comments) are added to the content of the task before them.
+func (c *converter) convertCSV(data []byte, name string) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: not a Todoist CSV export", importer.ErrInvalidExport)
+	}
+	columns := make(map[string]int, len(header))
+	for n, column := range header {
+		columns[strings.ToUpper(strings.TrimSpace(column))] = n
+	}
+	if _, ok := columns["TYPE"]; !ok {
+		return fmt.Errorf("%w: not a Todoist CSV export", importer.ErrInvalidExport)
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return fmt.Errorf("%w: not a Todoist CSV export", importer.ErrInvalidExport)
+	}
+
+	boardID := c.addBoard(name)
+
+	var (
+		section  string
+		last     *task
+		parents  []uuid.UUID // IDs of the last task at each indent
+		pending  []*task
+		position = make(map[uuid.UUID]int)
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", importer.ErrInvalidExport, err)
+		}
+		field := func(column string) string {
+			if n, ok := columns[column]; ok && n < len(record) {
+				return strings.TrimSpace(record[n])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(field("TYPE")) {
+		case "section":
+			section = field("CONTENT")
+			parents = parents[:0]
+		case "note":
+			if last != nil && field("CONTENT") != "" {
+				if last.item.Content != "" {
+					last.item.Content += "\n\n"
+				}
+				last.item.Content += field("CONTENT")
+			}
+		case "task":
+			if err := c.opts.CheckRows(c.doc, len(pending)+1); err != nil {
+				return err
+			}
+			indent, err := strconv.Atoi(field("INDENT"))
+			if err != nil || indent < 1 {
+				indent = 1
+			}
+			// A task cannot be deeper than one below the task before it
+			indent = min(indent, len(parents)+1)
+			parents = parents[:indent-1]
+
+			t := &task{
+				item: domain.Item{
+					ID:      uuid.New(),
+					BoardID: boardID,
+					Content: field("DESCRIPTION"),
+				},
+				priority: csvPriorities[field("PRIORITY")],
+			}
+			t.item.Title, t.labels = splitLabels(field("CONTENT"))
+			if section != "" {
+				t.labels = append(t.labels, section)
+			}
+
+			siblings := uuid.Nil
+			if len(parents) > 0 {
+				parentID := parents[len(parents)-1]
+				t.item.ParentID = &parentID
+				siblings = parentID
+			}
+			t.item.Position = position[siblings]
+			position[siblings]++
+
+			if date := field("DATE"); date != "" {
+				c.setCSVDate(t, date, field("TIMEZONE"))
+			}
+
+			parents = append(parents, t.item.ID)
+			pending = append(pending, t)
+			last = t
+		}
+	}
+
+	for _, t := range pending {
+		c.addTask(t)
+	}
+	return nil
+}
+
+// setCSVDate reads the DATE column, which holds a date as typed in Todoist:
+// an ISO date, or text like "tomorrow 9am" or "every monday"
+func (c *converter) setCSVDate(t *task, date, timezone string) {
+	loc := c.opts.Location
+	if timezone != "" {
+		if tz, err := time.LoadLocation(timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	if due, err := time.ParseInLocation("2006-01-02", date, loc); err == nil {
+		t.item.DueDate = &due
+		t.allDay = true
+		return
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if due, err := time.ParseInLocation(layout, date, loc); err == nil {
+			t.item.DueDate = &due
+			return
+		}
+	}
+
+	result := naturaldate.Parse(date, c.opts.Now.In(loc))
+	if result.Due == nil {
+		return
+	}
+	t.item.DueDate = result.Due
+	t.allDay = !result.HasTime
+	t.rule = result.RecurRule
+}
+
+// splitLabels takes the "@label" words out of a task's content
+func splitLabels(content string) (string, []string) {
+	words := strings.Fields(content)
+	kept := words[:0]
+	var labels []string
+	for _, word := range words {
+		if len(word) > 1 && word[0] == '@' {
+			labels = append(labels, word[1:])
+			continue
+		}
+		kept = append(kept, word)
+	}
+	if labels == nil {
+		return content, nil
+	}
+	return strings.Join(kept, " "), labels
+}
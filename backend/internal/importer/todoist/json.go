@@ -0,0 +1,268 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+)
+
+// id is a Todoist ID, a string in current APIs and a number in older ones
+type id string
+
+func (i *id) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*i = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*i = id(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*i = id(n.String())
+	return nil
+}
+
+// export holds the resources of a Sync API response (projects, sections,
+// items, labels) or of REST API listings (projects, sections, tasks)
+type export struct {
+	Projects []project `json:"projects"`
+	Sections []section `json:"sections"`
+	Items    []apiTask `json:"items"`
+	Tasks    []apiTask `json:"tasks"`
+	Labels   []label   `json:"labels"`
+}
+
+type project struct {
+	ID         id     `json:"id"`
+	Name       string `json:"name"`
+	ChildOrder int    `json:"child_order"`
+	Order      int    `json:"order"`
+	IsDeleted  bool   `json:"is_deleted"`
+}
+
+type section struct {
+	ID   id     `json:"id"`
+	Name string `json:"name"`
+}
+
+type label struct {
+	ID   id     `json:"id"`
+	Name string `json:"name"`
+}
+
+type apiTask struct {
+	ID          id     `json:"id"`
+	ProjectID   id     `json:"project_id"`
+	SectionID   id     `json:"section_id"`
+	ParentID    id     `json:"parent_id"`
+	Content     string `json:"content"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	Labels      []id   `json:"labels"`
+	Due         *due   `json:"due"`
+	ChildOrder  int    `json:"child_order"`
+	Order       int    `json:"order"`
+	Checked     bool   `json:"checked"`
+	IsCompleted bool   `json:"is_completed"`
+	IsDeleted   bool   `json:"is_deleted"`
+	CompletedAt string `json:"completed_at"`
+	AddedAt     string `json:"added_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type due struct {
+	Date        string `json:"date"`
+	Datetime    string `json:"datetime"`
+	Timezone    string `json:"timezone"`
+	String      string `json:"string"`
+	IsRecurring bool   `json:"is_recurring"`
+}
+
+// priorities maps API priorities, where 4 is Todoist's "p1"
+var priorities = map[int]string{4: "high", 3: "medium", 2: "low"}
+
+func (c *converter) convertJSON(data []byte) error {
+	var e export
+	if err := json.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("%w: not a Todoist export", importer.ErrInvalidExport)
+	}
+	tasks := append(e.Items, e.Tasks...)
+	if len(e.Projects) == 0 && len(tasks) == 0 {
+		return fmt.Errorf("%w: not a Todoist export", importer.ErrInvalidExport)
+	}
+
+	sections := make(map[id]string, len(e.Sections))
+	for _, s := range e.Sections {
+		sections[s.ID] = s.Name
+	}
+	labels := make(map[id]string, len(e.Labels))
+	for _, l := range e.Labels {
+		labels[l.ID] = l.Name
+	}
+
+	sort.SliceStable(e.Projects, func(x, y int) bool {
+		return e.Projects[x].ChildOrder+e.Projects[x].Order < e.Projects[y].ChildOrder+e.Projects[y].Order
+	})
+	boards := make(map[id]uuid.UUID, len(e.Projects))
+	deleted := make(map[id]bool)
+	for _, p := range e.Projects {
+		if p.IsDeleted {
+			deleted[p.ID] = true
+			continue
+		}
+		if err := c.opts.CheckRows(c.doc, 1); err != nil {
+			return err
+		}
+		boards[p.ID] = c.addBoard(p.Name)
+	}
+	for n := range tasks {
+		if deleted[tasks[n].ProjectID] {
+			tasks[n].IsDeleted = true
+		}
+	}
+
+	// Tasks of a project missing from the export share one board
+	var orphans uuid.UUID
+	boardOf := func(projectID id) uuid.UUID {
+		if boardID, ok := boards[projectID]; ok {
+			return boardID
+		}
+		if orphans == uuid.Nil {
+			orphans = c.addBoard(FolderName)
+		}
+		return orphans
+	}
+
+	items := make(map[id]uuid.UUID, len(tasks))
+	for _, t := range tasks {
+		if !t.IsDeleted {
+			items[t.ID] = uuid.New()
+		}
+	}
+
+	sort.SliceStable(tasks, func(x, y int) bool {
+		return tasks[x].ChildOrder+tasks[x].Order < tasks[y].ChildOrder+tasks[y].Order
+	})
+
+	type siblings struct {
+		boardID  uuid.UUID
+		parentID uuid.UUID
+	}
+	positions := make(map[siblings]int)
+	for _, t := range tasks {
+		if t.IsDeleted {
+			continue
+		}
+		if err := c.opts.CheckRows(c.doc, 1); err != nil {
+			return err
+		}
+
+		converted := &task{
+			item: domain.Item{
+				ID:        items[t.ID],
+				BoardID:   boardOf(t.ProjectID),
+				Title:     t.Content,
+				Content:   t.Description,
+				CreatedAt: parseTime(t.AddedAt, t.CreatedAt),
+			},
+			priority: priorities[t.Priority],
+		}
+		// A task whose parent was deleted stays on the board
+		if parentID, ok := items[t.ParentID]; ok {
+			converted.item.ParentID = &parentID
+		}
+		key := siblings{converted.item.BoardID, items[t.ParentID]}
+		converted.item.Position = positions[key]
+		positions[key]++
+
+		for _, l := range t.Labels {
+			if name, ok := labels[l]; ok {
+				converted.labels = append(converted.labels, name)
+			} else if l != "" {
+				converted.labels = append(converted.labels, string(l))
+			}
+		}
+		if name := sections[t.SectionID]; name != "" {
+			converted.labels = append(converted.labels, name)
+		}
+
+		if t.Checked || t.IsCompleted {
+			completedAt := parseTime(t.CompletedAt)
+			if completedAt.IsZero() {
+				completedAt = c.opts.Now
+			}
+			converted.item.Status = domain.ItemStatusCompleted
+			converted.item.CompletedAt = &completedAt
+		}
+
+		if t.Due != nil {
+			c.setDue(converted, t.Due)
+		}
+
+		c.addTask(converted)
+	}
+
+	return nil
+}
+
+// setDue reads a due date, which is either a date, a floating date and time
+// in the task's timezone (the user's, if none) or a UTC instant
+func (c *converter) setDue(t *task, d *due) {
+	value := d.Date
+	if d.Datetime != "" {
+		value = d.Datetime
+	}
+
+	loc := c.opts.Location
+	if d.Timezone != "" {
+		if tz, err := time.LoadLocation(d.Timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	var dueDate time.Time
+	var err error
+	switch {
+	case len(value) == len("2006-01-02"):
+		dueDate, err = time.ParseInLocation("2006-01-02", value, c.opts.Location)
+		t.allDay = true
+	case strings.HasSuffix(value, "Z") || strings.LastIndexAny(value, "+-") > len("2006-01-02"):
+		dueDate, err = time.Parse(time.RFC3339, value)
+	default:
+		dueDate, err = time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+	}
+	if err != nil {
+		t.allDay = false
+		return
+	}
+	t.item.DueDate = &dueDate
+
+	if d.IsRecurring {
+		t.rule = c.recurRule(d.String)
+	}
+}
+
+// parseTime returns the first of values that is an RFC 3339 time
+func parseTime(values ...string) time.Time {
+	for _, value := range values {
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
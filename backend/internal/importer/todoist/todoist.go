@@ -0,0 +1,110 @@
+// Package todoist converts Todoist exports into checklist boards, one per
+// project. It reads the JSON of the Sync and REST APIs as well as the CSV
+// backup, either a single project's CSV or the zip of all of them.
+package todoist
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+	"github.com/telegram-task-manager/backend/pkg/naturaldate"
+)
+
+// FolderName is the folder imported boards are put in
+const FolderName = "Todoist"
+
+// Importer implements importer.Importer for Todoist
+type Importer struct{}
+
+func New() *Importer {
+	return &Importer{}
+}
+
+// Convert detects the kind of export from its first bytes
+func (i *Importer) Convert(data []byte, opts importer.Options) (*domain.ExportDocument, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	doc := importer.NewDocument(opts.Now)
+	c := &converter{
+		doc:      doc,
+		folderID: importer.AddFolder(doc, FolderName),
+		opts:     opts,
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return doc, c.convertJSON(trimmed)
+	case bytes.HasPrefix(data, []byte("PK")):
+		return doc, c.convertZip(data)
+	default:
+		return doc, c.convertCSV(data, boardName(opts.Filename))
+	}
+}
+
+// converter adds the converted projects to doc
+type converter struct {
+	doc      *domain.ExportDocument
+	folderID uuid.UUID
+	opts     importer.Options
+}
+
+// task is an item being converted, with the fields kept in its metadata
+type task struct {
+	item     domain.Item
+	priority string
+	labels   []string
+	allDay   bool
+	rule     string
+}
+
+func (c *converter) addBoard(name string) uuid.UUID {
+	return importer.AddBoard(c.doc, c.folderID, importer.Title(name, FolderName), domain.BoardTypeChecklist, domain.BoardSettings{})
+}
+
+func (c *converter) addTask(t *task) {
+	t.item.Title = importer.Title(t.item.Title, "Untitled task")
+	t.item.Content = importer.Truncate(t.item.Content, importer.MaxContentLength)
+	if t.item.Status == "" {
+		t.item.Status = domain.ItemStatusPending
+	}
+	t.item.Metadata = importer.Metadata(domain.ItemMetadata{
+		Priority:  t.priority,
+		Labels:    t.labels,
+		AllDay:    t.allDay,
+		RecurRule: t.rule,
+	})
+	c.doc.Items = append(c.doc.Items, t.item)
+}
+
+// recurRule reads the rule of a recurring due string like "every monday"
+func (c *converter) recurRule(text string) string {
+	return naturaldate.Parse(text, c.opts.Now.In(c.opts.Location)).RecurRule
+}
+
+// idSuffix is the project ID Todoist appends to the names of backup files
+var idSuffix = regexp.MustCompile(`\s*\[\d+\]$`)
+
+// boardName derives a project name from the name of its CSV file
+func boardName(filename string) string {
+	name := filename
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(idSuffix.ReplaceAllString(name, ""))
+	if name == "" {
+		return FolderName
+	}
+	return name
+}
@@ -0,0 +1,382 @@
+package todoist
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+)
+
+// csvFixture is a project backup with nested tasks, a section and notes
+const csvFixture = "\xef\xbb\xbfTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+	"task,Buy groceries @errands,Milk and eggs,1,1,Anna,,2026-10-20,en,\n" +
+	"note,Check the fridge first,,,,Anna,,,,\n" +
+	"task,Milk,,4,2,Anna,,,,\n" +
+	"task,Eggs,,4,3,Anna,,,,\n" +
+	"task,Way too deep,,4,6,Anna,,,,\n" +
+	"task,Bread,,3,2,Anna,,,,\n" +
+	"\n" +
+	"section,Work,,,,,,,,\n" +
+	"task,Write report,,2,2,Anna,,2026-10-21 15:00,en,Europe/Berlin\n" +
+	"note,First draft done,,,,Anna,,,,\n" +
+	"note,Sent to Bob,,,,Anna,,,,\n" +
+	"task,Standup,,4,1,Anna,,every monday,en,\n"
+
+// jsonFixture is a Sync API response with numeric IDs, a deleted project and
+// a task of a project missing from the export
+const jsonFixture = `{
+	"projects": [
+		{"id": 2, "name": "Work", "child_order": 2},
+		{"id": 1, "name": "Home", "child_order": 1},
+		{"id": 3, "name": "Gone", "is_deleted": true}
+	],
+	"sections": [{"id": "s1", "name": "Errands"}],
+	"labels": [{"id": "l1", "name": "phone"}],
+	"items": [
+		{"id": "t1", "project_id": 1, "content": "Urgent", "priority": 4, "child_order": 1,
+			"labels": ["l1", "home"], "section_id": "s1", "added_at": "2026-10-01T08:00:00Z"},
+		{"id": "t2", "project_id": 1, "content": "Important", "priority": 3, "child_order": 2,
+			"due": {"date": "2026-10-20"}},
+		{"id": "t3", "project_id": 1, "content": "Someday", "priority": 2, "child_order": 3,
+			"due": {"date": "2026-10-20T15:00:00", "timezone": "Europe/Berlin"}},
+		{"id": "t4", "project_id": 1, "content": "Whenever", "priority": 1, "child_order": 4,
+			"due": {"date": "2026-10-20T15:00:00Z"}},
+		{"id": "t5", "project_id": 1, "parent_id": "t1", "content": "Call", "checked": true,
+			"completed_at": "2026-10-02T10:00:00Z"},
+		{"id": "t6", "project_id": 2, "content": "Standup",
+			"due": {"date": "2026-10-19", "string": "every monday", "is_recurring": true}},
+		{"id": "t7", "project_id": 3, "content": "Deleted with its project"},
+		{"id": "t8", "project_id": 2, "content": "Deleted", "is_deleted": true},
+		{"id": "t9", "project_id": 9, "content": "Orphan"}
+	]
+}`
+
+var (
+	moscow = mustLoadLocation("Europe/Moscow")
+	berlin = mustLoadLocation("Europe/Berlin")
+	now    = time.Date(2026, time.October, 16, 10, 30, 0, 0, moscow)
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// converted is an item of a converted document with its metadata
+type converted struct {
+	domain.Item
+	meta  domain.ItemMetadata
+	board string
+}
+
+// convert converts data and returns the items by title
+func convert(t *testing.T, data []byte, opts importer.Options) (*domain.ExportDocument, map[string]converted) {
+	t.Helper()
+	doc, err := New().Convert(data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boards := make(map[string]string, len(doc.Boards))
+	for _, board := range doc.Boards {
+		boards[board.ID.String()] = board.Name
+	}
+	items := make(map[string]converted, len(doc.Items))
+	for _, item := range doc.Items {
+		c := converted{Item: item, board: boards[item.BoardID.String()]}
+		if err := json.Unmarshal(item.Metadata, &c.meta); err != nil {
+			t.Fatal(err)
+		}
+		items[item.Title] = c
+	}
+	return doc, items
+}
+
+func TestConvertCSV(t *testing.T) {
+	doc, items := convert(t, []byte(csvFixture), importer.Options{
+		Filename: "Groceries [2203306141].csv",
+		Location: moscow,
+		Now:      now,
+	})
+
+	if len(doc.Boards) != 1 || doc.Boards[0].Name != "Groceries" || doc.Boards[0].Type != domain.BoardTypeChecklist {
+		t.Fatalf("boards = %+v, want one checklist board named Groceries", doc.Boards)
+	}
+	if len(items) != 7 {
+		t.Fatalf("got %d items, want 7", len(items))
+	}
+
+	tests := []struct {
+		title    string
+		parent   string // Title of the parent, empty for a top-level task
+		position int
+		content  string
+		priority string
+		labels   []string
+	}{
+		{"Buy groceries", "", 0, "Milk and eggs\n\nCheck the fridge first", "high", []string{"errands"}},
+		{"Milk", "Buy groceries", 0, "", "", nil},
+		{"Eggs", "Milk", 0, "", "", nil},
+		// A task is at most one level below the task before it
+		{"Way too deep", "Eggs", 0, "", "", nil},
+		{"Bread", "Buy groceries", 1, "", "low", nil},
+		// A section starts again at the top level and labels its tasks
+		{"Write report", "", 1, "First draft done\n\nSent to Bob", "medium", []string{"Work"}},
+		{"Standup", "", 2, "", "", []string{"Work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			item, ok := items[tt.title]
+			if !ok {
+				t.Fatalf("item %q is missing", tt.title)
+			}
+			switch {
+			case tt.parent == "" && item.ParentID != nil:
+				t.Errorf("parent = %v, want none", *item.ParentID)
+			case tt.parent != "" && (item.ParentID == nil || *item.ParentID != items[tt.parent].ID):
+				t.Errorf("parent = %v, want %q", item.ParentID, tt.parent)
+			}
+			if item.Position != tt.position {
+				t.Errorf("position = %d, want %d", item.Position, tt.position)
+			}
+			if item.Content != tt.content {
+				t.Errorf("content = %q, want %q", item.Content, tt.content)
+			}
+			if item.meta.Priority != tt.priority {
+				t.Errorf("priority = %q, want %q", item.meta.Priority, tt.priority)
+			}
+			if !slices.Equal(item.meta.Labels, tt.labels) {
+				t.Errorf("labels = %q, want %q", item.meta.Labels, tt.labels)
+			}
+			if item.Status != domain.ItemStatusPending {
+				t.Errorf("status = %q, want %q", item.Status, domain.ItemStatusPending)
+			}
+		})
+	}
+
+	dates := []struct {
+		title  string
+		due    time.Time
+		allDay bool
+	}{
+		{"Buy groceries", time.Date(2026, time.October, 20, 0, 0, 0, 0, moscow), true},
+		{"Write report", time.Date(2026, time.October, 21, 15, 0, 0, 0, berlin), false},
+		{"Standup", time.Date(2026, time.October, 19, 0, 0, 0, 0, moscow), true},
+	}
+	for _, tt := range dates {
+		item := items[tt.title]
+		if item.DueDate == nil || !item.DueDate.Equal(tt.due) {
+			t.Errorf("%s: due = %v, want %v", tt.title, item.DueDate, tt.due)
+		}
+		if item.meta.AllDay != tt.allDay {
+			t.Errorf("%s: all day = %v, want %v", tt.title, item.meta.AllDay, tt.allDay)
+		}
+	}
+	if rule := items["Standup"].meta.RecurRule; !strings.Contains(rule, "FREQ=WEEKLY") || !strings.Contains(rule, "BYDAY=MO") {
+		t.Errorf("Standup: rule = %q, want weekly on Mondays", rule)
+	}
+	if items["Milk"].DueDate != nil {
+		t.Errorf("Milk: due = %v, want none", items["Milk"].DueDate)
+	}
+}
+
+func TestConvertJSON(t *testing.T) {
+	doc, items := convert(t, []byte(jsonFixture), importer.Options{Location: moscow, Now: now})
+
+	var boards []string
+	for _, board := range doc.Boards {
+		boards = append(boards, board.Name)
+	}
+	// Projects in their order, then the board of tasks without a project
+	if want := []string{"Home", "Work", FolderName}; !slices.Equal(boards, want) {
+		t.Errorf("boards = %q, want %q", boards, want)
+	}
+	if len(items) != 7 {
+		t.Fatalf("got %d items, want 7", len(items))
+	}
+	for _, title := range []string{"Deleted with its project", "Deleted"} {
+		if _, ok := items[title]; ok {
+			t.Errorf("deleted item %q was imported", title)
+		}
+	}
+
+	tests := []struct {
+		title    string
+		board    string
+		priority string
+		labels   []string
+		due      *time.Time
+		allDay   bool
+	}{
+		// API priorities run the other way round: 4 is Todoist's "p1"
+		{"Urgent", "Home", "high", []string{"phone", "home", "Errands"}, nil, false},
+		{"Important", "Home", "medium", nil, ptr(time.Date(2026, time.October, 20, 0, 0, 0, 0, moscow)), true},
+		{"Someday", "Home", "low", nil, ptr(time.Date(2026, time.October, 20, 15, 0, 0, 0, berlin)), false},
+		{"Whenever", "Home", "", nil, ptr(time.Date(2026, time.October, 20, 15, 0, 0, 0, time.UTC)), false},
+		{"Call", "Home", "", nil, nil, false},
+		{"Standup", "Work", "", nil, ptr(time.Date(2026, time.October, 19, 0, 0, 0, 0, moscow)), true},
+		{"Orphan", FolderName, "", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			item, ok := items[tt.title]
+			if !ok {
+				t.Fatalf("item %q is missing", tt.title)
+			}
+			if item.board != tt.board {
+				t.Errorf("board = %q, want %q", item.board, tt.board)
+			}
+			if item.meta.Priority != tt.priority {
+				t.Errorf("priority = %q, want %q", item.meta.Priority, tt.priority)
+			}
+			if !slices.Equal(item.meta.Labels, tt.labels) {
+				t.Errorf("labels = %q, want %q", item.meta.Labels, tt.labels)
+			}
+			switch {
+			case tt.due == nil && item.DueDate != nil:
+				t.Errorf("due = %v, want none", item.DueDate)
+			case tt.due != nil && (item.DueDate == nil || !item.DueDate.Equal(*tt.due)):
+				t.Errorf("due = %v, want %v", item.DueDate, *tt.due)
+			}
+			if item.meta.AllDay != tt.allDay {
+				t.Errorf("all day = %v, want %v", item.meta.AllDay, tt.allDay)
+			}
+		})
+	}
+
+	call := items["Call"]
+	if call.ParentID == nil || *call.ParentID != items["Urgent"].ID {
+		t.Errorf("Call: parent = %v, want Urgent", call.ParentID)
+	}
+	if call.Status != domain.ItemStatusCompleted || call.CompletedAt == nil ||
+		!call.CompletedAt.Equal(time.Date(2026, time.October, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Call: status = %q, completed at %v, want completed at its completed_at", call.Status, call.CompletedAt)
+	}
+	if created := items["Urgent"].CreatedAt; !created.Equal(time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Urgent: created at %v, want its added_at", created)
+	}
+	if rule := items["Standup"].meta.RecurRule; !strings.Contains(rule, "FREQ=WEEKLY") {
+		t.Errorf("Standup: rule = %q, want weekly", rule)
+	}
+}
+
+// zipOf returns a zip archive of the named files
+func zipOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestConvertZip(t *testing.T) {
+	data := zipOf(t, map[string]string{
+		"Work [2203306142].csv": "TYPE,CONTENT,PRIORITY,INDENT\ntask,Write report,1,1\n",
+		"Home [2203306141].CSV": "\xef\xbb\xbfTYPE,CONTENT,PRIORITY,INDENT\ntask,Water plants,4,1\ntask,Fern,4,2\n",
+		"README.txt":            "not a project",
+	})
+	doc, items := convert(t, data, importer.Options{Filename: "backup.zip", Location: moscow, Now: now})
+
+	var boards []string
+	for _, board := range doc.Boards {
+		boards = append(boards, board.Name)
+	}
+	if want := []string{"Home", "Work"}; !slices.Equal(boards, want) {
+		t.Errorf("boards = %q, want %q", boards, want)
+	}
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+	if items["Water plants"].board != "Home" || items["Write report"].board != "Work" {
+		t.Errorf("items are not on the board of their file")
+	}
+	if fern := items["Fern"]; fern.ParentID == nil || *fern.ParentID != items["Water plants"].ID {
+		t.Errorf("Fern: parent = %v, want Water plants", fern.ParentID)
+	}
+}
+
+func TestConvertZipLimits(t *testing.T) {
+	// Zeros compress to almost nothing, the budget covers all files together
+	padding := strings.Repeat("0", 600)
+	data := zipOf(t, map[string]string{
+		"A.csv": "TYPE,CONTENT\ntask,A " + padding + "\n",
+		"B.csv": "TYPE,CONTENT\ntask,B " + padding + "\n",
+	})
+	if _, err := New().Convert(data, importer.Options{MaxSize: 1000}); !errors.Is(err, importer.ErrInvalidExport) {
+		t.Errorf("error = %v over the size limit, want ErrInvalidExport", err)
+	}
+	if _, err := New().Convert(data, importer.Options{MaxSize: 2000}); err != nil {
+		t.Errorf("error = %v within the size limit, want none", err)
+	}
+
+	// A folder, two boards and two items
+	if _, err := New().Convert(data, importer.Options{MaxRows: 4}); !errors.Is(err, importer.ErrInvalidExport) {
+		t.Errorf("error = %v over the row limit, want ErrInvalidExport", err)
+	}
+	if _, err := New().Convert(data, importer.Options{MaxRows: 5}); err != nil {
+		t.Errorf("error = %v at the row limit, want none", err)
+	}
+
+	if _, err := New().Convert(zipOf(t, map[string]string{"notes.txt": "hi"}), importer.Options{}); !errors.Is(err, importer.ErrInvalidExport) {
+		t.Errorf("error = %v for a zip without projects, want ErrInvalidExport", err)
+	}
+}
+
+func TestConvertRejectsOtherFiles(t *testing.T) {
+	inputs := []string{
+		"",
+		"name,email\nAnna,anna@example.com\n",
+		`{"boards": []}`,
+		`{"projects": "none"}`,
+		"PK\x03\x04 broken",
+	}
+	for _, input := range inputs {
+		if _, err := New().Convert([]byte(input), importer.Options{}); !errors.Is(err, importer.ErrInvalidExport) {
+			t.Errorf("Convert(%q) error = %v, want ErrInvalidExport", input, err)
+		}
+	}
+}
+
+func TestBoardName(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"Groceries [2203306141].csv", "Groceries"},
+		{"backup/Work [1].csv", "Work"},
+		{`C:\Users\anna\Home.csv`, "Home"},
+		{"Plain", "Plain"},
+		{"[123].csv", FolderName},
+		{"", FolderName},
+	}
+	for _, tt := range tests {
+		if got := boardName(tt.filename); got != tt.want {
+			t.Errorf("boardName(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
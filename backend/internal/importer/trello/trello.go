@@ -0,0 +1,231 @@
+// Package trello converts the JSON export of a Trello board ("Print and
+// export" → "Export as JSON") into a kanban board.
+package trello
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+)
+
+// FolderName is the folder imported boards are put in
+const FolderName = "Trello"
+
+// columnColors are given to the lists in turn, Trello lists have no color
+var columnColors = []string{"#6366f1", "#f59e0b", "#10b981", "#ef4444", "#8b5cf6", "#06b6d4"}
+
+type board struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Lists      []list      `json:"lists"`
+	Cards      []card      `json:"cards"`
+	Checklists []checklist `json:"checklists"`
+	Labels     []label     `json:"labels"`
+}
+
+type list struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type card struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Desc        string     `json:"desc"`
+	Closed      bool       `json:"closed"`
+	IDList      string     `json:"idList"`
+	IDLabels    []string   `json:"idLabels"`
+	Due         *time.Time `json:"due"`
+	DueComplete bool       `json:"dueComplete"`
+	Pos         float64    `json:"pos"`
+}
+
+type checklist struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	IDCard     string      `json:"idCard"`
+	Pos        float64     `json:"pos"`
+	CheckItems []checkItem `json:"checkItems"`
+}
+
+type checkItem struct {
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	State string     `json:"state"` // "complete" or "incomplete"
+	Due   *time.Time `json:"due"`
+	Pos   float64    `json:"pos"`
+}
+
+type label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Importer implements importer.Importer for Trello
+type Importer struct{}
+
+func New() *Importer {
+	return &Importer{}
+}
+
+// Convert maps the board to a kanban board in the Trello folder: open lists
+// become columns, cards become items in their list's column and checklist
+// items become child items of their card. A card with several checklists
+// gets a child item per checklist holding its items. Labels are kept by name
+// (or color, for unnamed labels). Archived cards and the cards of archived
+// lists are imported as archived items.
+func (i *Importer) Convert(data []byte, opts importer.Options) (*domain.ExportDocument, error) {
+	var b board
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%w: not a Trello board export", importer.ErrInvalidExport)
+	}
+	if b.ID == "" || b.Name == "" {
+		return nil, fmt.Errorf("%w: not a Trello board export", importer.ErrInvalidExport)
+	}
+
+	sort.SliceStable(b.Lists, func(x, y int) bool { return b.Lists[x].Pos < b.Lists[y].Pos })
+	sort.SliceStable(b.Cards, func(x, y int) bool { return b.Cards[x].Pos < b.Cards[y].Pos })
+	sort.SliceStable(b.Checklists, func(x, y int) bool { return b.Checklists[x].Pos < b.Checklists[y].Pos })
+
+	var settings domain.BoardSettings
+	openLists := make(map[string]bool)
+	for _, l := range b.Lists {
+		if l.Closed {
+			continue
+		}
+		settings.Columns = append(settings.Columns, domain.KanbanColumn{
+			ID:    l.ID,
+			Name:  importer.Truncate(l.Name, 255),
+			Color: columnColors[len(settings.Columns)%len(columnColors)],
+		})
+		openLists[l.ID] = true
+	}
+
+	labels := make(map[string]string, len(b.Labels))
+	for _, l := range b.Labels {
+		name := l.Name
+		if name == "" {
+			name = l.Color
+		}
+		labels[l.ID] = name
+	}
+
+	doc := importer.NewDocument(opts.Now)
+	folderID := importer.AddFolder(doc, FolderName)
+	boardID := importer.AddBoard(doc, folderID, b.Name, domain.BoardTypeKanban, settings)
+
+	checklists := make(map[string][]checklist)
+	for _, c := range b.Checklists {
+		checklists[c.IDCard] = append(checklists[c.IDCard], c)
+	}
+
+	positions := make(map[string]int)
+	for _, c := range b.Cards {
+		meta := domain.ItemMetadata{ColumnID: c.IDList}
+		for _, id := range c.IDLabels {
+			if name := labels[id]; name != "" {
+				meta.Labels = append(meta.Labels, name)
+			}
+		}
+
+		item := domain.Item{
+			ID:        uuid.New(),
+			BoardID:   boardID,
+			Title:     importer.Title(c.Name, "Untitled card"),
+			Content:   importer.Truncate(c.Desc, importer.MaxContentLength),
+			Status:    domain.ItemStatusPending,
+			Position:  positions[c.IDList],
+			DueDate:   c.Due,
+			Metadata:  importer.Metadata(meta),
+			CreatedAt: createdAt(c.ID),
+		}
+		positions[c.IDList]++
+
+		switch {
+		case c.Closed || !openLists[c.IDList]:
+			item.Status = domain.ItemStatusArchived
+		case c.DueComplete:
+			item.Status = domain.ItemStatusCompleted
+			item.CompletedAt = completedAt(c.Due, opts.Now)
+		}
+		doc.Items = append(doc.Items, item)
+
+		cardChecklists := checklists[c.ID]
+		for n, cl := range cardChecklists {
+			parentID := item.ID
+			if len(cardChecklists) > 1 {
+				group := childItem(boardID, parentID, cl.Name, n, nil, nil)
+				group.CreatedAt = createdAt(cl.ID)
+				doc.Items = append(doc.Items, group)
+				parentID = group.ID
+			}
+
+			sort.SliceStable(cl.CheckItems, func(x, y int) bool { return cl.CheckItems[x].Pos < cl.CheckItems[y].Pos })
+			for m, ci := range cl.CheckItems {
+				var completed *time.Time
+				if ci.State == "complete" {
+					completed = completedAt(ci.Due, opts.Now)
+				}
+				child := childItem(boardID, parentID, ci.Name, m, ci.Due, completed)
+				child.CreatedAt = createdAt(ci.ID)
+				doc.Items = append(doc.Items, child)
+			}
+		}
+		if err := opts.CheckRows(doc, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// childItem returns a checklist item, completed when completedAt is set
+func childItem(boardID, parentID uuid.UUID, name string, position int, due, completedAt *time.Time) domain.Item {
+	item := domain.Item{
+		ID:       uuid.New(),
+		BoardID:  boardID,
+		ParentID: &parentID,
+		Title:    importer.Title(name, "Checklist"),
+		Status:   domain.ItemStatusPending,
+		Position: position,
+		DueDate:  due,
+	}
+	if completedAt != nil {
+		item.Status = domain.ItemStatusCompleted
+		item.CompletedAt = completedAt
+	}
+	return item
+}
+
+// completedAt returns the completion time of a done card or check item:
+// Trello only keeps the due date, the time of the import stands in otherwise
+func completedAt(due *time.Time, now time.Time) *time.Time {
+	if due != nil && due.Before(now) {
+		return due
+	}
+	return &now
+}
+
+// createdAt reads the creation time from a Trello ID, whose first four bytes
+// are a Unix timestamp. Returns the zero time for other IDs.
+func createdAt(id string) time.Time {
+	if len(id) != 24 {
+		return time.Time{}
+	}
+	b, err := hex.DecodeString(strings.ToLower(id[:8]))
+	if err != nil {
+		return time.Time{}
+	}
+	seconds := int64(b[0])<<24 | int64(b[1])<<16 | int64(b[2])<<8 | int64(b[3])
+	return time.Unix(seconds, 0).UTC()
+}
@@ -0,0 +1,198 @@
+package trello
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+)
+
+// fixture is a board with an open and an archived list, an archived card, a
+// card with one checklist and a card with two
+const fixture = `{
+	"id": "5f5e10000000000000000000",
+	"name": "Roadmap",
+	"lists": [
+		{"id": "list-done", "name": "Done", "closed": true, "pos": 2},
+		{"id": "list-todo", "name": "To do", "closed": false, "pos": 1}
+	],
+	"labels": [
+		{"id": "label-urgent", "name": "Urgent", "color": "red"},
+		{"id": "label-green", "name": "", "color": "green"}
+	],
+	"cards": [
+		{"id": "5f5e10000000000000000004", "name": "Release", "idList": "list-todo", "pos": 4,
+			"due": "2026-10-01T09:00:00Z", "dueComplete": true},
+		{"id": "5f5e10000000000000000001", "name": "Design", "desc": "Mockups", "idList": "list-todo", "pos": 1,
+			"idLabels": ["label-urgent", "label-green", "label-missing"], "due": "2026-10-20T09:00:00Z"},
+		{"id": "5f5e10000000000000000002", "name": "Old idea", "idList": "list-todo", "pos": 2, "closed": true},
+		{"id": "5f5e10000000000000000003", "name": "Shipped", "idList": "list-done", "pos": 3}
+	],
+	"checklists": [
+		{"id": "5f5e20000000000000000003", "name": "QA", "idCard": "5f5e10000000000000000004", "pos": 2,
+			"checkItems": [{"id": "ci-qa", "name": "Smoke test", "state": "complete", "pos": 1}]},
+		{"id": "5f5e20000000000000000001", "name": "Steps", "idCard": "5f5e10000000000000000001", "pos": 1,
+			"checkItems": [
+				{"id": "ci-second", "name": "Review", "state": "complete", "pos": 2, "due": "2026-10-10T00:00:00Z"},
+				{"id": "ci-first", "name": "Sketch", "state": "incomplete", "pos": 1}
+			]},
+		{"id": "5f5e20000000000000000002", "name": "Docs", "idCard": "5f5e10000000000000000004", "pos": 1,
+			"checkItems": [{"id": "ci-docs", "name": "Changelog", "state": "incomplete", "pos": 1}]}
+	]
+}`
+
+func TestConvert(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	doc, err := New().Convert([]byte(fixture), importer.Options{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Folders) != 1 || doc.Folders[0].Name != FolderName {
+		t.Fatalf("folders = %+v, want one named %q", doc.Folders, FolderName)
+	}
+	if len(doc.Boards) != 1 || doc.Boards[0].Name != "Roadmap" || doc.Boards[0].Type != domain.BoardTypeKanban {
+		t.Fatalf("boards = %+v, want one kanban board named Roadmap", doc.Boards)
+	}
+	var settings domain.BoardSettings
+	if err := json.Unmarshal(doc.Boards[0].Settings, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Columns) != 1 || settings.Columns[0].ID != "list-todo" || settings.Columns[0].Name != "To do" {
+		t.Errorf("columns = %+v, want only the open list", settings.Columns)
+	}
+
+	items := make(map[string]domain.Item, len(doc.Items))
+	for _, item := range doc.Items {
+		items[item.Title] = item
+	}
+	if len(items) != 10 {
+		t.Fatalf("got %d items, want 10", len(items))
+	}
+
+	tests := []struct {
+		title    string
+		parent   string // Title of the parent, empty for a card
+		status   domain.ItemStatus
+		position int
+		labels   []string
+		created  string // "2006-01-02 15:04:05" in UTC, empty for none
+	}{
+		{"Design", "", domain.ItemStatusPending, 0, []string{"Urgent", "green"}, "2020-09-13 12:26:40"},
+		{"Old idea", "", domain.ItemStatusArchived, 1, nil, "2020-09-13 12:26:40"},
+		{"Shipped", "", domain.ItemStatusArchived, 0, nil, "2020-09-13 12:26:40"},
+		{"Release", "", domain.ItemStatusCompleted, 2, nil, "2020-09-13 12:26:40"},
+
+		// One checklist: its items are children of the card, in order
+		{"Sketch", "Design", domain.ItemStatusPending, 0, nil, ""},
+		{"Review", "Design", domain.ItemStatusCompleted, 1, nil, ""},
+
+		// Several checklists: one child per checklist holds its items
+		{"Docs", "Release", domain.ItemStatusPending, 0, nil, "2020-09-13 13:34:56"},
+		{"QA", "Release", domain.ItemStatusPending, 1, nil, "2020-09-13 13:34:56"},
+		{"Changelog", "Docs", domain.ItemStatusPending, 0, nil, ""},
+		{"Smoke test", "QA", domain.ItemStatusCompleted, 0, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			item, ok := items[tt.title]
+			if !ok {
+				t.Fatalf("item %q is missing", tt.title)
+			}
+
+			switch {
+			case tt.parent == "" && item.ParentID != nil:
+				t.Errorf("parent = %v, want none", *item.ParentID)
+			case tt.parent != "" && (item.ParentID == nil || *item.ParentID != items[tt.parent].ID):
+				t.Errorf("parent = %v, want %q", item.ParentID, tt.parent)
+			}
+			if item.Status != tt.status {
+				t.Errorf("status = %q, want %q", item.Status, tt.status)
+			}
+			if item.Position != tt.position {
+				t.Errorf("position = %d, want %d", item.Position, tt.position)
+			}
+
+			var meta domain.ItemMetadata
+			if err := json.Unmarshal(item.Metadata, &meta); err != nil && item.Metadata != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(meta.Labels, tt.labels) {
+				t.Errorf("labels = %q, want %q", meta.Labels, tt.labels)
+			}
+
+			created := ""
+			if !item.CreatedAt.IsZero() {
+				created = item.CreatedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			if created != tt.created {
+				t.Errorf("created at = %q, want %q", created, tt.created)
+			}
+		})
+	}
+
+	// Done cards and check items are completed at their due date when it has
+	// passed, at the time of the import otherwise
+	if got := items["Release"].CompletedAt; got == nil || !got.Equal(time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Release completed at %v, want its due date", got)
+	}
+	if got := items["Review"].CompletedAt; got == nil || !got.Equal(time.Date(2026, time.October, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Review completed at %v, want its due date", got)
+	}
+	if got := items["Smoke test"].CompletedAt; got == nil || !got.Equal(now) {
+		t.Errorf("Smoke test completed at %v, want %v", got, now)
+	}
+	if got := items["Design"].Content; got != "Mockups" {
+		t.Errorf("Design content = %q, want %q", got, "Mockups")
+	}
+}
+
+func TestConvertRejectsOtherFiles(t *testing.T) {
+	inputs := []string{
+		"",
+		"not json",
+		`{}`,
+		`{"id": "5f5e10000000000000000000"}`,
+		`[{"id": "1", "name": "Board"}]`,
+	}
+	for _, input := range inputs {
+		if _, err := New().Convert([]byte(input), importer.Options{}); !errors.Is(err, importer.ErrInvalidExport) {
+			t.Errorf("Convert(%q) error = %v, want ErrInvalidExport", input, err)
+		}
+	}
+}
+
+func TestConvertStopsAtRowLimit(t *testing.T) {
+	_, err := New().Convert([]byte(fixture), importer.Options{MaxRows: 5})
+	if !errors.Is(err, importer.ErrInvalidExport) {
+		t.Errorf("error = %v, want ErrInvalidExport", err)
+	}
+
+	// A folder, a board and ten items
+	if _, err := New().Convert([]byte(fixture), importer.Options{MaxRows: 12}); err != nil {
+		t.Errorf("error = %v at the limit, want none", err)
+	}
+}
+
+func TestCreatedAt(t *testing.T) {
+	tests := []struct {
+		id   string
+		want time.Time
+	}{
+		{"5f5e10000000000000000000", time.Date(2020, time.September, 13, 12, 26, 40, 0, time.UTC)},
+		{"5F5E1000AAAAAAAAAAAAAAAA", time.Date(2020, time.September, 13, 12, 26, 40, 0, time.UTC)},
+		{"00000000ffffffffffffffff", time.Unix(0, 0).UTC()},
+		{"", time.Time{}},
+		{"list-todo", time.Time{}},
+		{"zzzzzzzz0000000000000000", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := createdAt(tt.id); !got.Equal(tt.want) {
+			t.Errorf("createdAt(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
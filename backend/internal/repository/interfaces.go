@@ -12,10 +12,24 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id int64) (*domain.User, error)
 	Create(ctx context.Context, user *domain.User) error
 	Update(ctx context.Context, user *domain.User) error
+	// UpdateSettings saves all settings at once; it returns ErrInvalidInput
+	// and saves nothing when the inbox board is not the user's
 	UpdateSettings(ctx context.Context, userID int64, settings *domain.UserSettings) error
 	UpdateLastActive(ctx context.Context, userID int64) error
+	DisableNotifications(ctx context.Context, userID int64) error
 	GetInactiveUsers(ctx context.Context, since time.Time) ([]domain.InactiveUser, error)
 	GetUsersForReminderHour(ctx context.Context, hour int) ([]domain.User, error)
+	GetUsersForDigest(ctx context.Context, catchUp time.Duration) ([]domain.User, error)
+	// MarkDigestSent records the digest of the local date and reports false
+	// when it was recorded already
+	MarkDigestSent(ctx context.Context, userID int64, date time.Time) (bool, error)
+	UnmarkDigestSent(ctx context.Context, userID int64, date time.Time) error
+	GetUsersForWeeklyReport(ctx context.Context, weekday time.Weekday, hour int) ([]domain.User, error)
+	MarkWeeklyReportSent(ctx context.Context, userID int64, date time.Time) (bool, error)
+	UnmarkWeeklyReportSent(ctx context.Context, userID int64, date time.Time) error
+	GetUsersForOverdueCheck(ctx context.Context, hour int, catchUp time.Duration) ([]domain.User, error)
+	MarkOverdueNotified(ctx context.Context, userID int64, date time.Time) (bool, error)
+	UnmarkOverdueNotified(ctx context.Context, userID int64, date time.Time) error
 }
 
 type FolderRepository interface {
@@ -39,41 +53,56 @@ type BoardRepository interface {
 	UpdatePositions(ctx context.Context, folderID uuid.UUID, boardIDs []uuid.UUID) error
 	CountByUserID(ctx context.Context, userID int64) (int, error)
 	GetFolderOwner(ctx context.Context, folderID uuid.UUID) (int64, error)
+	GetFirstByType(ctx context.Context, userID int64, boardType domain.BoardType) (*domain.Board, error)
 }
 
 type ItemRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Item, error)
 	GetByBoardID(ctx context.Context, boardID uuid.UUID, filter *domain.ItemFilter) ([]domain.Item, error)
+	GetAllByBoardID(ctx context.Context, boardID uuid.UUID) ([]domain.Item, error)
 	GetWithChildren(ctx context.Context, id uuid.UUID) (*domain.Item, error)
 	Create(ctx context.Context, item *domain.Item) error
 	Update(ctx context.Context, item *domain.Item) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdatePositions(ctx context.Context, boardID uuid.UUID, itemIDs []uuid.UUID) error
 	Complete(ctx context.Context, id uuid.UUID, completed bool) error
-	GetOverdueTasks(ctx context.Context) ([]domain.OverdueTask, error)
+	// CompleteRecurring completes an open recurring item and, in the same
+	// transaction, creates its next occurrence with the given children and
+	// reminders. It reports whether the occurrence was created, which is not
+	// the case when the item was already completed or created one before.
+	CompleteRecurring(ctx context.Context, id uuid.UUID, next *domain.Item, reminders []domain.Reminder) (bool, error)
+	GetOverdueTasks(ctx context.Context, userID int64) ([]domain.OverdueTask, error)
+	GetDueSoonTasks(ctx context.Context) ([]domain.DueSoonTask, error)
 	GetDueSoon(ctx context.Context, userID int64, within time.Duration) ([]domain.Item, error)
 	CountByUserID(ctx context.Context, userID int64) (int, error)
 	CountCompletedByUserID(ctx context.Context, userID int64) (int, error)
 	CountOverdueByUserID(ctx context.Context, userID int64) (int, error)
 	GetBoardOwner(ctx context.Context, boardID uuid.UUID) (int64, error)
-	GetCompletionStats(ctx context.Context, userID int64, days int) ([]domain.CompletionStats, error)
+	GetCompletionStats(ctx context.Context, userID int64, days int, loc *time.Location) ([]domain.CompletionStats, error)
+	CountOverdueAt(ctx context.Context, userID int64, at time.Time) (int, error)
+	GetScheduledTasks(ctx context.Context, userID int64, excludeBoardID uuid.UUID) ([]domain.Item, error)
+	GetDueBetween(ctx context.Context, userID int64, from, to time.Time) ([]domain.Item, error)
+	GetOverdueByUserID(ctx context.Context, userID int64) ([]domain.Item, error)
 }
 
 type ReminderRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Reminder, error)
 	GetByItemID(ctx context.Context, itemID uuid.UUID) ([]domain.Reminder, error)
 	GetPending(ctx context.Context, before time.Time) ([]domain.Reminder, error)
+	ClaimPending(ctx context.Context, before time.Time, limit int, lease time.Duration) ([]domain.Reminder, error)
 	Create(ctx context.Context, reminder *domain.Reminder) error
 	Update(ctx context.Context, reminder *domain.Reminder) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	MarkSent(ctx context.Context, id uuid.UUID) error
 	DeleteByItemID(ctx context.Context, itemID uuid.UUID) error
+	DeleteRelativeByItemID(ctx context.Context, itemID uuid.UUID) error
 }
 
 type ActivityLogRepository interface {
 	Create(ctx context.Context, log *domain.ActivityLog) error
 	GetByUserID(ctx context.Context, userID int64, limit int) ([]domain.ActivityLog, error)
 	GetLastActivity(ctx context.Context, userID int64) (*domain.ActivityLog, error)
+	CountByBoard(ctx context.Context, userID int64, from, to time.Time, limit int) ([]domain.BoardActivity, error)
 }
 
 type HabitCompletionRepository interface {
@@ -82,3 +111,102 @@ type HabitCompletionRepository interface {
 	GetByItemID(ctx context.Context, itemID uuid.UUID, from, to time.Time) ([]domain.HabitCompletion, error)
 	GetStreak(ctx context.Context, itemID uuid.UUID) (current int, best int, err error)
 }
+
+type CalendarFeedRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	GetByUserID(ctx context.Context, userID int64) ([]domain.CalendarFeed, error)
+	Create(ctx context.Context, feed *domain.CalendarFeed) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateLastAccessed(ctx context.Context, id uuid.UUID) error
+}
+
+type BotListingRepository interface {
+	Save(ctx context.Context, userID int64, itemIDs []uuid.UUID) error
+	Get(ctx context.Context, userID int64) ([]uuid.UUID, error)
+}
+
+// UpdateOffsetRepository stores the getUpdates offset of the bot in polling mode
+type UpdateOffsetRepository interface {
+	LoadOffset(ctx context.Context, botID int64) (int64, error)
+	SaveOffset(ctx context.Context, botID int64, offset int64) error
+}
+
+// OutboxRepository queues Telegram notifications for the delivery worker
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, entry *domain.OutboxEntry) error
+	// EnqueueReminder marks the reminder sent and queues its notification in one
+	// transaction. Reports false when the reminder was already queued.
+	EnqueueReminder(ctx context.Context, entry *domain.OutboxEntry) (bool, error)
+	// ClaimDue leases up to limit due entries and counts the attempt. A claimed
+	// entry is not returned again until the lease expires.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEntry, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt time.Time) error
+	// Hold puts a claimed entry back until the given time without counting the attempt
+	Hold(ctx context.Context, id uuid.UUID, until time.Time) error
+	MarkDead(ctx context.Context, id uuid.UUID, lastError string) error
+	GetFailedByUserID(ctx context.Context, userID int64, limit int) ([]domain.OutboxEntry, error)
+	// DeleteSentBefore deletes entries sent before the given time and returns how many
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// JobLocker makes sure a scheduled job runs on only one instance at a time
+type JobLocker interface {
+	// TryLock takes the named lock without waiting and reports false when another
+	// instance holds it. Otherwise unlock must be called once the job is done.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// DueSoonMarkerRepository remembers which items were announced as due soon
+type DueSoonMarkerRepository interface {
+	// Mark records the announcement and reports false when it was recorded already
+	Mark(ctx context.Context, itemID uuid.UUID, dueDate time.Time) (bool, error)
+	Unmark(ctx context.Context, itemID uuid.UUID, dueDate time.Time) error
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// ExportRepository reads a user's data for the account export
+type ExportRepository interface {
+	// Snapshot calls fn with the user's data as of a single point in time
+	Snapshot(ctx context.Context, userID int64, fn func(ExportSnapshot) error) error
+}
+
+// ExportSnapshot streams the rows of one user, calling fn for each row in the
+// order of the export document. An error returned by fn stops the iteration.
+type ExportSnapshot interface {
+	Folders(ctx context.Context, fn func(*domain.Folder) error) error
+	Boards(ctx context.Context, fn func(*domain.Board) error) error
+	Items(ctx context.Context, fn func(*domain.Item) error) error
+	Reminders(ctx context.Context, fn func(*domain.Reminder) error) error
+	HabitCompletions(ctx context.Context, fn func(*domain.HabitCompletion) error) error
+}
+
+// ImportRepository writes an account import in a single transaction
+type ImportRepository interface {
+	// Transaction calls fn with the user's data and commits its writes when fn
+	// returns nil. Otherwise nothing is written.
+	Transaction(ctx context.Context, userID int64, fn func(ImportTx) error) error
+}
+
+// ImportTx reads and writes one user's data within an import. Created rows keep
+// the ID and timestamps they are given; zero timestamps are set to now.
+type ImportTx interface {
+	// Folders returns the existing folders with their boards
+	Folders(ctx context.Context) ([]domain.Folder, error)
+	// MaxItemPosition returns the highest position of a board's top-level
+	// items, -1 when it has none
+	MaxItemPosition(ctx context.Context, boardID uuid.UUID) (int, error)
+	CreateFolder(ctx context.Context, folder *domain.Folder) error
+	CreateBoard(ctx context.Context, board *domain.Board) error
+	CreateItem(ctx context.Context, item *domain.Item) error
+	CreateReminder(ctx context.Context, reminder *domain.Reminder) error
+	CreateHabitCompletion(ctx context.Context, completion *domain.HabitCompletion) error
+}
+
+// SearchRepository runs full-text searches over items
+type SearchRepository interface {
+	// Search returns a page of the user's items matching the query, best
+	// matches first, and the number of matches across all pages
+	Search(ctx context.Context, userID int64, query domain.SearchQuery) ([]domain.SearchHit, int, error)
+}
@@ -240,3 +240,36 @@ func (r *BoardRepository) GetFolderOwner(ctx context.Context, folderID uuid.UUID
 
 	return userID, nil
 }
+
+// GetFirstByType returns the user's first board of a type, in folder and board order
+func (r *BoardRepository) GetFirstByType(ctx context.Context, userID int64, boardType domain.BoardType) (*domain.Board, error) {
+	query := `
+		SELECT b.id, b.folder_id, b.name, b.type, b.settings, b.position, b.created_at, b.updated_at
+		FROM boards b
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1 AND b.type = $2
+		ORDER BY f.position ASC, b.position ASC, b.created_at ASC
+		LIMIT 1
+	`
+
+	var board domain.Board
+	err := r.db.QueryRow(ctx, query, userID, boardType).Scan(
+		&board.ID,
+		&board.FolderID,
+		&board.Name,
+		&board.Type,
+		&board.Settings,
+		&board.Position,
+		&board.CreatedAt,
+		&board.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &board, nil
+}
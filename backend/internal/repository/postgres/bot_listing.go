@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+type BotListingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBotListingRepository(db *pgxpool.Pool) *BotListingRepository {
+	return &BotListingRepository{db: db}
+}
+
+// Save replaces the user's last listing
+func (r *BotListingRepository) Save(ctx context.Context, userID int64, itemIDs []uuid.UUID) error {
+	query := `
+		INSERT INTO bot_listings (user_id, item_ids, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			item_ids = EXCLUDED.item_ids,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, itemIDs)
+	return err
+}
+
+func (r *BotListingRepository) Get(ctx context.Context, userID int64) ([]uuid.UUID, error) {
+	query := `SELECT item_ids FROM bot_listings WHERE user_id = $1`
+
+	var itemIDs []uuid.UUID
+	err := r.db.QueryRow(ctx, query, userID).Scan(&itemIDs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return itemIDs, nil
+}
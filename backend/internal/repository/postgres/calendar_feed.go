@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+type CalendarFeedRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarFeedRepository(db *pgxpool.Pool) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func (r *CalendarFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.CalendarFeed, error) {
+	query := `
+		SELECT id, user_id, board_id, token_hash, include_tasks, last_accessed_at, created_at
+		FROM calendar_feeds
+		WHERE id = $1
+	`
+
+	return r.scanOne(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *CalendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	query := `
+		SELECT id, user_id, board_id, token_hash, include_tasks, last_accessed_at, created_at
+		FROM calendar_feeds
+		WHERE token_hash = $1
+	`
+
+	return r.scanOne(r.db.QueryRow(ctx, query, tokenHash))
+}
+
+func (r *CalendarFeedRepository) scanOne(row pgx.Row) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := row.Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.BoardID,
+		&feed.TokenHash,
+		&feed.IncludeTasks,
+		&feed.LastAccessedAt,
+		&feed.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) GetByUserID(ctx context.Context, userID int64) ([]domain.CalendarFeed, error) {
+	query := `
+		SELECT id, user_id, board_id, token_hash, include_tasks, last_accessed_at, created_at
+		FROM calendar_feeds
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []domain.CalendarFeed
+	for rows.Next() {
+		var feed domain.CalendarFeed
+		if err := rows.Scan(
+			&feed.ID,
+			&feed.UserID,
+			&feed.BoardID,
+			&feed.TokenHash,
+			&feed.IncludeTasks,
+			&feed.LastAccessedAt,
+			&feed.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, feed)
+	}
+
+	return feeds, rows.Err()
+}
+
+func (r *CalendarFeedRepository) Create(ctx context.Context, feed *domain.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, board_id, token_hash, include_tasks)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		feed.UserID,
+		feed.BoardID,
+		feed.TokenHash,
+		feed.IncludeTasks,
+	).Scan(&feed.ID, &feed.CreatedAt)
+}
+
+func (r *CalendarFeedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM calendar_feeds WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CalendarFeedRepository) UpdateLastAccessed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE calendar_feeds SET last_accessed_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DueSoonMarkerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDueSoonMarkerRepository(db *pgxpool.Pool) *DueSoonMarkerRepository {
+	return &DueSoonMarkerRepository{db: db}
+}
+
+func (r *DueSoonMarkerRepository) Mark(ctx context.Context, itemID uuid.UUID, dueDate time.Time) (bool, error) {
+	query := `
+		INSERT INTO due_soon_notifications (item_id, due_date)
+		VALUES ($1, $2)
+		ON CONFLICT (item_id, due_date) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, itemID, dueDate)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *DueSoonMarkerRepository) Unmark(ctx context.Context, itemID uuid.UUID, dueDate time.Time) error {
+	query := `DELETE FROM due_soon_notifications WHERE item_id = $1 AND due_date = $2`
+	_, err := r.db.Exec(ctx, query, itemID, dueDate)
+	return err
+}
+
+// DeleteBefore removes markers of due dates that have long passed
+func (r *DueSoonMarkerRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM due_soon_notifications WHERE due_date < $1`
+	_, err := r.db.Exec(ctx, query, before)
+	return err
+}
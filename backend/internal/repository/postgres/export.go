@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+type ExportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExportRepository(db *pgxpool.Pool) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// Snapshot reads in a repeatable read transaction, so the rows of all queries
+// are consistent even while the user keeps editing
+func (r *ExportRepository) Snapshot(ctx context.Context, userID int64, fn func(repository.ExportSnapshot) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&exportSnapshot{tx: tx, userID: userID}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type exportSnapshot struct {
+	tx     pgx.Tx
+	userID int64
+}
+
+func (s *exportSnapshot) Folders(ctx context.Context, fn func(*domain.Folder) error) error {
+	query := `
+		SELECT id, user_id, name, color, icon, position, created_at, updated_at
+		FROM folders
+		WHERE user_id = $1
+		ORDER BY position ASC, created_at ASC
+	`
+
+	return eachRow(ctx, s, query, func(rows pgx.Rows, folder *domain.Folder) error {
+		return rows.Scan(
+			&folder.ID,
+			&folder.UserID,
+			&folder.Name,
+			&folder.Color,
+			&folder.Icon,
+			&folder.Position,
+			&folder.CreatedAt,
+			&folder.UpdatedAt,
+		)
+	}, fn)
+}
+
+func (s *exportSnapshot) Boards(ctx context.Context, fn func(*domain.Board) error) error {
+	query := `
+		SELECT b.id, b.folder_id, b.name, b.type, b.settings, b.position, b.created_at, b.updated_at
+		FROM boards b
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		ORDER BY f.position ASC, b.folder_id, b.position ASC, b.created_at ASC
+	`
+
+	return eachRow(ctx, s, query, func(rows pgx.Rows, board *domain.Board) error {
+		return rows.Scan(
+			&board.ID,
+			&board.FolderID,
+			&board.Name,
+			&board.Type,
+			&board.Settings,
+			&board.Position,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+		)
+	}, fn)
+}
+
+// Items lists top-level items first, then their children level by level
+func (s *exportSnapshot) Items(ctx context.Context, fn func(*domain.Item) error) error {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT i.id, 0 AS depth
+			FROM items i
+			JOIN boards b ON i.board_id = b.id
+			JOIN folders f ON b.folder_id = f.id
+			WHERE f.user_id = $1 AND i.parent_id IS NULL
+
+			UNION ALL
+
+			SELECT c.id, tree.depth + 1
+			FROM items c
+			JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT i.id, i.board_id, i.parent_id, i.title, COALESCE(i.content, ''), i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at
+		FROM tree
+		JOIN items i ON i.id = tree.id
+		ORDER BY tree.depth ASC, i.board_id, i.position ASC, i.created_at ASC
+	`
+
+	return eachRow(ctx, s, query, func(rows pgx.Rows, item *domain.Item) error {
+		return rows.Scan(
+			&item.ID,
+			&item.BoardID,
+			&item.ParentID,
+			&item.Title,
+			&item.Content,
+			&item.Status,
+			&item.Position,
+			&item.DueDate,
+			&item.CompletedAt,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+	}, fn)
+}
+
+func (s *exportSnapshot) Reminders(ctx context.Context, fn func(*domain.Reminder) error) error {
+	query := `
+		SELECT id, user_id, item_id, remind_at, COALESCE(message, ''), sent, sent_at, created_at, relative_offset
+		FROM reminders
+		WHERE user_id = $1
+		ORDER BY remind_at ASC
+	`
+
+	return eachRow(ctx, s, query, func(rows pgx.Rows, reminder *domain.Reminder) error {
+		return rows.Scan(
+			&reminder.ID,
+			&reminder.UserID,
+			&reminder.ItemID,
+			&reminder.RemindAt,
+			&reminder.Message,
+			&reminder.Sent,
+			&reminder.SentAt,
+			&reminder.CreatedAt,
+			&reminder.Offset,
+		)
+	}, fn)
+}
+
+func (s *exportSnapshot) HabitCompletions(ctx context.Context, fn func(*domain.HabitCompletion) error) error {
+	query := `
+		SELECT hc.id, hc.item_id, hc.completed_date, hc.created_at
+		FROM habit_completions hc
+		JOIN items i ON hc.item_id = i.id
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		ORDER BY hc.item_id, hc.completed_date ASC
+	`
+
+	return eachRow(ctx, s, query, func(rows pgx.Rows, c *domain.HabitCompletion) error {
+		return rows.Scan(&c.ID, &c.ItemID, &c.CompletedDate, &c.CreatedAt)
+	}, fn)
+}
+
+// eachRow runs a query of the user's rows and hands them to fn one at a time,
+// so the result is never held in memory as a whole
+func eachRow[T any](ctx context.Context, s *exportSnapshot, query string, scan func(pgx.Rows, *T) error, fn func(*T) error) error {
+	rows, err := s.tx.Query(ctx, query, s.userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := scan(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
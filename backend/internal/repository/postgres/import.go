@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+type ImportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImportRepository(db *pgxpool.Pool) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+func (r *ImportRepository) Transaction(ctx context.Context, userID int64, fn func(repository.ImportTx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&importTx{tx: tx, userID: userID}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type importTx struct {
+	tx     pgx.Tx
+	userID int64
+}
+
+func (t *importTx) Folders(ctx context.Context) ([]domain.Folder, error) {
+	// Lock the folders so concurrent imports of the same user merge one after the other
+	query := `
+		SELECT id, user_id, name, color, icon, position, created_at, updated_at
+		FROM folders
+		WHERE user_id = $1
+		ORDER BY position ASC
+		FOR UPDATE
+	`
+
+	rows, err := t.tx.Query(ctx, query, t.userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []domain.Folder
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var folder domain.Folder
+		if err := rows.Scan(
+			&folder.ID,
+			&folder.UserID,
+			&folder.Name,
+			&folder.Color,
+			&folder.Icon,
+			&folder.Position,
+			&folder.CreatedAt,
+			&folder.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		index[folder.ID] = len(folders)
+		folders = append(folders, folder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT b.id, b.folder_id, b.name, b.type, b.settings, b.position, b.created_at, b.updated_at
+		FROM boards b
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		ORDER BY b.position ASC
+	`
+
+	rows, err = t.tx.Query(ctx, query, t.userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var board domain.Board
+		if err := rows.Scan(
+			&board.ID,
+			&board.FolderID,
+			&board.Name,
+			&board.Type,
+			&board.Settings,
+			&board.Position,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		folder := &folders[index[board.FolderID]]
+		folder.Boards = append(folder.Boards, board)
+	}
+
+	return folders, rows.Err()
+}
+
+func (t *importTx) MaxItemPosition(ctx context.Context, boardID uuid.UUID) (int, error) {
+	query := `SELECT COALESCE(MAX(position), -1) FROM items WHERE board_id = $1 AND parent_id IS NULL`
+
+	var position int
+	err := t.tx.QueryRow(ctx, query, boardID).Scan(&position)
+	return position, err
+}
+
+func (t *importTx) CreateFolder(ctx context.Context, folder *domain.Folder) error {
+	query := `
+		INSERT INTO folders (id, user_id, name, color, icon, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), COALESCE($8, NOW()))
+		RETURNING created_at, updated_at
+	`
+
+	folder.UserID = t.userID
+	return t.tx.QueryRow(ctx, query,
+		folder.ID,
+		folder.UserID,
+		folder.Name,
+		folder.Color,
+		folder.Icon,
+		folder.Position,
+		nullTime(folder.CreatedAt),
+		nullTime(folder.UpdatedAt),
+	).Scan(&folder.CreatedAt, &folder.UpdatedAt)
+}
+
+func (t *importTx) CreateBoard(ctx context.Context, board *domain.Board) error {
+	query := `
+		INSERT INTO boards (id, folder_id, name, type, settings, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), COALESCE($8, NOW()))
+		RETURNING created_at, updated_at
+	`
+
+	settings := board.Settings
+	if settings == nil {
+		settings = []byte("{}")
+	}
+
+	return t.tx.QueryRow(ctx, query,
+		board.ID,
+		board.FolderID,
+		board.Name,
+		board.Type,
+		settings,
+		board.Position,
+		nullTime(board.CreatedAt),
+		nullTime(board.UpdatedAt),
+	).Scan(&board.CreatedAt, &board.UpdatedAt)
+}
+
+func (t *importTx) CreateItem(ctx context.Context, item *domain.Item) error {
+	query := `
+		INSERT INTO items (id, board_id, parent_id, title, content, status, position,
+		                   due_date, completed_at, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, NOW()), COALESCE($12, NOW()))
+		RETURNING created_at, updated_at
+	`
+
+	metadata := item.Metadata
+	if metadata == nil {
+		metadata = []byte("{}")
+	}
+
+	return t.tx.QueryRow(ctx, query,
+		item.ID,
+		item.BoardID,
+		item.ParentID,
+		item.Title,
+		item.Content,
+		item.Status,
+		item.Position,
+		item.DueDate,
+		item.CompletedAt,
+		metadata,
+		nullTime(item.CreatedAt),
+		nullTime(item.UpdatedAt),
+	).Scan(&item.CreatedAt, &item.UpdatedAt)
+}
+
+func (t *importTx) CreateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	query := `
+		INSERT INTO reminders (id, user_id, item_id, remind_at, message, sent, sent_at, relative_offset, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
+		RETURNING created_at
+	`
+
+	reminder.UserID = t.userID
+	return t.tx.QueryRow(ctx, query,
+		reminder.ID,
+		reminder.UserID,
+		reminder.ItemID,
+		reminder.RemindAt,
+		reminder.Message,
+		reminder.Sent,
+		reminder.SentAt,
+		reminder.Offset,
+		nullTime(reminder.CreatedAt),
+	).Scan(&reminder.CreatedAt)
+}
+
+func (t *importTx) CreateHabitCompletion(ctx context.Context, completion *domain.HabitCompletion) error {
+	query := `
+		INSERT INTO habit_completions (id, item_id, completed_date, created_at)
+		VALUES ($1, $2, $3, COALESCE($4, NOW()))
+		RETURNING created_at
+	`
+
+	return t.tx.QueryRow(ctx, query,
+		completion.ID,
+		completion.ItemID,
+		completion.CompletedDate,
+		nullTime(completion.CreatedAt),
+	).Scan(&completion.CreatedAt)
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -124,6 +124,59 @@ func (r *ItemRepository) GetByBoardID(ctx context.Context, boardID uuid.UUID, fi
 	return items, rows.Err()
 }
 
+// GetAllByBoardID returns all items of a board, nested ones included, with
+// parents before their children
+func (r *ItemRepository) GetAllByBoardID(ctx context.Context, boardID uuid.UUID) ([]domain.Item, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth
+			FROM items
+			WHERE board_id = $1 AND parent_id IS NULL
+
+			UNION ALL
+
+			SELECT c.id, tree.depth + 1
+			FROM items c
+			JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT i.id, i.board_id, i.parent_id, i.title, COALESCE(i.content, ''), i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at
+		FROM tree
+		JOIN items i ON i.id = tree.id
+		ORDER BY tree.depth ASC, i.position ASC, i.created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.Item
+	for rows.Next() {
+		var item domain.Item
+		if err := rows.Scan(
+			&item.ID,
+			&item.BoardID,
+			&item.ParentID,
+			&item.Title,
+			&item.Content,
+			&item.Status,
+			&item.Position,
+			&item.DueDate,
+			&item.CompletedAt,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
 func (r *ItemRepository) GetWithChildren(ctx context.Context, id uuid.UUID) (*domain.Item, error) {
 	item, err := r.GetByID(ctx, id)
 	if err != nil {
@@ -296,20 +349,127 @@ func (r *ItemRepository) Complete(ctx context.Context, id uuid.UUID, completed b
 	return nil
 }
 
-func (r *ItemRepository) GetOverdueTasks(ctx context.Context) ([]domain.OverdueTask, error) {
+// CompleteRecurring completes the item and creates its next occurrence in one
+// transaction. Concurrent calls serialize on the item's row, so only the first
+// one finds it open.
+func (r *ItemRepository) CompleteRecurring(ctx context.Context, id uuid.UUID, next *domain.Item, reminders []domain.Reminder) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE items
+		SET status = 'completed', completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status <> 'completed'
+		RETURNING COALESCE(metadata ? 'next_occurrence_id', false)
+	`
+
+	var spawned bool
+	if err := tx.QueryRow(ctx, query, id).Scan(&spawned); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return false, err
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM items WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, domain.ErrNotFound
+		}
+		return false, nil
+	}
+
+	// Reopened and completed again, the occurrence already exists
+	if spawned {
+		return false, tx.Commit(ctx)
+	}
+
+	query = `
+		INSERT INTO items (board_id, parent_id, title, content, status, position, due_date, metadata)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, (SELECT COALESCE(MAX(position), 0) + 1 FROM items WHERE board_id = $1 AND parent_id IS NOT DISTINCT FROM $2)), $7, $8)
+		RETURNING id, position, created_at, updated_at
+	`
+
+	create := func(item *domain.Item) error {
+		var position *int
+		if item.Position > 0 {
+			position = &item.Position
+		}
+
+		metadata := item.Metadata
+		if metadata == nil {
+			metadata = []byte("{}")
+		}
+
+		return tx.QueryRow(ctx, query,
+			item.BoardID,
+			item.ParentID,
+			item.Title,
+			item.Content,
+			item.Status,
+			position,
+			item.DueDate,
+			metadata,
+		).Scan(&item.ID, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	}
+
+	if err := create(next); err != nil {
+		return false, err
+	}
+	for i := range next.Children {
+		next.Children[i].ParentID = &next.ID
+		if err := create(&next.Children[i]); err != nil {
+			return false, err
+		}
+	}
+
+	for i := range reminders {
+		reminder := &reminders[i]
+		reminder.ItemID = next.ID
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO reminders (user_id, item_id, remind_at, message, relative_offset)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		`,
+			reminder.UserID,
+			reminder.ItemID,
+			reminder.RemindAt,
+			reminder.Message,
+			reminder.Offset,
+		).Scan(&reminder.ID, &reminder.CreatedAt); err != nil {
+			return false, err
+		}
+	}
+
+	query = `
+		UPDATE items
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'), '{next_occurrence_id}', to_jsonb($2::text))
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, query, id, next.ID.String()); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// GetOverdueTasks returns the user's open items whose due date has passed, with their board names
+func (r *ItemRepository) GetOverdueTasks(ctx context.Context, userID int64) ([]domain.OverdueTask, error) {
 	query := `
 		SELECT f.user_id, i.id, i.title, i.due_date, b.name
 		FROM items i
 		JOIN boards b ON i.board_id = b.id
 		JOIN folders f ON b.folder_id = f.id
-		JOIN users u ON f.user_id = u.id
-		WHERE i.due_date < NOW()
-		  AND i.status != 'completed'
-		  AND u.notification_enabled = true
+		WHERE f.user_id = $1
+		  AND i.due_date < NOW()
+		  AND i.status NOT IN ('completed', 'archived')
 		ORDER BY i.due_date ASC
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -333,6 +493,53 @@ func (r *ItemRepository) GetOverdueTasks(ctx context.Context) ([]domain.OverdueT
 	return tasks, rows.Err()
 }
 
+// GetDueSoonTasks returns open items of users with notifications enabled that
+// are due within the user's due-soon window and were not announced for that due date yet
+func (r *ItemRepository) GetDueSoonTasks(ctx context.Context) ([]domain.DueSoonTask, error) {
+	query := `
+		SELECT f.user_id, u.timezone, i.id, i.title, i.due_date, b.name
+		FROM items i
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		JOIN users u ON f.user_id = u.id
+		WHERE u.notification_enabled = true
+		  AND u.due_soon_minutes > 0
+		  AND i.status NOT IN ('completed', 'archived')
+		  AND i.due_date > NOW()
+		  AND i.due_date <= NOW() + make_interval(mins => u.due_soon_minutes)
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM due_soon_notifications d
+			WHERE d.item_id = i.id AND d.due_date = i.due_date
+		  )
+		ORDER BY i.due_date ASC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []domain.DueSoonTask
+	for rows.Next() {
+		var task domain.DueSoonTask
+		if err := rows.Scan(
+			&task.UserID,
+			&task.Timezone,
+			&task.ItemID,
+			&task.Title,
+			&task.DueDate,
+			&task.BoardName,
+		); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
 func (r *ItemRepository) GetDueSoon(ctx context.Context, userID int64, within time.Duration) ([]domain.Item, error) {
 	query := `
 		SELECT i.id, i.board_id, i.parent_id, i.title, i.content, i.status, i.position,
@@ -421,6 +628,27 @@ func (r *ItemRepository) CountOverdueByUserID(ctx context.Context, userID int64)
 	return count, err
 }
 
+// CountOverdueAt counts the user's items that were overdue at the given time:
+// due before it and not completed by then. Archived items are left out.
+func (r *ItemRepository) CountOverdueAt(ctx context.Context, userID int64, at time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM items i
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		  AND i.due_date < $2
+		  AND i.created_at <= $2
+		  AND (i.completed_at IS NULL OR i.completed_at > $2)
+		  AND i.status != 'archived'
+		  AND b.type NOT IN ('calendar', 'habit_tracker')
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, userID, at).Scan(&count)
+	return count, err
+}
+
 func (r *ItemRepository) GetBoardOwner(ctx context.Context, boardID uuid.UUID) (int64, error) {
 	query := `
 		SELECT f.user_id
@@ -441,32 +669,34 @@ func (r *ItemRepository) GetBoardOwner(ctx context.Context, boardID uuid.UUID) (
 	return userID, nil
 }
 
-func (r *ItemRepository) GetCompletionStats(ctx context.Context, userID int64, days int) ([]domain.CompletionStats, error) {
+// GetCompletionStats counts the items completed and created on each of the
+// last days calendar days (including today) in the given timezone
+func (r *ItemRepository) GetCompletionStats(ctx context.Context, userID int64, days int, loc *time.Location) ([]domain.CompletionStats, error) {
 	query := `
 		WITH dates AS (
 			SELECT generate_series(
-				CURRENT_DATE - ($2 - 1) * INTERVAL '1 day',
-				CURRENT_DATE,
+				(NOW() AT TIME ZONE $3)::date - ($2 - 1) * INTERVAL '1 day',
+				(NOW() AT TIME ZONE $3)::date,
 				INTERVAL '1 day'
 			)::date AS date
 		),
 		completed AS (
-			SELECT DATE(i.completed_at) AS date, COUNT(*) AS count
+			SELECT DATE(i.completed_at AT TIME ZONE $3) AS date, COUNT(*) AS count
 			FROM items i
 			JOIN boards b ON i.board_id = b.id
 			JOIN folders f ON b.folder_id = f.id
 			WHERE f.user_id = $1
-			  AND i.completed_at >= CURRENT_DATE - ($2 - 1) * INTERVAL '1 day'
-			GROUP BY DATE(i.completed_at)
+			  AND DATE(i.completed_at AT TIME ZONE $3) >= (NOW() AT TIME ZONE $3)::date - ($2 - 1)
+			GROUP BY DATE(i.completed_at AT TIME ZONE $3)
 		),
 		created AS (
-			SELECT DATE(i.created_at) AS date, COUNT(*) AS count
+			SELECT DATE(i.created_at AT TIME ZONE $3) AS date, COUNT(*) AS count
 			FROM items i
 			JOIN boards b ON i.board_id = b.id
 			JOIN folders f ON b.folder_id = f.id
 			WHERE f.user_id = $1
-			  AND i.created_at >= CURRENT_DATE - ($2 - 1) * INTERVAL '1 day'
-			GROUP BY DATE(i.created_at)
+			  AND DATE(i.created_at AT TIME ZONE $3) >= (NOW() AT TIME ZONE $3)::date - ($2 - 1)
+			GROUP BY DATE(i.created_at AT TIME ZONE $3)
 		)
 		SELECT d.date::text, COALESCE(c.count, 0), COALESCE(cr.count, 0)
 		FROM dates d
@@ -475,7 +705,7 @@ func (r *ItemRepository) GetCompletionStats(ctx context.Context, userID int64, d
 		ORDER BY d.date ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, userID, days)
+	rows, err := r.db.Query(ctx, query, userID, days, loc.String())
 	if err != nil {
 		return nil, err
 	}
@@ -492,3 +722,91 @@ func (r *ItemRepository) GetCompletionStats(ctx context.Context, userID int64, d
 
 	return stats, rows.Err()
 }
+
+// GetScheduledTasks returns the user's dated tasks outside of calendar boards,
+// skipping ones completed more than 30 days ago
+func (r *ItemRepository) GetScheduledTasks(ctx context.Context, userID int64, excludeBoardID uuid.UUID) ([]domain.Item, error) {
+	query := `
+		SELECT i.id, i.board_id, i.parent_id, i.title, i.content, i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at
+		FROM items i
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		  AND i.board_id != $2
+		  AND b.type != 'calendar'
+		  AND i.due_date IS NOT NULL
+		  AND i.status != 'archived'
+		  AND (i.status != 'completed' OR i.completed_at > NOW() - INTERVAL '30 days')
+		ORDER BY i.due_date ASC
+	`
+
+	return r.queryItems(ctx, query, userID, excludeBoardID)
+}
+
+// GetDueBetween returns the user's open items due in [from, to) across all boards
+func (r *ItemRepository) GetDueBetween(ctx context.Context, userID int64, from, to time.Time) ([]domain.Item, error) {
+	query := `
+		SELECT i.id, i.board_id, i.parent_id, i.title, i.content, i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at
+		FROM items i
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		  AND i.due_date >= $2 AND i.due_date < $3
+		  AND i.status NOT IN ('completed', 'archived')
+		ORDER BY i.due_date ASC
+	`
+
+	return r.queryItems(ctx, query, userID, from, to)
+}
+
+// GetOverdueByUserID returns the user's open items whose due date has passed
+func (r *ItemRepository) GetOverdueByUserID(ctx context.Context, userID int64) ([]domain.Item, error) {
+	query := `
+		SELECT i.id, i.board_id, i.parent_id, i.title, i.content, i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at
+		FROM items i
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		WHERE f.user_id = $1
+		  AND i.due_date < NOW()
+		  AND i.status NOT IN ('completed', 'archived')
+		ORDER BY i.due_date ASC
+	`
+
+	return r.queryItems(ctx, query, userID)
+}
+
+// queryItems runs a query selecting the full item column list
+func (r *ItemRepository) queryItems(ctx context.Context, query string, args ...interface{}) ([]domain.Item, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.Item
+	for rows.Next() {
+		var item domain.Item
+		if err := rows.Scan(
+			&item.ID,
+			&item.BoardID,
+			&item.ParentID,
+			&item.Title,
+			&item.Content,
+			&item.Status,
+			&item.Position,
+			&item.DueDate,
+			&item.CompletedAt,
+			&item.Metadata,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobLocker implements repository.JobLocker with Postgres session-level
+// advisory locks. The lock is held by a pooled connection for the duration of
+// the job; if the instance dies, the session ends and the lock is released.
+type JobLocker struct {
+	db *pgxpool.Pool
+}
+
+func NewJobLocker(db *pgxpool.Pool) *JobLocker {
+	return &JobLocker{db: db}
+}
+
+func (l *JobLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var locked bool
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, name).Scan(&locked)
+	if err != nil || !locked {
+		conn.Release()
+		return nil, false, err
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock(hashtextextended($1, 0))`, name); err != nil {
+			// Ending the session releases the lock; the pool drops the closed connection
+			_ = conn.Conn().Close(ctx)
+		}
+		conn.Release()
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const outboxColumns = `
+	id, user_id, kind, reminder_id, item_id, payload, status, attempts,
+	COALESCE(last_error, ''), next_attempt_at, sent_at, created_at, updated_at
+`
+
+func (r *OutboxRepository) Enqueue(ctx context.Context, entry *domain.OutboxEntry) error {
+	query := `
+		INSERT INTO notification_outbox (user_id, kind, reminder_id, item_id, payload, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()))
+		RETURNING ` + outboxColumns
+
+	row := r.db.QueryRow(ctx, query,
+		entry.UserID,
+		entry.Kind,
+		entry.ReminderID,
+		entry.ItemID,
+		entry.Payload,
+		nextAttemptAt(entry),
+	)
+
+	return scanOutboxEntry(row, entry)
+}
+
+func (r *OutboxRepository) EnqueueReminder(ctx context.Context, entry *domain.OutboxEntry) (bool, error) {
+	// Marking the reminder and inserting the entry in one statement makes them
+	// a single transaction: a crash leaves either both or neither
+	query := `
+		WITH marked AS (
+			UPDATE reminders
+			SET sent = true, sent_at = NOW()
+			WHERE id = $3 AND sent = false
+			RETURNING id
+		)
+		INSERT INTO notification_outbox (user_id, kind, reminder_id, item_id, payload, next_attempt_at)
+		SELECT $1, $2, marked.id, $4, $5, COALESCE($6, NOW())
+		FROM marked
+		RETURNING ` + outboxColumns
+
+	row := r.db.QueryRow(ctx, query,
+		entry.UserID,
+		entry.Kind,
+		entry.ReminderID,
+		entry.ItemID,
+		entry.Payload,
+		nextAttemptAt(entry),
+	)
+
+	if err := scanOutboxEntry(row, entry); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxEntry, error) {
+	query := `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2),
+		    updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.OutboxEntry
+	for rows.Next() {
+		var entry domain.OutboxEntry
+		if err := scanOutboxEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE notification_outbox
+		SET status = 'sent', sent_at = NOW(), last_error = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, query, id)
+}
+
+// MarkFailed records a failed attempt and schedules the next one
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt time.Time) error {
+	query := `
+		UPDATE notification_outbox
+		SET last_error = $2, next_attempt_at = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, query, id, lastError, retryAt)
+}
+
+// Hold postpones a claimed entry, for instance to the end of quiet hours,
+// and gives back the attempt that claiming it counted
+func (r *OutboxRepository) Hold(ctx context.Context, id uuid.UUID, until time.Time) error {
+	query := `
+		UPDATE notification_outbox
+		SET attempts = GREATEST(attempts - 1, 0), next_attempt_at = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, query, id, until)
+}
+
+// MarkDead records the last error and stops retrying the entry
+func (r *OutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE notification_outbox
+		SET status = 'dead', last_error = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, query, id, lastError)
+}
+
+// GetFailedByUserID returns the user's dead entries and pending entries that
+// failed at least once, newest first
+func (r *OutboxRepository) GetFailedByUserID(ctx context.Context, userID int64, limit int) ([]domain.OutboxEntry, error) {
+	query := `
+		SELECT ` + outboxColumns + `
+		FROM notification_outbox
+		WHERE user_id = $1
+		  AND (status = 'dead' OR (status = 'pending' AND last_error IS NOT NULL))
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.OutboxEntry
+	for rows.Next() {
+		var entry domain.OutboxEntry
+		if err := scanOutboxEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM notification_outbox
+		WHERE status = 'sent' AND sent_at < $1
+	`
+
+	result, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *OutboxRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// nextAttemptAt returns when a new entry is due, nil for right away
+func nextAttemptAt(entry *domain.OutboxEntry) *time.Time {
+	if entry.NextAttemptAt.IsZero() {
+		return nil
+	}
+	return &entry.NextAttemptAt
+}
+
+func scanOutboxEntry(row pgx.Row, entry *domain.OutboxEntry) error {
+	return row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.Kind,
+		&entry.ReminderID,
+		&entry.ItemID,
+		&entry.Payload,
+		&entry.Status,
+		&entry.Attempts,
+		&entry.LastError,
+		&entry.NextAttemptAt,
+		&entry.SentAt,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+}
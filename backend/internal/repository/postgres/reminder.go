@@ -21,7 +21,7 @@ func NewReminderRepository(db *pgxpool.Pool) *ReminderRepository {
 
 func (r *ReminderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Reminder, error) {
 	query := `
-		SELECT id, user_id, item_id, remind_at, message, sent, sent_at, created_at
+		SELECT id, user_id, item_id, remind_at, message, sent, sent_at, created_at, relative_offset
 		FROM reminders
 		WHERE id = $1
 	`
@@ -36,6 +36,7 @@ func (r *ReminderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 		&reminder.Sent,
 		&reminder.SentAt,
 		&reminder.CreatedAt,
+		&reminder.Offset,
 	)
 
 	if err != nil {
@@ -50,7 +51,7 @@ func (r *ReminderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 
 func (r *ReminderRepository) GetByItemID(ctx context.Context, itemID uuid.UUID) ([]domain.Reminder, error) {
 	query := `
-		SELECT id, user_id, item_id, remind_at, message, sent, sent_at, created_at
+		SELECT id, user_id, item_id, remind_at, message, sent, sent_at, created_at, relative_offset
 		FROM reminders
 		WHERE item_id = $1
 		ORDER BY remind_at ASC
@@ -74,6 +75,7 @@ func (r *ReminderRepository) GetByItemID(ctx context.Context, itemID uuid.UUID)
 			&reminder.Sent,
 			&reminder.SentAt,
 			&reminder.CreatedAt,
+			&reminder.Offset,
 		); err != nil {
 			return nil, err
 		}
@@ -131,10 +133,74 @@ func (r *ReminderRepository) GetPending(ctx context.Context, before time.Time) (
 	return reminders, rows.Err()
 }
 
+// ClaimPending leases up to limit due reminders of users with notifications
+// enabled. Rows locked or leased by another instance are skipped, so concurrent
+// callers never get the same reminder until its lease expires.
+func (r *ReminderRepository) ClaimPending(ctx context.Context, before time.Time, limit int, lease time.Duration) ([]domain.Reminder, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE reminders
+			SET claimed_until = NOW() + make_interval(secs => $3)
+			WHERE id IN (
+				SELECT r.id
+				FROM reminders r
+				JOIN users u ON r.user_id = u.id
+				WHERE r.sent = false
+				  AND r.remind_at <= $1
+				  AND (r.claimed_until IS NULL OR r.claimed_until < NOW())
+				  AND u.notification_enabled = true
+				ORDER BY r.remind_at ASC
+				LIMIT $2
+				FOR UPDATE OF r SKIP LOCKED
+			)
+			RETURNING id, user_id, item_id, remind_at, message, sent, sent_at, created_at
+		)
+		SELECT c.id, c.user_id, c.item_id, c.remind_at, c.message, c.sent, c.sent_at, c.created_at,
+		       i.id, i.board_id, i.title, i.content, i.status, i.due_date
+		FROM claimed c
+		JOIN items i ON c.item_id = i.id
+		ORDER BY c.remind_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []domain.Reminder
+	for rows.Next() {
+		var reminder domain.Reminder
+		var item domain.Item
+		if err := rows.Scan(
+			&reminder.ID,
+			&reminder.UserID,
+			&reminder.ItemID,
+			&reminder.RemindAt,
+			&reminder.Message,
+			&reminder.Sent,
+			&reminder.SentAt,
+			&reminder.CreatedAt,
+			&item.ID,
+			&item.BoardID,
+			&item.Title,
+			&item.Content,
+			&item.Status,
+			&item.DueDate,
+		); err != nil {
+			return nil, err
+		}
+		reminder.Item = &item
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
 func (r *ReminderRepository) Create(ctx context.Context, reminder *domain.Reminder) error {
 	query := `
-		INSERT INTO reminders (user_id, item_id, remind_at, message)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO reminders (user_id, item_id, remind_at, message, relative_offset)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
 
@@ -143,6 +209,7 @@ func (r *ReminderRepository) Create(ctx context.Context, reminder *domain.Remind
 		reminder.ItemID,
 		reminder.RemindAt,
 		reminder.Message,
+		reminder.Offset,
 	).Scan(&reminder.ID, &reminder.CreatedAt)
 
 	return err
@@ -151,7 +218,7 @@ func (r *ReminderRepository) Create(ctx context.Context, reminder *domain.Remind
 func (r *ReminderRepository) Update(ctx context.Context, reminder *domain.Reminder) error {
 	query := `
 		UPDATE reminders
-		SET remind_at = $2, message = $3
+		SET remind_at = $2, message = $3, claimed_until = NULL
 		WHERE id = $1
 	`
 
@@ -212,6 +279,13 @@ func (r *ReminderRepository) DeleteByItemID(ctx context.Context, itemID uuid.UUI
 	return err
 }
 
+// DeleteRelativeByItemID deletes the item's reminders that are relative to its due date
+func (r *ReminderRepository) DeleteRelativeByItemID(ctx context.Context, itemID uuid.UUID) error {
+	query := `DELETE FROM reminders WHERE item_id = $1 AND relative_offset IS NOT NULL`
+	_, err := r.db.Exec(ctx, query, itemID)
+	return err
+}
+
 // ActivityLogRepository
 
 type ActivityLogRepository struct {
@@ -302,6 +376,40 @@ func (r *ActivityLogRepository) GetLastActivity(ctx context.Context, userID int6
 	return &log, nil
 }
 
+// CountByBoard counts the item activity of the user in [from, to) per board
+// and returns the limit most active boards
+func (r *ActivityLogRepository) CountByBoard(ctx context.Context, userID int64, from, to time.Time, limit int) ([]domain.BoardActivity, error) {
+	query := `
+		SELECT b.id, b.name, COUNT(*) AS cnt
+		FROM activity_log a
+		JOIN items i ON a.entity_id = i.id
+		JOIN boards b ON i.board_id = b.id
+		WHERE a.user_id = $1
+		  AND a.entity_type = 'item'
+		  AND a.created_at >= $2 AND a.created_at < $3
+		GROUP BY b.id, b.name
+		ORDER BY cnt DESC, b.name ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var boards []domain.BoardActivity
+	for rows.Next() {
+		var board domain.BoardActivity
+		if err := rows.Scan(&board.BoardID, &board.Name, &board.Count); err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+
+	return boards, rows.Err()
+}
+
 // HabitCompletionRepository
 
 type HabitCompletionRepository struct {
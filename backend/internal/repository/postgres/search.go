@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+// titleHeadline highlights every match in a title; snippetHeadline picks up
+// to two short excerpts of the content around the matches
+var (
+	titleHeadline = fmt.Sprintf(`StartSel=%s, StopSel=%s, HighlightAll=true`,
+		domain.SearchMatchStart, domain.SearchMatchStop)
+	snippetHeadline = fmt.Sprintf(`StartSel=%s, StopSel=%s, MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" … "`,
+		domain.SearchMatchStart, domain.SearchMatchStop)
+)
+
+type SearchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSearchRepository(db *pgxpool.Pool) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+func (r *SearchRepository) Search(ctx context.Context, userID int64, query domain.SearchQuery) ([]domain.SearchHit, int, error) {
+	// The query is parsed with both configurations, like the search vector.
+	// Headlines are only built for the page of hits, with the configuration
+	// that matched the text.
+	sql := `
+		WITH q AS (
+			SELECT websearch_to_tsquery('russian', $2) AS ru,
+			       websearch_to_tsquery('english', $2) AS en
+		),
+		hits AS (
+			SELECT i.id,
+			       ts_rank_cd(i.search_vector, q.ru || q.en) AS rank,
+			       COUNT(*) OVER () AS total
+			FROM items i
+			JOIN boards b ON i.board_id = b.id
+			JOIN folders f ON b.folder_id = f.id
+			CROSS JOIN q
+			WHERE f.user_id = $1
+			  AND i.search_vector @@ (q.ru || q.en)
+			  AND ($3::text[] IS NULL OR b.type::text = ANY($3::text[]))
+			  AND ($4::text[] IS NULL OR i.status = ANY($4::text[]))
+			ORDER BY rank DESC, i.updated_at DESC, i.id
+			LIMIT $5 OFFSET $6
+		)
+		SELECT i.id, i.board_id, i.parent_id, i.title, COALESCE(i.content, ''), i.status, i.position,
+		       i.due_date, i.completed_at, i.metadata, i.created_at, i.updated_at,
+		       b.name, b.type, f.id, f.name, hits.rank, hits.total,
+		       CASE WHEN to_tsvector('russian', i.title) @@ q.ru
+		            THEN ts_headline('russian', i.title, q.ru, $7)
+		            ELSE ts_headline('english', i.title, q.en, $7)
+		       END,
+		       CASE WHEN to_tsvector('russian', COALESCE(i.content, '')) @@ q.ru
+		            THEN ts_headline('russian', i.content, q.ru, $8)
+		            WHEN to_tsvector('english', COALESCE(i.content, '')) @@ q.en
+		            THEN ts_headline('english', i.content, q.en, $8)
+		            ELSE ''
+		       END
+		FROM hits
+		JOIN items i ON i.id = hits.id
+		JOIN boards b ON i.board_id = b.id
+		JOIN folders f ON b.folder_id = f.id
+		CROSS JOIN q
+		ORDER BY hits.rank DESC, i.updated_at DESC, i.id
+	`
+
+	var boardTypes, statuses []string
+	for _, t := range query.BoardTypes {
+		boardTypes = append(boardTypes, string(t))
+	}
+	for _, s := range query.Statuses {
+		statuses = append(statuses, string(s))
+	}
+
+	rows, err := r.db.Query(ctx, sql,
+		userID,
+		query.Text,
+		boardTypes,
+		statuses,
+		query.Limit,
+		query.Offset,
+		titleHeadline,
+		snippetHeadline,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var hits []domain.SearchHit
+	total := 0
+	for rows.Next() {
+		var hit domain.SearchHit
+		if err := rows.Scan(
+			&hit.Item.ID,
+			&hit.Item.BoardID,
+			&hit.Item.ParentID,
+			&hit.Item.Title,
+			&hit.Item.Content,
+			&hit.Item.Status,
+			&hit.Item.Position,
+			&hit.Item.DueDate,
+			&hit.Item.CompletedAt,
+			&hit.Item.Metadata,
+			&hit.Item.CreatedAt,
+			&hit.Item.UpdatedAt,
+			&hit.BoardName,
+			&hit.BoardType,
+			&hit.FolderID,
+			&hit.FolderName,
+			&hit.Rank,
+			&total,
+			&hit.TitleHighlight,
+			&hit.Snippet,
+		); err != nil {
+			return nil, 0, err
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, total, rows.Err()
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UpdateOffsetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUpdateOffsetRepository(db *pgxpool.Pool) *UpdateOffsetRepository {
+	return &UpdateOffsetRepository{db: db}
+}
+
+// LoadOffset returns the stored offset, or 0 when polling has not run yet
+func (r *UpdateOffsetRepository) LoadOffset(ctx context.Context, botID int64) (int64, error) {
+	query := `SELECT update_offset FROM telegram_update_offsets WHERE bot_id = $1`
+
+	var offset int64
+	err := r.db.QueryRow(ctx, query, botID).Scan(&offset)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return offset, nil
+}
+
+func (r *UpdateOffsetRepository) SaveOffset(ctx context.Context, botID int64, offset int64) error {
+	query := `
+		INSERT INTO telegram_update_offsets (bot_id, update_offset, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (bot_id) DO UPDATE SET
+			update_offset = EXCLUDED.update_offset,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(ctx, query, botID, offset)
+	return err
+}
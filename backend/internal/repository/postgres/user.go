@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/telegram-task-manager/backend/internal/domain"
@@ -21,7 +22,8 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	query := `
 		SELECT id, username, first_name, last_name, language_code,
-		       notification_enabled, reminder_hours, timezone, last_active_at, created_at, updated_at
+		       notification_enabled, reminder_hours, timezone, inbox_board_id, due_soon_minutes,
+		       quiet_hours, digest_time, weekly_report, last_active_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -36,6 +38,11 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, e
 		&user.NotificationEnabled,
 		&user.ReminderHours,
 		&user.Timezone,
+		&user.InboxBoardID,
+		&user.DueSoonMinutes,
+		&user.QuietHours,
+		&user.DigestTime,
+		&user.WeeklyReport,
 		&user.LastActiveAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -55,6 +62,11 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, e
 		ReminderHours:       user.ReminderHours,
 		LanguageCode:        user.LanguageCode,
 		Timezone:            user.Timezone,
+		InboxBoardID:        user.InboxBoardID,
+		DueSoonMinutes:      &user.DueSoonMinutes,
+		QuietHours:          user.QuietHours,
+		DigestTime:          user.DigestTime,
+		WeeklyReport:        &user.WeeklyReport,
 	}
 
 	return &user, nil
@@ -127,11 +139,25 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// UpdateSettings saves the settings in one statement. Optional settings that
+// are nil keep their values. Returns ErrInvalidInput, without saving anything,
+// when the inbox board does not belong to the user.
 func (r *UserRepository) UpdateSettings(ctx context.Context, userID int64, settings *domain.UserSettings) error {
 	query := `
 		UPDATE users
-		SET notification_enabled = $2, reminder_hours = $3, language_code = $4, timezone = $5, updated_at = NOW()
+		SET notification_enabled = $2, reminder_hours = $3, language_code = $4, timezone = $5,
+		    due_soon_minutes = COALESCE($6, due_soon_minutes), weekly_report = COALESCE($7, weekly_report),
+		    inbox_board_id = CASE WHEN $8 THEN $9::uuid ELSE inbox_board_id END,
+		    quiet_hours = CASE WHEN $10 THEN $11::jsonb ELSE quiet_hours END,
+		    digest_time = CASE WHEN $12 THEN $13::varchar ELSE digest_time END,
+		    updated_at = NOW()
 		WHERE id = $1
+		  AND ($9::uuid IS NULL OR EXISTS (
+			SELECT 1
+			FROM boards b
+			JOIN folders f ON b.folder_id = f.id
+			WHERE b.id = $9 AND f.user_id = $1
+		  ))
 	`
 
 	timezone := settings.Timezone
@@ -139,12 +165,35 @@ func (r *UserRepository) UpdateSettings(ctx context.Context, userID int64, setti
 		timezone = "UTC"
 	}
 
+	// The nil UUID, quiet hours without a window and an empty digest time
+	// clear the setting
+	inboxBoardID := settings.InboxBoardID
+	if inboxBoardID != nil && *inboxBoardID == uuid.Nil {
+		inboxBoardID = nil
+	}
+	quietHours := settings.QuietHours
+	if quietHours != nil && quietHours.IsOff() {
+		quietHours = nil
+	}
+	digestTime := settings.DigestTime
+	if digestTime != nil && *digestTime == "" {
+		digestTime = nil
+	}
+
 	result, err := r.db.Exec(ctx, query,
 		userID,
 		settings.NotificationEnabled,
 		settings.ReminderHours,
 		settings.LanguageCode,
 		timezone,
+		settings.DueSoonMinutes,
+		settings.WeeklyReport,
+		settings.InboxBoardID != nil,
+		inboxBoardID,
+		settings.QuietHours != nil,
+		quietHours,
+		settings.DigestTime != nil,
+		digestTime,
 	)
 
 	if err != nil {
@@ -152,12 +201,94 @@ func (r *UserRepository) UpdateSettings(ctx context.Context, userID int64, setti
 	}
 
 	if result.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrInvalidInput
+		}
 		return domain.ErrNotFound
 	}
 
 	return nil
 }
 
+// MarkDigestSent records the digest of the given local date and reports false
+// when it was recorded already
+func (r *UserRepository) MarkDigestSent(ctx context.Context, userID int64, date time.Time) (bool, error) {
+	query := `
+		UPDATE users
+		SET digest_sent_on = $2
+		WHERE id = $1 AND (digest_sent_on IS NULL OR digest_sent_on < $2)
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// UnmarkDigestSent forgets the digest of the given local date, so it is sent
+// on the next run
+func (r *UserRepository) UnmarkDigestSent(ctx context.Context, userID int64, date time.Time) error {
+	query := `UPDATE users SET digest_sent_on = NULL WHERE id = $1 AND digest_sent_on = $2`
+	_, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	return err
+}
+
+// MarkWeeklyReportSent records the weekly report of the given local date and
+// reports false when it was recorded already
+func (r *UserRepository) MarkWeeklyReportSent(ctx context.Context, userID int64, date time.Time) (bool, error) {
+	query := `
+		UPDATE users
+		SET weekly_report_sent_on = $2
+		WHERE id = $1 AND (weekly_report_sent_on IS NULL OR weekly_report_sent_on < $2)
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// UnmarkWeeklyReportSent forgets the weekly report of the given local date,
+// so it is sent on the next run
+func (r *UserRepository) UnmarkWeeklyReportSent(ctx context.Context, userID int64, date time.Time) error {
+	query := `UPDATE users SET weekly_report_sent_on = NULL WHERE id = $1 AND weekly_report_sent_on = $2`
+	_, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	return err
+}
+
+// MarkOverdueNotified records the overdue notification of the given local date
+// and reports false when it was recorded already
+func (r *UserRepository) MarkOverdueNotified(ctx context.Context, userID int64, date time.Time) (bool, error) {
+	query := `
+		UPDATE users
+		SET overdue_notified_on = $2
+		WHERE id = $1 AND (overdue_notified_on IS NULL OR overdue_notified_on < $2)
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// UnmarkOverdueNotified forgets the overdue notification of the given local
+// date, so it is sent on the next run
+func (r *UserRepository) UnmarkOverdueNotified(ctx context.Context, userID int64, date time.Time) error {
+	query := `UPDATE users SET overdue_notified_on = NULL WHERE id = $1 AND overdue_notified_on = $2`
+	_, err := r.db.Exec(ctx, query, userID, date.Format("2006-01-02"))
+	return err
+}
+
 func (r *UserRepository) UpdateLastActive(ctx context.Context, userID int64) error {
 	query := `
 		UPDATE users
@@ -169,6 +300,18 @@ func (r *UserRepository) UpdateLastActive(ctx context.Context, userID int64) err
 	return err
 }
 
+// DisableNotifications turns notifications off, e.g. after the user blocked the bot
+func (r *UserRepository) DisableNotifications(ctx context.Context, userID int64) error {
+	query := `
+		UPDATE users
+		SET notification_enabled = false, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
 func (r *UserRepository) GetInactiveUsers(ctx context.Context, since time.Time) ([]domain.InactiveUser, error) {
 	query := `
 		SELECT id, last_active_at,
@@ -243,3 +386,149 @@ func (r *UserRepository) GetUsersForReminderHour(ctx context.Context, hour int)
 
 	return users, rows.Err()
 }
+
+// GetUsersForDigest returns users with notifications enabled whose digest time
+// passed less than catchUp ago today in their timezone and who have not
+// received today's digest yet
+func (r *UserRepository) GetUsersForDigest(ctx context.Context, catchUp time.Duration) ([]domain.User, error) {
+	query := `
+		SELECT id, username, first_name, last_name, language_code,
+		       notification_enabled, reminder_hours, timezone, digest_time, last_active_at, created_at, updated_at
+		FROM (
+			SELECT *, NOW() AT TIME ZONE COALESCE(NULLIF(timezone, ''), 'UTC') AS local_now
+			FROM users
+			WHERE notification_enabled = true AND digest_time IS NOT NULL
+		) u
+		WHERE local_now::time >= digest_time::time
+		  AND local_now::time - digest_time::time < make_interval(secs => $1)
+		  AND (digest_sent_on IS NULL OR digest_sent_on < local_now::date)
+	`
+
+	rows, err := r.db.Query(ctx, query, catchUp.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.LanguageCode,
+			&user.NotificationEnabled,
+			&user.ReminderHours,
+			&user.Timezone,
+			&user.DigestTime,
+			&user.LastActiveAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		user.TelegramID = user.ID
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// GetUsersForWeeklyReport returns users who opted in to the weekly report and
+// for whom it is the given weekday, at or after the given hour, in their
+// timezone, and who have not received the report today
+func (r *UserRepository) GetUsersForWeeklyReport(ctx context.Context, weekday time.Weekday, hour int) ([]domain.User, error) {
+	query := `
+		SELECT id, username, first_name, last_name, language_code,
+		       notification_enabled, reminder_hours, timezone, weekly_report, last_active_at, created_at, updated_at
+		FROM (
+			SELECT *, NOW() AT TIME ZONE COALESCE(NULLIF(timezone, ''), 'UTC') AS local_now
+			FROM users
+			WHERE notification_enabled = true AND weekly_report = true
+		) u
+		WHERE EXTRACT(DOW FROM local_now)::int = $1
+		  AND EXTRACT(HOUR FROM local_now)::int >= $2
+		  AND (weekly_report_sent_on IS NULL OR weekly_report_sent_on < local_now::date)
+	`
+
+	rows, err := r.db.Query(ctx, query, int(weekday), hour)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.LanguageCode,
+			&user.NotificationEnabled,
+			&user.ReminderHours,
+			&user.Timezone,
+			&user.WeeklyReport,
+			&user.LastActiveAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		user.TelegramID = user.ID
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// GetUsersForOverdueCheck returns users with notifications enabled for whom
+// the given hour started less than catchUp ago in their timezone and who were
+// not notified about overdue tasks today
+func (r *UserRepository) GetUsersForOverdueCheck(ctx context.Context, hour int, catchUp time.Duration) ([]domain.User, error) {
+	query := `
+		SELECT id, username, first_name, last_name, language_code,
+		       notification_enabled, reminder_hours, timezone, last_active_at, created_at, updated_at
+		FROM (
+			SELECT *, NOW() AT TIME ZONE COALESCE(NULLIF(timezone, ''), 'UTC') AS local_now
+			FROM users
+			WHERE notification_enabled = true
+		) u
+		WHERE local_now::time >= make_time($1, 0, 0)
+		  AND local_now::time - make_time($1, 0, 0) < make_interval(secs => $2)
+		  AND (overdue_notified_on IS NULL OR overdue_notified_on < local_now::date)
+	`
+
+	rows, err := r.db.Query(ctx, query, hour, catchUp.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.LanguageCode,
+			&user.NotificationEnabled,
+			&user.ReminderHours,
+			&user.Timezone,
+			&user.LastActiveAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		user.TelegramID = user.ID
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
@@ -11,54 +11,116 @@ import (
 	"github.com/telegram-task-manager/backend/internal/service"
 )
 
+const (
+	// reminderBatchSize and reminderLease bound one run of checkReminders
+	reminderBatchSize = 500
+	reminderLease     = 2 * time.Minute
+
+	// jobLockHold keeps the lock of a job that is not idempotent a little longer
+	// than the job runs, so an instance whose clock lags behind does not run the
+	// same tick again right after another instance finished it
+	jobLockHold = time.Minute
+
+	// Overdue tasks are announced in the user's local morning, or up to
+	// overdueCatchUp later, e.g. after downtime
+	overdueCheckHour = 9
+	overdueCatchUp   = 3 * time.Hour
+
+	// digestCatchUp is how late a digest may still be sent, e.g. after downtime
+	digestCatchUp = time.Hour
+
+	// Weekly reports go out on Sunday evenings in the user's timezone
+	weeklyReportDay  = time.Sunday
+	weeklyReportHour = 19
+)
+
 type ReminderScheduler struct {
 	cron            *cron.Cron
 	notificationSvc *service.NotificationService
+	digestSvc       *service.DigestService
+	reportSvc       *service.WeeklyReportService
 	userRepo        repository.UserRepository
 	itemRepo        repository.ItemRepository
 	reminderRepo    repository.ReminderRepository
+	dueSoonRepo     repository.DueSoonMarkerRepository
+	jobLocker       repository.JobLocker
 	logger          *slog.Logger
 }
 
 func NewReminderScheduler(
 	notificationSvc *service.NotificationService,
+	digestSvc *service.DigestService,
+	reportSvc *service.WeeklyReportService,
 	userRepo repository.UserRepository,
 	itemRepo repository.ItemRepository,
 	reminderRepo repository.ReminderRepository,
+	dueSoonRepo repository.DueSoonMarkerRepository,
+	jobLocker repository.JobLocker,
 	logger *slog.Logger,
 ) *ReminderScheduler {
 	return &ReminderScheduler{
 		cron:            cron.New(cron.WithSeconds()),
 		notificationSvc: notificationSvc,
+		digestSvc:       digestSvc,
+		reportSvc:       reportSvc,
 		userRepo:        userRepo,
 		itemRepo:        itemRepo,
 		reminderRepo:    reminderRepo,
+		dueSoonRepo:     dueSoonRepo,
+		jobLocker:       jobLocker,
 		logger:          logger,
 	}
 }
 
-// Start starts the scheduler
+// Start starts the scheduler. Every job is guarded by a lock shared by all
+// instances, so running several replicas does not send duplicate messages.
 func (s *ReminderScheduler) Start() error {
 	// Check reminders every 5 minutes
-	_, err := s.cron.AddFunc("0 */5 * * * *", s.checkReminders)
+	_, err := s.cron.AddFunc("0 */5 * * * *", s.singleton("check_reminders", 0, s.checkReminders))
 	if err != nil {
 		return err
 	}
 
 	// Check inactive users every hour
-	_, err = s.cron.AddFunc("0 0 * * * *", s.checkInactiveUsers)
+	_, err = s.cron.AddFunc("0 0 * * * *", s.singleton("check_inactive_users", jobLockHold, s.checkInactiveUsers))
+	if err != nil {
+		return err
+	}
+
+	// Check overdue tasks every 15 minutes, in each user's local morning
+	_, err = s.cron.AddFunc("0 */15 * * * *", s.singleton("check_overdue_tasks", jobLockHold, s.checkOverdueTasks))
+	if err != nil {
+		return err
+	}
+
+	// Check tasks due soon every 5 minutes, so short windows are not missed
+	_, err = s.cron.AddFunc("30 */5 * * * *", s.singleton("check_due_soon_tasks", jobLockHold, s.checkDueSoonTasks))
+	if err != nil {
+		return err
+	}
+
+	// Send morning digests every 5 minutes, at each user's local digest time
+	_, err = s.cron.AddFunc("15 */5 * * * *", s.singleton("send_digests", jobLockHold, s.sendDigests))
+	if err != nil {
+		return err
+	}
+
+	// Send weekly reports every 15 minutes, on Sunday evenings in each user's timezone
+	_, err = s.cron.AddFunc("45 */15 * * * *", s.singleton("send_weekly_reports", jobLockHold, s.sendWeeklyReports))
 	if err != nil {
 		return err
 	}
 
-	// Check overdue tasks daily at midnight
-	_, err = s.cron.AddFunc("0 0 0 * * *", s.checkOverdueTasks)
+	// Delete old sent notifications once a day
+	_, err = s.cron.AddFunc("0 30 3 * * *", s.singleton("purge_outbox", jobLockHold, s.purgeOutbox))
 	if err != nil {
 		return err
 	}
 
-	// Check tasks due soon every 30 minutes
-	_, err = s.cron.AddFunc("0 */30 * * * *", s.checkDueSoonTasks)
+	// Deliver queued notifications every 10 seconds, one batch at a time
+	_, err = s.cron.AddJob("*/10 * * * * *",
+		cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).
+			Then(cron.FuncJob(s.singleton("deliver_notifications", 0, s.deliverNotifications))))
 	if err != nil {
 		return err
 	}
@@ -76,16 +138,44 @@ func (s *ReminderScheduler) Stop() {
 	s.logger.Info("scheduler stopped")
 }
 
-// checkReminders sends pending reminders
+// singleton wraps a job so that it is skipped while another instance runs it.
+// The lock is kept for at least hold after the job started.
+func (s *ReminderScheduler) singleton(name string, hold time.Duration, job func()) func() {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		unlock, ok, err := s.jobLocker.TryLock(ctx, "scheduler:"+name)
+		cancel()
+		if err != nil {
+			s.logger.Error("failed to lock scheduled job", "job", name, "error", err)
+			return
+		}
+		if !ok {
+			s.logger.Debug("scheduled job is running on another instance", "job", name)
+			return
+		}
+
+		started := time.Now()
+		job()
+
+		if remaining := hold - time.Since(started); remaining > 0 {
+			time.AfterFunc(remaining, unlock)
+			return
+		}
+		unlock()
+	}
+}
+
+// checkReminders queues pending reminders for delivery. Reminders are claimed
+// with a lease, so instances running the job concurrently split them up.
 func (s *ReminderScheduler) checkReminders() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), reminderLease)
 	defer cancel()
 
 	s.logger.Debug("checking pending reminders")
 
-	reminders, err := s.reminderRepo.GetPending(ctx, time.Now())
+	reminders, err := s.reminderRepo.ClaimPending(ctx, time.Now(), reminderBatchSize, reminderLease)
 	if err != nil {
-		s.logger.Error("failed to get pending reminders", "error", err)
+		s.logger.Error("failed to claim pending reminders", "error", err)
 		return
 	}
 
@@ -98,8 +188,8 @@ func (s *ReminderScheduler) checkReminders() {
 			return
 		}
 
-		if err := s.notificationSvc.SendReminder(ctx, &reminder); err != nil {
-			s.logger.Error("failed to send reminder",
+		if err := s.notificationSvc.QueueReminder(ctx, &reminder); err != nil {
+			s.logger.Error("failed to queue reminder",
 				"reminder_id", reminder.ID,
 				"user_id", reminder.UserID,
 				"error", err,
@@ -145,8 +235,8 @@ func (s *ReminderScheduler) checkInactiveUsers() {
 			continue
 		}
 
-		if err := s.notificationSvc.SendInactivityReminder(ctx, user.TelegramID); err != nil {
-			s.logger.Error("failed to send inactivity reminder",
+		if err := s.notificationSvc.QueueInactivityReminder(ctx, user.TelegramID); err != nil {
+			s.logger.Error("failed to queue inactivity reminder",
 				"user_id", user.TelegramID,
 				"error", err,
 			)
@@ -156,88 +246,261 @@ func (s *ReminderScheduler) checkInactiveUsers() {
 		sent++
 	}
 
-	s.logger.Info("sent inactivity reminders", "count", sent)
+	s.logger.Info("queued inactivity reminders", "count", sent)
 }
 
-// checkOverdueTasks sends notifications about overdue tasks
+// checkOverdueTasks notifies users about their overdue tasks once a day, in
+// their local morning
 func (s *ReminderScheduler) checkOverdueTasks() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
 	s.logger.Debug("checking overdue tasks")
 
-	overdueTasks, err := s.itemRepo.GetOverdueTasks(ctx)
+	users, err := s.userRepo.GetUsersForOverdueCheck(ctx, overdueCheckHour, overdueCatchUp)
 	if err != nil {
-		s.logger.Error("failed to get overdue tasks", "error", err)
+		s.logger.Error("failed to get users for overdue check", "error", err)
 		return
 	}
 
-	s.logger.Info("found overdue tasks", "count", len(overdueTasks))
-
-	if len(overdueTasks) == 0 {
-		return
-	}
+	queued := 0
+	for i := range users {
+		if ctx.Err() != nil {
+			s.logger.Warn("overdue check cancelled due to timeout", "queued", queued)
+			return
+		}
 
-	// Group tasks by user
-	userTasks := make(map[int64][]struct {
-		ItemID    interface{}
-		Title     string
-		BoardName string
-	})
-
-	for _, task := range overdueTasks {
-		userTasks[task.UserID] = append(userTasks[task.UserID], struct {
-			ItemID    interface{}
-			Title     string
-			BoardName string
-		}{
-			ItemID:    task.ItemID,
-			Title:     task.Title,
-			BoardName: task.BoardName,
-		})
-	}
+		user := &users[i]
 
-	// Send one notification per user with ONLY their overdue tasks
-	for userID := range userTasks {
-		// Filter overdue tasks to get only this user's tasks
-		var userOverdueTasks []domain.OverdueTask
-		for _, task := range overdueTasks {
-			if task.UserID == userID {
-				userOverdueTasks = append(userOverdueTasks, task)
-			}
+		tasks, err := s.itemRepo.GetOverdueTasks(ctx, user.ID)
+		if err != nil {
+			s.logger.Error("failed to get overdue tasks", "user_id", user.ID, "error", err)
+			continue
 		}
 
-		if len(userOverdueTasks) == 0 {
+		// Mark first, so that a user is never notified twice a day
+		today := time.Now().In(user.Location())
+		marked, err := s.userRepo.MarkOverdueNotified(ctx, user.ID, today)
+		if err != nil {
+			s.logger.Error("failed to mark overdue notification", "user_id", user.ID, "error", err)
+			continue
+		}
+		if !marked || len(tasks) == 0 {
 			continue
 		}
 
 		s.logger.Info("sending overdue notification",
-			"user_id", userID,
-			"task_count", len(userOverdueTasks),
+			"user_id", user.ID,
+			"task_count", len(tasks),
 		)
 
-		// Send notification via the notification service with only this user's tasks
-		if err := s.notificationSvc.SendOverdueTasksNotification(ctx, userID, userOverdueTasks); err != nil {
-			s.logger.Error("failed to send overdue tasks notification",
-				"user_id", userID,
+		if err := s.notificationSvc.QueueOverdueTasksNotification(ctx, user.ID, tasks); err != nil {
+			s.logger.Error("failed to queue overdue tasks notification",
+				"user_id", user.ID,
 				"error", err,
 			)
+			// Try again on the next run
+			_ = s.userRepo.UnmarkOverdueNotified(ctx, user.ID, today)
+			continue
 		}
+
+		queued++
 	}
+
+	s.logger.Info("queued overdue notifications", "count", queued)
 }
 
-// checkDueSoonTasks sends notifications about tasks due soon
+// checkDueSoonTasks queues notifications about tasks due within each user's
+// due-soon window. Every item is announced once per due date.
 func (s *ReminderScheduler) checkDueSoonTasks() {
-	_, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
 	s.logger.Debug("checking tasks due soon")
 
-	// This would require getting all users and checking their tasks
-	// For now, this is handled by the reminder system
-	// Users can set explicit reminders for their tasks
+	tasks, err := s.itemRepo.GetDueSoonTasks(ctx)
+	if err != nil {
+		s.logger.Error("failed to get tasks due soon", "error", err)
+		return
+	}
+
+	queued := 0
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			s.logger.Warn("due soon check cancelled due to timeout", "queued", queued)
+			return
+		}
+
+		// Mark first, so that an item is never announced twice
+		marked, err := s.dueSoonRepo.Mark(ctx, task.ItemID, task.DueDate)
+		if err != nil {
+			s.logger.Error("failed to mark task due soon", "item_id", task.ItemID, "error", err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+
+		due := task.DueDate.In(domain.LoadLocation(task.Timezone))
+		item := &domain.Item{ID: task.ItemID, Title: task.Title, DueDate: &due}
+		if err := s.notificationSvc.QueueDueSoonNotification(ctx, task.UserID, item); err != nil {
+			s.logger.Error("failed to queue due soon notification",
+				"user_id", task.UserID,
+				"item_id", task.ItemID,
+				"error", err,
+			)
+			// Try again on the next run
+			_ = s.dueSoonRepo.Unmark(ctx, task.ItemID, task.DueDate)
+			continue
+		}
+
+		queued++
+	}
+
+	// Markers are only consulted for due dates still ahead
+	if err := s.dueSoonRepo.DeleteBefore(ctx, time.Now()); err != nil {
+		s.logger.Error("failed to delete due soon markers", "error", err)
+	}
+
+	s.logger.Info("queued due soon notifications", "count", queued)
+}
+
+// sendDigests queues the morning digest of users whose digest time has come.
+// Every user gets at most one digest a day; days with nothing on the agenda are skipped.
+func (s *ReminderScheduler) sendDigests() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	s.logger.Debug("checking morning digests")
+
+	users, err := s.userRepo.GetUsersForDigest(ctx, digestCatchUp)
+	if err != nil {
+		s.logger.Error("failed to get users for digest", "error", err)
+		return
+	}
+
+	queued := 0
+	for i := range users {
+		if ctx.Err() != nil {
+			s.logger.Warn("digest check cancelled due to timeout", "queued", queued)
+			return
+		}
+
+		user := &users[i]
+		now := time.Now()
+
+		digest, err := s.digestSvc.Build(ctx, user, now)
+		if err != nil {
+			s.logger.Error("failed to build digest", "user_id", user.ID, "error", err)
+			continue
+		}
+
+		// Mark first, so that a digest is never sent twice
+		today := now.In(user.Location())
+		marked, err := s.userRepo.MarkDigestSent(ctx, user.ID, today)
+		if err != nil {
+			s.logger.Error("failed to mark digest sent", "user_id", user.ID, "error", err)
+			continue
+		}
+		if !marked || digest.IsEmpty() {
+			continue
+		}
+
+		if err := s.notificationSvc.QueueDigest(ctx, user.ID, digest); err != nil {
+			s.logger.Error("failed to queue digest", "user_id", user.ID, "error", err)
+			// Try again on the next run
+			_ = s.userRepo.UnmarkDigestSent(ctx, user.ID, today)
+			continue
+		}
+
+		queued++
+	}
+
+	s.logger.Info("queued morning digests", "count", queued)
+}
+
+// sendWeeklyReports queues the weekly report of users who opted in, once a week.
+// Weeks without any activity are skipped.
+func (s *ReminderScheduler) sendWeeklyReports() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	s.logger.Debug("checking weekly reports")
+
+	users, err := s.userRepo.GetUsersForWeeklyReport(ctx, weeklyReportDay, weeklyReportHour)
+	if err != nil {
+		s.logger.Error("failed to get users for weekly report", "error", err)
+		return
+	}
+
+	queued := 0
+	for i := range users {
+		if ctx.Err() != nil {
+			s.logger.Warn("weekly report check cancelled due to timeout", "queued", queued)
+			return
+		}
+
+		user := &users[i]
+		now := time.Now()
+
+		report, err := s.reportSvc.Build(ctx, user, now)
+		if err != nil {
+			s.logger.Error("failed to build weekly report", "user_id", user.ID, "error", err)
+			continue
+		}
+
+		// Mark first, so that a report is never sent twice
+		today := now.In(user.Location())
+		marked, err := s.userRepo.MarkWeeklyReportSent(ctx, user.ID, today)
+		if err != nil {
+			s.logger.Error("failed to mark weekly report sent", "user_id", user.ID, "error", err)
+			continue
+		}
+		if !marked || report.IsEmpty() {
+			continue
+		}
+
+		if err := s.notificationSvc.QueueWeeklyReport(ctx, user.ID, report); err != nil {
+			s.logger.Error("failed to queue weekly report", "user_id", user.ID, "error", err)
+			// Try again on the next run
+			_ = s.userRepo.UnmarkWeeklyReportSent(ctx, user.ID, today)
+			continue
+		}
+
+		queued++
+	}
+
+	s.logger.Info("queued weekly reports", "count", queued)
+}
+
+// deliverNotifications sends due notifications of the outbox
+func (s *ReminderScheduler) deliverNotifications() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	delivered, err := s.notificationSvc.DeliverPending(ctx)
+	if err != nil {
+		s.logger.Error("failed to deliver notifications", "delivered", delivered, "error", err)
+		return
+	}
+
+	if delivered > 0 {
+		s.logger.Debug("delivered notifications", "count", delivered)
+	}
+}
+
+// purgeOutbox deletes sent notifications past their retention period
+func (s *ReminderScheduler) purgeOutbox() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	deleted, err := s.notificationSvc.PurgeSent(ctx)
+	if err != nil {
+		s.logger.Error("failed to purge sent notifications", "error", err)
+		return
+	}
 
-	s.logger.Debug("due soon check completed")
+	s.logger.Info("purged sent notifications", "count", deleted)
 }
 
 // RunOnce runs all checks once (useful for testing)
@@ -246,6 +509,9 @@ func (s *ReminderScheduler) RunOnce() {
 	s.checkInactiveUsers()
 	s.checkOverdueTasks()
 	s.checkDueSoonTasks()
+	s.sendDigests()
+	s.sendWeeklyReports()
+	s.deliverNotifications()
 }
 
 // AddCustomJob adds a custom cron job
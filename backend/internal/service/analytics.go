@@ -73,7 +73,7 @@ func (s *AnalyticsService) GetOverview(ctx context.Context, userID int64) (*doma
 	}, nil
 }
 
-// GetCompletionStats returns completion statistics for the last N days
+// GetCompletionStats returns completion statistics for the last N days in the user's timezone
 func (s *AnalyticsService) GetCompletionStats(ctx context.Context, userID int64, days int) ([]domain.CompletionStats, error) {
 	if days <= 0 {
 		days = 7
@@ -82,5 +82,10 @@ func (s *AnalyticsService) GetCompletionStats(ctx context.Context, userID int64,
 		days = 90
 	}
 
-	return s.itemRepo.GetCompletionStats(ctx, userID, days)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.itemRepo.GetCompletionStats(ctx, userID, days, user.Location())
 }
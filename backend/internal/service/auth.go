@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -126,9 +127,31 @@ func (s *AuthService) GetCurrentUser(ctx context.Context, userID int64) (*domain
 	return user, nil
 }
 
-// UpdateUserSettings updates user settings
+// UpdateUserSettings validates the settings and saves them at once, so an
+// invalid value leaves all of them unchanged
 func (s *AuthService) UpdateUserSettings(ctx context.Context, userID int64, settings *domain.UserSettings) error {
-	return s.userRepo.UpdateSettings(ctx, userID, settings)
+	if settings.DueSoonMinutes != nil && (*settings.DueSoonMinutes < 0 || *settings.DueSoonMinutes > domain.MaxDueSoonMinutes) {
+		return domain.NewBadRequestError(fmt.Sprintf("due_soon_minutes must be between 0 and %d", domain.MaxDueSoonMinutes))
+	}
+	if settings.QuietHours != nil && !settings.QuietHours.IsOff() {
+		if err := settings.QuietHours.Validate(); err != nil {
+			return domain.NewBadRequestError("quiet_hours needs a start and an end in HH:MM format that differ, and a mode of hold or silent")
+		}
+		if settings.QuietHours.Mode == "" {
+			settings.QuietHours.Mode = domain.QuietHoursHold
+		}
+	}
+	if settings.DigestTime != nil && *settings.DigestTime != "" {
+		if err := domain.ValidateClock(*settings.DigestTime); err != nil {
+			return domain.NewBadRequestError("digest_time must be in HH:MM format")
+		}
+	}
+
+	err := s.userRepo.UpdateSettings(ctx, userID, settings)
+	if err == domain.ErrInvalidInput {
+		return domain.NewBadRequestError("inbox_board_id is not one of your boards")
+	}
+	return err
 }
 
 func (s *AuthService) generateToken(user *domain.User) (string, error) {
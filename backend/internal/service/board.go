@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/telegram-task-manager/backend/internal/domain"
@@ -13,17 +14,20 @@ type BoardService struct {
 	boardRepo    repository.BoardRepository
 	folderRepo   repository.FolderRepository
 	activityRepo repository.ActivityLogRepository
+	itemService  *ItemService
 }
 
 func NewBoardService(
 	boardRepo repository.BoardRepository,
 	folderRepo repository.FolderRepository,
 	activityRepo repository.ActivityLogRepository,
+	itemService *ItemService,
 ) *BoardService {
 	return &BoardService{
 		boardRepo:    boardRepo,
 		folderRepo:   folderRepo,
 		activityRepo: activityRepo,
+		itemService:  itemService,
 	}
 }
 
@@ -113,6 +117,10 @@ func (s *BoardService) CreateBoard(ctx context.Context, userID int64, folderID u
 		Position: req.Position,
 	}
 
+	if err := validateBoardSettings(board); err != nil {
+		return nil, err
+	}
+
 	if err := s.boardRepo.Create(ctx, board); err != nil {
 		return nil, err
 	}
@@ -145,6 +153,9 @@ func (s *BoardService) UpdateBoard(ctx context.Context, userID int64, boardID uu
 		return nil, domain.ErrForbidden
 	}
 
+	// Settings that cannot be read have no default offsets
+	oldSettings, _ := board.ParseSettings()
+
 	// Apply updates
 	if req.Name != nil {
 		board.Name = *req.Name
@@ -156,10 +167,22 @@ func (s *BoardService) UpdateBoard(ctx context.Context, userID int64, boardID uu
 		board.Position = *req.Position
 	}
 
+	if err := validateBoardSettings(board); err != nil {
+		return nil, err
+	}
+
 	if err := s.boardRepo.Update(ctx, board); err != nil {
 		return nil, err
 	}
 
+	// Items without offsets of their own follow the board's defaults
+	newSettings, _ := board.ParseSettings()
+	if !slices.Equal(oldSettings.ReminderOffsets, newSettings.ReminderOffsets) {
+		if err := s.itemService.syncBoardRelativeReminders(ctx, userID, board.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
 		UserID:     userID,
@@ -249,3 +272,13 @@ func (s *BoardService) getDefaultSettings(boardType domain.BoardType) json.RawMe
 	data, _ := json.Marshal(settings)
 	return data
 }
+
+// validateBoardSettings rejects invalid default reminder offsets. Other
+// settings are free-form for the frontend.
+func validateBoardSettings(board *domain.Board) error {
+	settings, err := board.ParseSettings()
+	if err != nil {
+		return nil
+	}
+	return domain.ValidateReminderOffsets(settings.ReminderOffsets)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+	"github.com/telegram-task-manager/backend/pkg/i18n"
+)
+
+// BotService backs the text commands of the Telegram bot
+type BotService struct {
+	itemService *ItemService
+	itemRepo    repository.ItemRepository
+	boardRepo   repository.BoardRepository
+	userRepo    repository.UserRepository
+	listingRepo repository.BotListingRepository
+}
+
+func NewBotService(
+	itemService *ItemService,
+	itemRepo repository.ItemRepository,
+	boardRepo repository.BoardRepository,
+	userRepo repository.UserRepository,
+	listingRepo repository.BotListingRepository,
+) *BotService {
+	return &BotService{
+		itemService: itemService,
+		itemRepo:    itemRepo,
+		boardRepo:   boardRepo,
+		userRepo:    userRepo,
+		listingRepo: listingRepo,
+	}
+}
+
+// Locale returns the timezone and language of the user's replies. Unknown
+// users get UTC, and the fallback language code is used when the user has
+// none, e.g. the one Telegram sent with the update.
+func (s *BotService) Locale(ctx context.Context, userID int64, fallback string) (*time.Location, i18n.Lang) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.UTC, i18n.Parse(fallback)
+	}
+	if user.LanguageCode == "" {
+		return user.Location(), i18n.Parse(fallback)
+	}
+	return user.Location(), i18n.Parse(user.LanguageCode)
+}
+
+// GetInboxBoard returns the board that quick-captured items go to: the user's
+// configured inbox, or else their first checklist board
+func (s *BotService) GetInboxBoard(ctx context.Context, userID int64) (*domain.Board, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.InboxBoardID != nil {
+		board, err := s.boardRepo.GetByID(ctx, *user.InboxBoardID)
+		if err == nil {
+			ownerID, err := s.boardRepo.GetFolderOwner(ctx, board.FolderID)
+			if err == nil && ownerID == userID {
+				return board, nil
+			}
+		}
+	}
+
+	return s.boardRepo.GetFirstByType(ctx, userID, domain.BoardTypeChecklist)
+}
+
+// AddItem creates an item with the given text in the user's inbox board.
+// Date phrases in the text become the item's due date, see ItemService.QuickAddItem.
+func (s *BotService) AddItem(ctx context.Context, userID int64, text string) (*domain.Item, *domain.Board, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil, domain.ErrInvalidInput
+	}
+
+	board, err := s.GetInboxBoard(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	item, err := s.itemService.QuickAddItem(ctx, userID, board.ID, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return item, board, nil
+}
+
+// GetTodayItems lists the user's open items due today in their timezone and
+// remembers the listing for CompleteListed. Due dates are returned in the user's timezone.
+func (s *BotService) GetTodayItems(ctx context.Context, userID int64) ([]domain.Item, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := user.Location()
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	items, err := s.itemRepo.GetDueBetween(ctx, userID, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.saveListing(ctx, userID, items, loc)
+}
+
+// GetOverdueItems lists the user's open items past their due date and
+// remembers the listing for CompleteListed. Due dates are returned in the user's timezone.
+func (s *BotService) GetOverdueItems(ctx context.Context, userID int64) ([]domain.Item, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.itemRepo.GetOverdueByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.saveListing(ctx, userID, items, user.Location())
+}
+
+// CompleteListed completes the n-th (1-based) item of the user's last listing
+func (s *BotService) CompleteListed(ctx context.Context, userID int64, n int) (*domain.Item, error) {
+	itemIDs, err := s.listingRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 1 || n > len(itemIDs) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.itemService.CompleteItem(ctx, userID, itemIDs[n-1], true)
+}
+
+func (s *BotService) saveListing(ctx context.Context, userID int64, items []domain.Item, loc *time.Location) ([]domain.Item, error) {
+	itemIDs := make([]uuid.UUID, len(items))
+	for i := range items {
+		itemIDs[i] = items[i].ID
+		if items[i].DueDate != nil {
+			due := items[i].DueDate.In(loc)
+			items[i].DueDate = &due
+		}
+	}
+
+	if err := s.listingRepo.Save(ctx, userID, itemIDs); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
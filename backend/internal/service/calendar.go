@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+	"github.com/telegram-task-manager/backend/pkg/rrule"
+)
+
+// MaxCalendarRangeDays limits how many days a single calendar request may span
+const MaxCalendarRangeDays = 400
+
+// maxRecurExceptions limits how many occurrences of one recurring item can be skipped or moved
+const maxRecurExceptions = 1000
+
+type CalendarService struct {
+	itemRepo     repository.ItemRepository
+	boardRepo    repository.BoardRepository
+	userRepo     repository.UserRepository
+	activityRepo repository.ActivityLogRepository
+}
+
+func NewCalendarService(
+	itemRepo repository.ItemRepository,
+	boardRepo repository.BoardRepository,
+	userRepo repository.UserRepository,
+	activityRepo repository.ActivityLogRepository,
+) *CalendarService {
+	return &CalendarService{
+		itemRepo:     itemRepo,
+		boardRepo:    boardRepo,
+		userRepo:     userRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// GetBoardCalendar expands the items of a board into occurrences between two dates
+// (inclusive), rendered in the user's timezone. Only the calendar dates of from and to are used.
+func (s *CalendarService) GetBoardCalendar(ctx context.Context, userID int64, boardID uuid.UUID, from, to time.Time) (*domain.CalendarRange, error) {
+	// Check board ownership
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := user.Location()
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+
+	if end.Before(start) || end.Sub(start) > MaxCalendarRangeDays*24*time.Hour {
+		return nil, domain.ErrInvalidInput
+	}
+
+	items, err := s.itemRepo.GetByBoardID(ctx, boardID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CalendarRange{
+		From:     start.Format("2006-01-02"),
+		To:       end.Format("2006-01-02"),
+		Timezone: loc.String(),
+		Events:   ExpandOccurrences(items, loc, start, end),
+	}, nil
+}
+
+// ExpandOccurrences turns items into calendar occurrences within [from, to].
+// Recurring items are expanded with their RRULE and exceptions; completed
+// recurring items are shown once, since completing them spawns the next instance.
+func ExpandOccurrences(items []domain.Item, loc *time.Location, from, to time.Time) []domain.CalendarOccurrence {
+	events := make([]domain.CalendarOccurrence, 0)
+
+	for i := range items {
+		item := &items[i]
+		if item.DueDate == nil {
+			continue
+		}
+
+		meta, _ := item.ParseMetadata()
+		dtstart := seriesStart(*item.DueDate, meta.AllDay, loc)
+
+		var rule *rrule.Rule
+		if meta.RecurRule != "" && item.Status != domain.ItemStatusCompleted {
+			rule, _ = rrule.Parse(meta.RecurRule)
+		}
+
+		if rule == nil {
+			if !dtstart.Before(from) && !dtstart.After(to) {
+				events = append(events, newOccurrence(item, &meta, dtstart, loc))
+			}
+			continue
+		}
+
+		handled := make(map[int]bool)
+		for _, occ := range rule.Between(dtstart, from, to) {
+			idx := findException(meta.RecurExceptions, occ, meta.AllDay, loc)
+			if idx < 0 {
+				events = append(events, newRecurringOccurrence(item, &meta, occ, occ, loc))
+				continue
+			}
+
+			handled[idx] = true
+			if ex := meta.RecurExceptions[idx]; !ex.Skip && ex.Start != nil {
+				start := ex.Start.In(loc)
+				if !start.Before(from) && !start.After(to) {
+					events = append(events, newRecurringOccurrence(item, &meta, occ, start, loc))
+				}
+			}
+		}
+
+		// Occurrences moved into the window from outside of it
+		for idx, ex := range meta.RecurExceptions {
+			if handled[idx] || ex.Skip || ex.Start == nil {
+				continue
+			}
+			start := ex.Start.In(loc)
+			if !start.Before(from) && !start.After(to) {
+				events = append(events, newRecurringOccurrence(item, &meta, ex.OriginalStart.In(loc), start, loc))
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	return events
+}
+
+func newOccurrence(item *domain.Item, meta *domain.ItemMetadata, start time.Time, loc *time.Location) domain.CalendarOccurrence {
+	start = start.In(loc)
+	return domain.CalendarOccurrence{
+		ItemID:     item.ID,
+		BoardID:    item.BoardID,
+		Title:      item.Title,
+		Content:    item.Content,
+		Status:     item.Status,
+		Start:      start,
+		Date:       start.Format("2006-01-02"),
+		AllDay:     meta.AllDay,
+		Location:   meta.Location,
+		EventColor: meta.EventColor,
+	}
+}
+
+func newRecurringOccurrence(item *domain.Item, meta *domain.ItemMetadata, original, start time.Time, loc *time.Location) domain.CalendarOccurrence {
+	occ := newOccurrence(item, meta, start, loc)
+	occ.Recurring = true
+	occ.OriginalStart = &original
+	occ.Moved = !original.Equal(start)
+	return occ
+}
+
+// seriesStart returns the start of an item's first occurrence in loc;
+// all-day items start at midnight
+func seriesStart(due time.Time, allDay bool, loc *time.Location) time.Time {
+	due = due.In(loc)
+	if allDay {
+		return time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc)
+	}
+	return due
+}
+
+// isOccurrence reports whether the recurring item has an occurrence starting at t,
+// or on the date of t for all-day items
+func isOccurrence(item *domain.Item, meta *domain.ItemMetadata, t time.Time, loc *time.Location) bool {
+	rule, err := rrule.Parse(meta.RecurRule)
+	if err != nil || item.DueDate == nil {
+		return false
+	}
+
+	from, to := t, t
+	if meta.AllDay {
+		from = seriesStart(t, true, loc)
+		to = from.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	return len(rule.Between(seriesStart(*item.DueDate, meta.AllDay, loc), from, to)) > 0
+}
+
+// findException returns the index of the exception for the occurrence, or -1.
+// All-day occurrences are matched by date, since clients may send any time of day.
+func findException(exceptions []domain.RecurException, occ time.Time, allDay bool, loc *time.Location) int {
+	for i, ex := range exceptions {
+		if ex.OriginalStart.Equal(occ) {
+			return i
+		}
+		if allDay && ex.OriginalStart.In(loc).Format("2006-01-02") == occ.Format("2006-01-02") {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetException skips or moves a single occurrence of a recurring item.
+// An existing exception for the same occurrence is replaced; times that are
+// not an occurrence of the item's rule are rejected.
+func (s *CalendarService) SetException(ctx context.Context, userID int64, itemID uuid.UUID, req *domain.RecurException) (*domain.Item, error) {
+	if !req.Skip && req.Start == nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	item, meta, err := s.getRecurringItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	if !isOccurrence(item, meta, req.OriginalStart, loc) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	exceptions := meta.RecurExceptions
+	if idx := findException(exceptions, req.OriginalStart, meta.AllDay, loc); idx >= 0 {
+		exceptions[idx] = *req
+	} else {
+		if len(exceptions) >= maxRecurExceptions {
+			return nil, domain.ErrInvalidInput
+		}
+		exceptions = append(exceptions, *req)
+	}
+
+	return s.saveExceptions(ctx, item, exceptions)
+}
+
+// RemoveException restores a skipped or moved occurrence
+func (s *CalendarService) RemoveException(ctx context.Context, userID int64, itemID uuid.UUID, originalStart time.Time) (*domain.Item, error) {
+	item, meta, err := s.getRecurringItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	idx := findException(meta.RecurExceptions, originalStart, meta.AllDay, loc)
+	if idx < 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	exceptions := append(meta.RecurExceptions[:idx:idx], meta.RecurExceptions[idx+1:]...)
+
+	return s.saveExceptions(ctx, item, exceptions)
+}
+
+func (s *CalendarService) getRecurringItem(ctx context.Context, userID int64, itemID uuid.UUID) (*domain.Item, *domain.ItemMetadata, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Check ownership
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, item.BoardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ownerID != userID {
+		return nil, nil, domain.ErrForbidden
+	}
+
+	meta, err := item.ParseMetadata()
+	if err != nil || meta.RecurRule == "" {
+		return nil, nil, domain.ErrInvalidInput
+	}
+
+	return item, &meta, nil
+}
+
+func (s *CalendarService) saveExceptions(ctx context.Context, item *domain.Item, exceptions []domain.RecurException) (*domain.Item, error) {
+	var value interface{}
+	if len(exceptions) > 0 {
+		value = exceptions
+	}
+
+	metadata, err := domain.MergeMetadata(item.Metadata, map[string]interface{}{
+		"recur_exceptions": value,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Metadata = metadata
+	if err := s.itemRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
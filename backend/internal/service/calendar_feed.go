@@ -0,0 +1,374 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+	"github.com/telegram-task-manager/backend/pkg/ical"
+	"github.com/telegram-task-manager/backend/pkg/rrule"
+)
+
+const (
+	// MaxCalendarFeedsPerUser limits how many feed tokens a user may hold at once
+	MaxCalendarFeedsPerUser = 20
+
+	feedProdID    = "-//Telegram Task Manager//Calendar Feed//EN"
+	feedUIDDomain = "telegram-task-manager"
+
+	// defaultEventDuration is used for timed events without an end time
+	defaultEventDuration = time.Hour
+)
+
+type CalendarFeedService struct {
+	feedRepo  repository.CalendarFeedRepository
+	boardRepo repository.BoardRepository
+	itemRepo  repository.ItemRepository
+	userRepo  repository.UserRepository
+	publicURL string
+}
+
+func NewCalendarFeedService(
+	feedRepo repository.CalendarFeedRepository,
+	boardRepo repository.BoardRepository,
+	itemRepo repository.ItemRepository,
+	userRepo repository.UserRepository,
+	publicURL string,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:  feedRepo,
+		boardRepo: boardRepo,
+		itemRepo:  itemRepo,
+		userRepo:  userRepo,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// GetUserFeeds returns the user's feeds without their tokens
+func (s *CalendarFeedService) GetUserFeeds(ctx context.Context, userID int64) ([]domain.CalendarFeed, error) {
+	feeds, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if feeds == nil {
+		feeds = []domain.CalendarFeed{}
+	}
+
+	return feeds, nil
+}
+
+// CreateFeed issues a new feed token for a calendar board.
+// The token is only returned here; afterwards the feed can only be revoked.
+func (s *CalendarFeedService) CreateFeed(ctx context.Context, userID int64, req *domain.CreateCalendarFeedRequest) (*domain.CalendarFeed, error) {
+	board, err := s.boardRepo.GetByID(ctx, req.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership through folder
+	ownerID, err := s.boardRepo.GetFolderOwner(ctx, board.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	if board.Type != domain.BoardTypeCalendar {
+		return nil, domain.ErrInvalidBoardType
+	}
+
+	existing, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(existing) >= MaxCalendarFeedsPerUser {
+		return nil, domain.ErrConflict
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &domain.CalendarFeed{
+		UserID:       userID,
+		BoardID:      board.ID,
+		TokenHash:    hashFeedToken(token),
+		IncludeTasks: req.IncludeTasks,
+	}
+
+	if err := s.feedRepo.Create(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	feed.Token = token
+	feed.URL = s.publicURL + "/ical/" + token + ".ics"
+
+	return feed, nil
+}
+
+// DeleteFeed revokes a feed token
+func (s *CalendarFeedService) DeleteFeed(ctx context.Context, userID int64, feedID uuid.UUID) error {
+	feed, err := s.feedRepo.GetByID(ctx, feedID)
+	if err != nil {
+		return err
+	}
+
+	if feed.UserID != userID {
+		return domain.ErrForbidden
+	}
+
+	return s.feedRepo.Delete(ctx, feedID)
+}
+
+// RenderFeed serializes the board of the feed identified by token as an iCalendar document
+func (s *CalendarFeedService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	feed, err := s.feedRepo.GetByTokenHash(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := s.boardRepo.GetByID(ctx, feed.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	loc := user.Location()
+
+	items, err := s.itemRepo.GetByBoardID(ctx, board.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.NewCalendar(feedProdID)
+	cal.Add("METHOD", "PUBLISH")
+	cal.AddText("X-WR-CALNAME", board.Name)
+	cal.Add("X-WR-TIMEZONE", loc.String())
+	cal.Add("REFRESH-INTERVAL", "PT1H", ical.Param{Name: "VALUE", Value: "DURATION"})
+	cal.Add("X-PUBLISHED-TTL", "PT1H")
+
+	if loc != time.UTC {
+		cal.AddComponent(ical.Timezone(loc, time.Now().In(loc).Year()))
+	}
+
+	for i := range items {
+		for _, event := range itemEvents(&items[i], loc) {
+			cal.AddComponent(event)
+		}
+	}
+
+	if feed.IncludeTasks {
+		tasks, err := s.itemRepo.GetScheduledTasks(ctx, feed.UserID, board.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tasks {
+			cal.AddComponent(itemTodo(&tasks[i], loc))
+		}
+	}
+
+	_ = s.feedRepo.UpdateLastAccessed(ctx, feed.ID)
+
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// itemEvents converts a calendar item into a VEVENT. Recurring items carry their
+// RRULE, skipped occurrences become EXDATEs and moved occurrences become
+// separate VEVENTs with a RECURRENCE-ID.
+func itemEvents(item *domain.Item, loc *time.Location) []*ical.Component {
+	if item.DueDate == nil || item.Status == domain.ItemStatusArchived {
+		return nil
+	}
+
+	meta, _ := item.ParseMetadata()
+
+	start := item.DueDate.In(loc)
+	if meta.AllDay {
+		start = startOfDay(start)
+	}
+
+	// Completed recurring items already spawned their next instance
+	var rule *rrule.Rule
+	if meta.RecurRule != "" && item.Status != domain.ItemStatusCompleted {
+		rule, _ = rrule.Parse(meta.RecurRule)
+	}
+
+	if rule == nil {
+		return []*ical.Component{newFeedEvent(item, &meta, start, false)}
+	}
+
+	event := newFeedEvent(item, &meta, start, true)
+	event.Add("RRULE", rule.StringFor(meta.AllDay, loc))
+	events := []*ical.Component{event}
+
+	for _, ex := range meta.RecurExceptions {
+		original := ex.OriginalStart.In(loc)
+		if meta.AllDay {
+			original = startOfDay(original)
+		}
+
+		if ex.Skip || ex.Start == nil {
+			addFeedTime(event, "EXDATE", original, meta.AllDay, true)
+			continue
+		}
+
+		moved := ex.Start.In(loc)
+		if meta.AllDay {
+			moved = startOfDay(moved)
+		}
+
+		override := newFeedEvent(item, &meta, moved, true)
+		addFeedTime(override, "RECURRENCE-ID", original, meta.AllDay, true)
+		events = append(events, override)
+	}
+
+	return events
+}
+
+// newFeedEvent builds a VEVENT starting at start. Recurring events use local
+// times with a TZID so the series follows daylight saving changes.
+func newFeedEvent(item *domain.Item, meta *domain.ItemMetadata, start time.Time, recurring bool) *ical.Component {
+	event := ical.NewComponent("VEVENT")
+	addFeedCommon(event, item)
+
+	if meta.Location != "" {
+		event.AddText("LOCATION", meta.Location)
+	}
+
+	if meta.AllDay {
+		event.AddDate("DTSTART", start)
+		event.AddDate("DTEND", start.AddDate(0, 0, 1))
+	} else {
+		addFeedTime(event, "DTSTART", start, false, recurring)
+		addFeedTime(event, "DTEND", eventEnd(start, meta), false, recurring)
+	}
+
+	event.Add("STATUS", "CONFIRMED")
+	event.Add("TRANSP", "OPAQUE")
+
+	return event
+}
+
+// itemTodo converts a dated task into a VTODO
+func itemTodo(item *domain.Item, loc *time.Location) *ical.Component {
+	meta, _ := item.ParseMetadata()
+
+	todo := ical.NewComponent("VTODO")
+	addFeedCommon(todo, item)
+
+	if meta.AllDay {
+		todo.AddDate("DUE", item.DueDate.In(loc))
+	} else {
+		todo.AddUTC("DUE", *item.DueDate)
+	}
+
+	switch item.Status {
+	case domain.ItemStatusCompleted:
+		todo.Add("STATUS", "COMPLETED")
+		todo.Add("PERCENT-COMPLETE", "100")
+		if item.CompletedAt != nil {
+			todo.AddUTC("COMPLETED", *item.CompletedAt)
+		}
+	case domain.ItemStatusInProgress:
+		todo.Add("STATUS", "IN-PROCESS")
+	default:
+		todo.Add("STATUS", "NEEDS-ACTION")
+	}
+
+	switch meta.Priority {
+	case "high":
+		todo.Add("PRIORITY", "1")
+	case "medium":
+		todo.Add("PRIORITY", "5")
+	case "low":
+		todo.Add("PRIORITY", "9")
+	}
+
+	return todo
+}
+
+func addFeedCommon(c *ical.Component, item *domain.Item) {
+	c.Add("UID", feedUID(item))
+	c.AddUTC("DTSTAMP", item.UpdatedAt)
+	c.AddUTC("CREATED", item.CreatedAt)
+	c.AddUTC("LAST-MODIFIED", item.UpdatedAt)
+	c.AddText("SUMMARY", item.Title)
+
+	if item.Content != "" {
+		c.AddText("DESCRIPTION", item.Content)
+	}
+}
+
+// feedUID keeps the UID of imported events so calendar apps can match them
+func feedUID(item *domain.Item) string {
+	if meta, err := item.ParseMetadata(); err == nil && meta.ICalUID != "" {
+		return meta.ICalUID
+	}
+	return item.ID.String() + "@" + feedUIDDomain
+}
+
+func addFeedTime(c *ical.Component, name string, t time.Time, allDay, local bool) {
+	switch {
+	case allDay:
+		c.AddDate(name, t)
+	case local:
+		c.AddLocal(name, t)
+	default:
+		c.AddUTC(name, t)
+	}
+}
+
+// eventEnd uses the item's end time ("HH:MM") when it is later on the same day
+func eventEnd(start time.Time, meta *domain.ItemMetadata) time.Time {
+	if meta.EndTime != "" {
+		if t, err := time.Parse("15:04", meta.EndTime); err == nil {
+			end := time.Date(start.Year(), start.Month(), start.Day(), t.Hour(), t.Minute(), 0, 0, start.Location())
+			if end.After(start) {
+				return end
+			}
+		}
+	}
+	return start.Add(defaultEventDuration)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func generateFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/pkg/ical"
+	"github.com/telegram-task-manager/backend/pkg/rrule"
+)
+
+const (
+	// MaxCalendarImportSize is the largest .ics upload accepted, in bytes
+	MaxCalendarImportSize = 5 << 20
+
+	// MaxCalendarImportEvents limits the number of events in one import
+	MaxCalendarImportEvents = 5000
+
+	maxItemTitleLength   = 500
+	maxItemContentLength = 10000
+)
+
+// icalEvent groups a VEVENT with the overrides of its occurrences (same UID, with RECURRENCE-ID)
+type icalEvent struct {
+	uid       string
+	master    *ical.Component
+	overrides []*ical.Component
+}
+
+// importedEvent is a VEVENT mapped onto item columns and metadata
+type importedEvent struct {
+	title     string
+	content   string
+	dueDate   time.Time
+	cancelled bool
+	metadata  map[string]interface{}
+	warnings  []string
+}
+
+// ImportICS creates or updates board items from an iCalendar file.
+// Events are matched by UID, so importing the same file again updates the
+// existing items instead of creating duplicates.
+func (s *CalendarService) ImportICS(ctx context.Context, userID int64, boardID uuid.UUID, r io.Reader) (*domain.CalendarImportResult, error) {
+	board, err := s.boardRepo.GetByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check board ownership
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	if board.Type != domain.BoardTypeCalendar {
+		return nil, domain.ErrInvalidBoardType
+	}
+
+	cal, err := ical.Decode(r)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalidCalendar) {
+			return nil, domain.ErrInvalidInput
+		}
+		return nil, err
+	}
+
+	if cal.Name != "VCALENDAR" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	events := groupICalEvents(cal)
+	if len(events) > MaxCalendarImportEvents {
+		return nil, domain.ErrInvalidInput
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	existing, err := s.itemRepo.GetByBoardID(ctx, boardID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byUID := make(map[string]*domain.Item, len(existing))
+	for i := range existing {
+		item := &existing[i]
+		// Events exported by our own feed carry the item ID in their UID
+		byUID[item.ID.String()+"@"+feedUIDDomain] = item
+		if meta, err := item.ParseMetadata(); err == nil && meta.ICalUID != "" {
+			byUID[meta.ICalUID] = item
+		}
+	}
+
+	result := &domain.CalendarImportResult{Errors: []domain.CalendarImportError{}}
+
+	for _, ev := range events {
+		summary := ""
+		if ev.master != nil {
+			if p := ev.master.Get("SUMMARY"); p != nil {
+				summary = p.Text()
+			}
+		}
+
+		imported, err := parseICalEvent(ev, loc)
+		if err != nil {
+			result.Skipped++
+			result.Errors = append(result.Errors, domain.CalendarImportError{UID: ev.uid, Summary: summary, Error: err.Error()})
+			continue
+		}
+
+		for _, warning := range imported.warnings {
+			result.Errors = append(result.Errors, domain.CalendarImportError{UID: ev.uid, Summary: summary, Error: warning})
+		}
+
+		item, exists := byUID[ev.uid]
+		if imported.cancelled && !exists {
+			result.Skipped++
+			continue
+		}
+
+		if !exists {
+			item = &domain.Item{
+				BoardID: boardID,
+				Status:  domain.ItemStatusPending,
+			}
+		}
+
+		metadata, err := domain.MergeMetadata(item.Metadata, imported.metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		due := imported.dueDate
+		item.Title = imported.title
+		item.Content = imported.content
+		item.DueDate = &due
+		item.Metadata = metadata
+
+		if !exists {
+			if err := s.itemRepo.Create(ctx, item); err != nil {
+				return nil, err
+			}
+			byUID[ev.uid] = item
+			result.Created++
+			continue
+		}
+
+		if imported.cancelled {
+			item.Status = domain.ItemStatusArchived
+		}
+
+		if err := s.itemRepo.Update(ctx, item); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	if result.Created > 0 || result.Updated > 0 {
+		// Log activity
+		_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
+			UserID:     userID,
+			Action:     "import",
+			EntityType: "board",
+			EntityID:   boardID,
+		})
+	}
+
+	return result, nil
+}
+
+// groupICalEvents collects the VEVENTs of a calendar by UID, keeping file order
+func groupICalEvents(cal *ical.Component) []*icalEvent {
+	var events []*icalEvent
+	byUID := make(map[string]*icalEvent)
+
+	for _, c := range cal.Components {
+		if c.Name != "VEVENT" {
+			continue
+		}
+
+		uid := ""
+		if p := c.Get("UID"); p != nil {
+			uid = strings.TrimSpace(p.Value)
+		}
+		if uid == "" {
+			uid = syntheticUID(c)
+		}
+
+		ev, ok := byUID[uid]
+		if !ok {
+			ev = &icalEvent{uid: uid}
+			byUID[uid] = ev
+			events = append(events, ev)
+		}
+
+		if c.Get("RECURRENCE-ID") != nil {
+			ev.overrides = append(ev.overrides, c)
+		} else if ev.master == nil {
+			ev.master = c
+		}
+	}
+
+	return events
+}
+
+// syntheticUID derives a stable UID for events that lack one, so re-imports still match
+func syntheticUID(c *ical.Component) string {
+	h := sha256.New()
+	for _, name := range []string{"DTSTART", "SUMMARY"} {
+		if p := c.Get(name); p != nil {
+			h.Write([]byte(p.Value))
+		}
+		h.Write([]byte{0})
+	}
+	return "import-" + hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// parseICalEvent maps SUMMARY, DESCRIPTION, DTSTART, DTEND, RRULE, EXDATE,
+// LOCATION and occurrence overrides onto an item
+func parseICalEvent(ev *icalEvent, loc *time.Location) (*importedEvent, error) {
+	if ev.master == nil {
+		return nil, errors.New("occurrence override without its recurring event")
+	}
+	c := ev.master
+
+	dtstart := c.Get("DTSTART")
+	if dtstart == nil {
+		return nil, errors.New("missing DTSTART")
+	}
+
+	start, allDay, err := dtstart.Time(loc)
+	if err != nil {
+		return nil, err
+	}
+	if allDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	}
+
+	imported := &importedEvent{
+		title:   "Untitled event",
+		dueDate: start,
+		metadata: map[string]interface{}{
+			"ical_uid":         ev.uid,
+			"all_day":          nil,
+			"end_time":         nil,
+			"location":         nil,
+			"recur_rule":       nil,
+			"recur_exceptions": nil,
+		},
+	}
+
+	if p := c.Get("SUMMARY"); p != nil && strings.TrimSpace(p.Text()) != "" {
+		imported.title = truncateRunes(strings.TrimSpace(p.Text()), maxItemTitleLength)
+	}
+	if p := c.Get("DESCRIPTION"); p != nil {
+		imported.content = truncateRunes(p.Text(), maxItemContentLength)
+	}
+	if p := c.Get("LOCATION"); p != nil && p.Text() != "" {
+		imported.metadata["location"] = p.Text()
+	}
+	if p := c.Get("STATUS"); p != nil && strings.EqualFold(p.Value, "CANCELLED") {
+		imported.cancelled = true
+	}
+
+	if allDay {
+		imported.metadata["all_day"] = true
+	} else if p := c.Get("DTEND"); p != nil {
+		// Timed events ending on the same day keep their end time
+		if end, _, err := p.Time(loc); err == nil {
+			localStart, localEnd := start.In(loc), end.In(loc)
+			if end.After(start) && localEnd.Format("2006-01-02") == localStart.Format("2006-01-02") {
+				imported.metadata["end_time"] = localEnd.Format("15:04")
+			}
+		}
+	}
+
+	p := c.Get("RRULE")
+	if p == nil {
+		return imported, nil
+	}
+
+	rule, err := rrule.Parse(p.Value)
+	if err != nil {
+		imported.warnings = append(imported.warnings, "recurrence not supported, imported as a single event: "+err.Error())
+		return imported, nil
+	}
+	imported.metadata["recur_rule"] = rule.String()
+
+	anchor := func(t time.Time) time.Time {
+		if allDay {
+			t = t.In(loc)
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		return t
+	}
+
+	var exceptions []domain.RecurException
+	for _, exdate := range c.GetAll("EXDATE") {
+		times, _, err := exdate.Times(loc)
+		if err != nil {
+			imported.warnings = append(imported.warnings, err.Error())
+			continue
+		}
+		for _, t := range times {
+			exceptions = append(exceptions, domain.RecurException{OriginalStart: anchor(t), Skip: true})
+		}
+	}
+
+	for _, override := range ev.overrides {
+		original, _, err := override.Get("RECURRENCE-ID").Time(loc)
+		if err != nil {
+			imported.warnings = append(imported.warnings, err.Error())
+			continue
+		}
+
+		if status := override.Get("STATUS"); status != nil && strings.EqualFold(status.Value, "CANCELLED") {
+			exceptions = append(exceptions, domain.RecurException{OriginalStart: anchor(original), Skip: true})
+			continue
+		}
+
+		p := override.Get("DTSTART")
+		if p == nil {
+			continue
+		}
+		moved, _, err := p.Time(loc)
+		if err != nil {
+			imported.warnings = append(imported.warnings, err.Error())
+			continue
+		}
+
+		// Only rescheduling is supported; overrides that change other fields are ignored
+		original, moved = anchor(original), anchor(moved)
+		if !moved.Equal(original) {
+			exceptions = append(exceptions, domain.RecurException{OriginalStart: original, Start: &moved})
+		}
+	}
+
+	if len(exceptions) > 0 {
+		imported.metadata["recur_exceptions"] = exceptions
+	}
+
+	return imported, nil
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
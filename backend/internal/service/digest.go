@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+// DigestService collects the agenda of the morning digest
+type DigestService struct {
+	folderRepo repository.FolderRepository
+	itemRepo   repository.ItemRepository
+	habitRepo  repository.HabitCompletionRepository
+}
+
+func NewDigestService(
+	folderRepo repository.FolderRepository,
+	itemRepo repository.ItemRepository,
+	habitRepo repository.HabitCompletionRepository,
+) *DigestService {
+	return &DigestService{
+		folderRepo: folderRepo,
+		itemRepo:   itemRepo,
+		habitRepo:  habitRepo,
+	}
+}
+
+// Build collects the user's agenda for the day of now in their timezone.
+// Calendar events come from calendar boards and habits from habit trackers;
+// the items of all other boards are listed as due today or overdue.
+func (s *DigestService) Build(ctx context.Context, user *domain.User, now time.Time) (*domain.Digest, error) {
+	loc := user.Location()
+	local := now.In(loc)
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 1)
+
+	folders, err := s.folderRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	boards := make(map[uuid.UUID]*domain.Board)
+	digest := &domain.Digest{Date: from}
+
+	for i := range folders {
+		for j := range folders[i].Boards {
+			board := &folders[i].Boards[j]
+			boards[board.ID] = board
+
+			switch board.Type {
+			case domain.BoardTypeCalendar:
+				events, err := s.boardEvents(ctx, board.ID, loc, from, to)
+				if err != nil {
+					return nil, err
+				}
+				digest.Events = append(digest.Events, events...)
+			case domain.BoardTypeHabitTracker:
+				habits, err := s.uncheckedHabits(ctx, board.ID, local)
+				if err != nil {
+					return nil, err
+				}
+				digest.Habits = append(digest.Habits, habits...)
+			}
+		}
+	}
+
+	slices.SortStableFunc(digest.Events, func(a, b domain.CalendarOccurrence) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	due, err := s.itemRepo.GetDueBetween(ctx, user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[uuid.UUID]int)
+	for _, item := range due {
+		board, ok := boards[item.BoardID]
+		if !ok || !listsTasks(board.Type) {
+			continue
+		}
+
+		idx, ok := groups[board.ID]
+		if !ok {
+			idx = len(digest.Boards)
+			groups[board.ID] = idx
+			digest.Boards = append(digest.Boards, domain.DigestBoard{BoardID: board.ID, Name: board.Name})
+		}
+		digest.Boards[idx].Items = append(digest.Boards[idx].Items, withLocalDueDate(item, loc))
+	}
+
+	overdue, err := s.itemRepo.GetOverdueByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range overdue {
+		// Items due earlier today are listed with today's
+		if !item.DueDate.Before(from) {
+			continue
+		}
+		if board, ok := boards[item.BoardID]; ok && listsTasks(board.Type) {
+			digest.Overdue = append(digest.Overdue, withLocalDueDate(item, loc))
+		}
+	}
+
+	return digest, nil
+}
+
+// boardEvents returns the occurrences of a calendar board's events in [from, to)
+func (s *DigestService) boardEvents(ctx context.Context, boardID uuid.UUID, loc *time.Location, from, to time.Time) ([]domain.CalendarOccurrence, error) {
+	items, err := s.itemRepo.GetByBoardID(ctx, boardID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items = slices.DeleteFunc(items, func(item domain.Item) bool {
+		return item.Status == domain.ItemStatusArchived
+	})
+
+	return ExpandOccurrences(items, loc, from, to.Add(-time.Nanosecond)), nil
+}
+
+// uncheckedHabits returns the daily habits of a board that are due on the day
+// of local and have no completion for it yet
+func (s *DigestService) uncheckedHabits(ctx context.Context, boardID uuid.UUID, local time.Time) ([]domain.Item, error) {
+	items, err := s.itemRepo.GetByBoardID(ctx, boardID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completions are stored by calendar date
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	var habits []domain.Item
+	for _, item := range items {
+		if item.ParentID != nil || item.Status == domain.ItemStatusArchived {
+			continue
+		}
+
+		meta, _ := item.ParseMetadata()
+		if meta.Frequency != "" && meta.Frequency != "daily" {
+			continue
+		}
+		// Target days are weekdays, 0 being Sunday
+		if len(meta.TargetDays) > 0 && !slices.Contains(meta.TargetDays, int(local.Weekday())) {
+			continue
+		}
+
+		completions, err := s.habitRepo.GetByItemID(ctx, item.ID, day, day)
+		if err != nil {
+			return nil, err
+		}
+		if len(completions) == 0 {
+			habits = append(habits, item)
+		}
+	}
+
+	return habits, nil
+}
+
+// listsTasks reports whether the items of a board type are listed as tasks in the digest
+func listsTasks(boardType domain.BoardType) bool {
+	switch boardType {
+	case domain.BoardTypeCalendar, domain.BoardTypeHabitTracker:
+		return false
+	}
+	return true
+}
+
+func withLocalDueDate(item domain.Item, loc *time.Location) domain.Item {
+	if item.DueDate != nil {
+		due := item.DueDate.In(loc)
+		item.DueDate = &due
+	}
+	return item
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+type ExportService struct {
+	userRepo   repository.UserRepository
+	exportRepo repository.ExportRepository
+	folderRepo repository.FolderRepository
+	boardRepo  repository.BoardRepository
+	itemRepo   repository.ItemRepository
+}
+
+func NewExportService(
+	userRepo repository.UserRepository,
+	exportRepo repository.ExportRepository,
+	folderRepo repository.FolderRepository,
+	boardRepo repository.BoardRepository,
+	itemRepo repository.ItemRepository,
+) *ExportService {
+	return &ExportService{
+		userRepo:   userRepo,
+		exportRepo: exportRepo,
+		folderRepo: folderRepo,
+		boardRepo:  boardRepo,
+		itemRepo:   itemRepo,
+	}
+}
+
+// Export writes the user's account to w as a domain.ExportDocument. Rows are
+// written as they are read, so memory use does not grow with the account.
+// When an error is returned, w holds an incomplete document.
+func (s *ExportService) Export(ctx context.Context, userID int64, w io.Writer) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.exportRepo.Snapshot(ctx, userID, func(snapshot repository.ExportSnapshot) error {
+		doc := &documentWriter{w: bufio.NewWriter(w)}
+		doc.field("format", domain.ExportFormat)
+		doc.field("version", domain.ExportVersion)
+		doc.field("exported_at", time.Now().UTC())
+		doc.field("user", domain.NewExportUser(user))
+		doc.field("settings", domain.NewExportSettings(user))
+
+		writeList(ctx, doc, "folders", snapshot.Folders)
+		writeList(ctx, doc, "boards", snapshot.Boards)
+		writeList(ctx, doc, "items", snapshot.Items)
+		writeList(ctx, doc, "reminders", snapshot.Reminders)
+		writeList(ctx, doc, "habit_completions", snapshot.HabitCompletions)
+
+		return doc.close()
+	})
+}
+
+// documentWriter writes a JSON object field by field. The first error is
+// kept and makes the following writes no-ops.
+type documentWriter struct {
+	w      *bufio.Writer
+	fields int
+	err    error
+}
+
+func (d *documentWriter) field(name string, value any) {
+	d.key(name)
+	d.value(value)
+}
+
+func (d *documentWriter) key(name string) {
+	if d.fields == 0 {
+		d.write("{")
+	} else {
+		d.write(",\n")
+	}
+	d.fields++
+	d.value(name)
+	d.write(":")
+}
+
+func (d *documentWriter) value(value any) {
+	if d.err != nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		d.err = err
+		return
+	}
+	_, d.err = d.w.Write(data)
+}
+
+func (d *documentWriter) write(s string) {
+	if d.err != nil {
+		return
+	}
+	_, d.err = d.w.WriteString(s)
+}
+
+// close ends the object and flushes what is buffered
+func (d *documentWriter) close() error {
+	d.write("}\n")
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.Flush()
+}
+
+// writeList writes the rows passed by each to its callback as an array field,
+// one row per line
+func writeList[T any](ctx context.Context, d *documentWriter, name string, each func(context.Context, func(*T) error) error) {
+	d.key(name)
+	d.write("[")
+	if d.err != nil {
+		return
+	}
+
+	rows := 0
+	err := each(ctx, func(row *T) error {
+		if rows > 0 {
+			d.write(",")
+		}
+		rows++
+		d.write("\n")
+		d.value(row)
+		return d.err
+	})
+	if d.err == nil {
+		d.err = err
+	}
+
+	d.write("]")
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/importer"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+const (
+	// MaxImportSize is the largest export document accepted, in bytes
+	MaxImportSize = 50 << 20
+
+	// MaxImportRows limits the folders, boards, items, reminders and habit
+	// completions of one import together
+	MaxImportRows = 100000
+
+	maxFolderNameLength  = 255
+	maxFolderColorLength = 7
+	maxFolderIconLength  = 50
+	maxBoardNameLength   = 255
+)
+
+// errDryRun rolls back the transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+type ImportService struct {
+	userRepo   repository.UserRepository
+	importRepo repository.ImportRepository
+	importers  importer.Registry
+}
+
+func NewImportService(userRepo repository.UserRepository, importRepo repository.ImportRepository, importers importer.Registry) *ImportService {
+	return &ImportService{
+		userRepo:   userRepo,
+		importRepo: importRepo,
+		importers:  importers,
+	}
+}
+
+// HasSource reports whether an importer is registered for source
+func (s *ImportService) HasSource(source string) bool {
+	_, ok := s.importers[source]
+	return ok
+}
+
+// Import reads an export document of this app from r and imports it,
+// see ImportDocument
+func (s *ImportService) Import(ctx context.Context, userID int64, r io.Reader, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	var doc domain.ExportDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+			errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, domain.NewBadRequestError("invalid export document")
+		}
+		return nil, err
+	}
+
+	if doc.Format != domain.ExportFormat {
+		return nil, domain.NewBadRequestError("not an export of this app")
+	}
+	if doc.Version < 1 || doc.Version > domain.ExportVersion {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("unsupported export version %d", doc.Version))
+	}
+
+	return s.ImportDocument(ctx, userID, &doc, opts)
+}
+
+// ImportFrom converts the export of another app, read from r, with the
+// importer registered for source and imports the result, see ImportDocument.
+// filename is the name of the uploaded file, if any; some importers take the
+// board name from it.
+func (s *ImportService) ImportFrom(ctx context.Context, userID int64, source string, r io.Reader, filename string, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	conv, ok := s.importers[source]
+	if !ok {
+		return nil, domain.NewBadRequestError("unknown import source")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := conv.Convert(data, importer.Options{
+		Filename: filename,
+		Location: user.Location(),
+		Now:      time.Now(),
+		MaxSize:  MaxImportSize,
+		MaxRows:  MaxImportRows,
+	})
+	if err != nil {
+		if errors.Is(err, importer.ErrInvalidExport) {
+			return nil, domain.NewBadRequestError(err.Error())
+		}
+		return nil, err
+	}
+
+	return s.ImportDocument(ctx, userID, doc, opts)
+}
+
+// ImportDocument recreates the folders, boards, items, reminders and habit
+// completions of doc in the user's account under new IDs. Settings are not
+// imported. Everything is written in one transaction: a document that fails
+// halfway leaves the account unchanged, and so does a dry run.
+func (s *ImportService) ImportDocument(ctx context.Context, userID int64, doc *domain.ExportDocument, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = domain.ImportModeCopy
+	}
+	if !opts.Mode.IsValid() {
+		return nil, domain.ErrInvalidInput
+	}
+
+	plan, err := planImport(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ImportResult{Mode: opts.Mode, DryRun: opts.DryRun}
+	err = s.importRepo.Transaction(ctx, userID, func(tx repository.ImportTx) error {
+		if err := plan.write(ctx, tx, opts.Mode, result); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// importPlan is a validated document with new IDs for its items
+type importPlan struct {
+	doc *domain.ExportDocument
+	// items lists parent items before their children
+	items       []domain.Item
+	itemIDs     map[uuid.UUID]uuid.UUID
+	completions []domain.HabitCompletion
+}
+
+// planImport validates the references and fields of doc and orders its items
+// for insertion
+func planImport(doc *domain.ExportDocument) (*importPlan, error) {
+	rows := len(doc.Folders) + len(doc.Boards) + len(doc.Items) + len(doc.Reminders) + len(doc.HabitCompletions)
+	if rows > MaxImportRows {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("too many rows, at most %d can be imported at once", MaxImportRows))
+	}
+
+	folders := make(map[uuid.UUID]bool, len(doc.Folders))
+	for _, folder := range doc.Folders {
+		switch {
+		case folders[folder.ID]:
+			return nil, invalidRow("folder", folder.ID, "duplicate id")
+		case !validName(folder.Name, maxFolderNameLength):
+			return nil, invalidRow("folder", folder.ID, "invalid name")
+		case utf8.RuneCountInString(folder.Color) > maxFolderColorLength:
+			return nil, invalidRow("folder", folder.ID, "invalid color")
+		case utf8.RuneCountInString(folder.Icon) > maxFolderIconLength:
+			return nil, invalidRow("folder", folder.ID, "invalid icon")
+		}
+		folders[folder.ID] = true
+	}
+
+	boards := make(map[uuid.UUID]bool, len(doc.Boards))
+	for _, board := range doc.Boards {
+		switch {
+		case boards[board.ID]:
+			return nil, invalidRow("board", board.ID, "duplicate id")
+		case !folders[board.FolderID]:
+			return nil, invalidRow("board", board.ID, "unknown folder")
+		case !validName(board.Name, maxBoardNameLength):
+			return nil, invalidRow("board", board.ID, "invalid name")
+		case !board.Type.IsValid():
+			return nil, invalidRow("board", board.ID, "invalid type")
+		}
+		boards[board.ID] = true
+	}
+
+	items := make(map[uuid.UUID]*domain.Item, len(doc.Items))
+	for i := range doc.Items {
+		item := &doc.Items[i]
+		if item.Status == "" {
+			item.Status = domain.ItemStatusPending
+		}
+		switch {
+		case items[item.ID] != nil:
+			return nil, invalidRow("item", item.ID, "duplicate id")
+		case !boards[item.BoardID]:
+			return nil, invalidRow("item", item.ID, "unknown board")
+		case !validName(item.Title, maxItemTitleLength):
+			return nil, invalidRow("item", item.ID, "invalid title")
+		case !item.Status.IsValid():
+			return nil, invalidRow("item", item.ID, "invalid status")
+		case len(item.Metadata) > 0 && !json.Valid(item.Metadata):
+			return nil, invalidRow("item", item.ID, "invalid metadata")
+		case validateItemMetadata(item) != nil:
+			return nil, invalidRow("item", item.ID, "invalid metadata")
+		}
+
+		// The next occurrence it points to is not the imported copy
+		if len(item.Metadata) > 0 {
+			metadata, err := domain.MergeMetadata(item.Metadata, map[string]interface{}{"next_occurrence_id": nil})
+			if err != nil {
+				return nil, invalidRow("item", item.ID, "invalid metadata")
+			}
+			item.Metadata = metadata
+		}
+		items[item.ID] = item
+	}
+
+	// Order the items by their depth in the tree, following each parent chain
+	// at most len(items) steps to detect cycles
+	depths := make(map[uuid.UUID]int, len(doc.Items))
+	var depthOf func(item *domain.Item, steps int) (int, error)
+	depthOf = func(item *domain.Item, steps int) (int, error) {
+		if depth, ok := depths[item.ID]; ok {
+			return depth, nil
+		}
+		if item.ParentID == nil {
+			depths[item.ID] = 0
+			return 0, nil
+		}
+		parent := items[*item.ParentID]
+		if parent == nil {
+			return 0, invalidRow("item", item.ID, "unknown parent")
+		}
+		if parent.BoardID != item.BoardID {
+			return 0, invalidRow("item", item.ID, "parent on another board")
+		}
+		if steps > len(items) {
+			return 0, invalidRow("item", item.ID, "parent cycle")
+		}
+		depth, err := depthOf(parent, steps+1)
+		if err != nil {
+			return 0, err
+		}
+		depths[item.ID] = depth + 1
+		return depth + 1, nil
+	}
+
+	plan := &importPlan{
+		doc:     doc,
+		items:   make([]domain.Item, 0, len(doc.Items)),
+		itemIDs: make(map[uuid.UUID]uuid.UUID, len(doc.Items)),
+	}
+
+	var levels [][]domain.Item
+	for _, item := range doc.Items {
+		depth, err := depthOf(items[item.ID], 0)
+		if err != nil {
+			return nil, err
+		}
+		for len(levels) <= depth {
+			levels = append(levels, nil)
+		}
+		levels[depth] = append(levels[depth], item)
+		plan.itemIDs[item.ID] = uuid.New()
+	}
+	for _, level := range levels {
+		plan.items = append(plan.items, level...)
+	}
+
+	for _, reminder := range doc.Reminders {
+		if items[reminder.ItemID] == nil {
+			return nil, invalidRow("reminder", reminder.ID, "unknown item")
+		}
+		if reminder.RemindAt.IsZero() {
+			return nil, invalidRow("reminder", reminder.ID, "missing remind_at")
+		}
+	}
+
+	// A habit is completed at most once a day
+	type completionKey struct {
+		itemID uuid.UUID
+		date   string
+	}
+	seen := make(map[completionKey]bool, len(doc.HabitCompletions))
+	for _, completion := range doc.HabitCompletions {
+		if items[completion.ItemID] == nil {
+			return nil, invalidRow("habit completion", completion.ID, "unknown item")
+		}
+		key := completionKey{completion.ItemID, completion.CompletedDate.Format("2006-01-02")}
+		if !seen[key] {
+			seen[key] = true
+			plan.completions = append(plan.completions, completion)
+		}
+	}
+
+	return plan, nil
+}
+
+// write creates the rows of the plan. Folders and boards are placed after the
+// existing ones; in merge mode, those matching an existing folder (by name)
+// or board (by name and type) are added to it instead.
+func (p *importPlan) write(ctx context.Context, tx repository.ImportTx, mode domain.ImportMode, result *domain.ImportResult) error {
+	existing, err := tx.Folders(ctx)
+	if err != nil {
+		return err
+	}
+
+	folderPosition := 0
+	for _, folder := range existing {
+		folderPosition = max(folderPosition, folder.Position+1)
+	}
+
+	folderIDs := make(map[uuid.UUID]uuid.UUID, len(p.doc.Folders))
+	merged := make(map[uuid.UUID]*domain.Folder)
+	for _, folder := range p.doc.Folders {
+		if mode == domain.ImportModeMerge {
+			if match := findFolder(existing, folder.Name); match != nil {
+				folderIDs[folder.ID] = match.ID
+				merged[match.ID] = match
+				result.FoldersMerged++
+				continue
+			}
+		}
+
+		created := folder
+		created.ID = uuid.New()
+		created.Position += folderPosition
+		created.Boards = nil
+		if err := tx.CreateFolder(ctx, &created); err != nil {
+			return err
+		}
+		folderIDs[folder.ID] = created.ID
+		result.FoldersCreated++
+	}
+
+	// Boards go after the existing boards of merged folders
+	boardPositions := make(map[uuid.UUID]int)
+	for id, folder := range merged {
+		for _, board := range folder.Boards {
+			boardPositions[id] = max(boardPositions[id], board.Position+1)
+		}
+	}
+
+	boardIDs := make(map[uuid.UUID]uuid.UUID, len(p.doc.Boards))
+	mergedBoards := make(map[uuid.UUID]bool)
+	for _, board := range p.doc.Boards {
+		folderID := folderIDs[board.FolderID]
+		if folder := merged[folderID]; folder != nil {
+			if match := findBoard(folder.Boards, board.Name, board.Type); match != nil {
+				boardIDs[board.ID] = match.ID
+				mergedBoards[match.ID] = true
+				result.BoardsMerged++
+				continue
+			}
+		}
+
+		created := board
+		created.ID = uuid.New()
+		created.FolderID = folderID
+		created.Position += boardPositions[folderID]
+		created.Items = nil
+		if err := tx.CreateBoard(ctx, &created); err != nil {
+			return err
+		}
+		boardIDs[board.ID] = created.ID
+		result.BoardsCreated++
+	}
+
+	// Top-level items go after the existing items of merged boards
+	itemPositions := make(map[uuid.UUID]int)
+	for id := range mergedBoards {
+		position, err := tx.MaxItemPosition(ctx, id)
+		if err != nil {
+			return err
+		}
+		itemPositions[id] = position + 1
+	}
+
+	for _, item := range p.items {
+		created := item
+		created.ID = p.itemIDs[item.ID]
+		created.BoardID = boardIDs[item.BoardID]
+		created.Children = nil
+		created.NextOccurrence = nil
+		if item.ParentID != nil {
+			parentID := p.itemIDs[*item.ParentID]
+			created.ParentID = &parentID
+		} else {
+			created.Position += itemPositions[created.BoardID]
+		}
+		if err := tx.CreateItem(ctx, &created); err != nil {
+			return err
+		}
+		result.ItemsCreated++
+	}
+
+	now := time.Now()
+	for _, reminder := range p.doc.Reminders {
+		created := reminder
+		created.ID = uuid.New()
+		created.ItemID = p.itemIDs[reminder.ItemID]
+		created.Item = nil
+		// Reminders that came due meanwhile would all fire at once
+		if !created.Sent && created.RemindAt.Before(now) {
+			created.Sent = true
+			created.SentAt = &now
+		}
+		if err := tx.CreateReminder(ctx, &created); err != nil {
+			return err
+		}
+		result.RemindersCreated++
+	}
+
+	for _, completion := range p.completions {
+		created := completion
+		created.ID = uuid.New()
+		created.ItemID = p.itemIDs[completion.ItemID]
+		if err := tx.CreateHabitCompletion(ctx, &created); err != nil {
+			return err
+		}
+		result.HabitCompletionsCreated++
+	}
+
+	return nil
+}
+
+func findFolder(folders []domain.Folder, name string) *domain.Folder {
+	for i := range folders {
+		if strings.EqualFold(strings.TrimSpace(folders[i].Name), strings.TrimSpace(name)) {
+			return &folders[i]
+		}
+	}
+	return nil
+}
+
+func findBoard(boards []domain.Board, name string, boardType domain.BoardType) *domain.Board {
+	for i := range boards {
+		if boards[i].Type == boardType && strings.EqualFold(strings.TrimSpace(boards[i].Name), strings.TrimSpace(name)) {
+			return &boards[i]
+		}
+	}
+	return nil
+}
+
+func validName(name string, maxLength int) bool {
+	return strings.TrimSpace(name) != "" && utf8.RuneCountInString(name) <= maxLength
+}
+
+func invalidRow(kind string, id uuid.UUID, reason string) error {
+	return domain.NewBadRequestError(fmt.Sprintf("%s %s: %s", kind, id, reason))
+}
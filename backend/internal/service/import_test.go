@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+func TestPlanImport(t *testing.T) {
+	folder := domain.Folder{ID: uuid.New(), Name: "Folder"}
+	board := domain.Board{ID: uuid.New(), FolderID: folder.ID, Name: "Tasks", Type: domain.BoardTypeKanban}
+	other := domain.Board{ID: uuid.New(), FolderID: folder.ID, Name: "Other", Type: domain.BoardTypeKanban}
+	parent := domain.Item{ID: uuid.New(), BoardID: board.ID, Title: "Parent"}
+
+	doc := func(items ...domain.Item) *domain.ExportDocument {
+		return &domain.ExportDocument{
+			Folders: []domain.Folder{folder},
+			Boards:  []domain.Board{board, other},
+			Items:   append([]domain.Item{parent}, items...),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		item    domain.Item
+		wantErr string
+	}{
+		{
+			name: "child",
+			item: domain.Item{ID: uuid.New(), BoardID: board.ID, ParentID: &parent.ID, Title: "Child"},
+		},
+		{
+			name:    "parent on another board",
+			item:    domain.Item{ID: uuid.New(), BoardID: other.ID, ParentID: &parent.ID, Title: "Child"},
+			wantErr: "parent on another board",
+		},
+		{
+			name:    "unknown parent",
+			item:    domain.Item{ID: uuid.New(), BoardID: board.ID, ParentID: &other.ID, Title: "Child"},
+			wantErr: "unknown parent",
+		},
+		{
+			name: "recurring",
+			item: domain.Item{ID: uuid.New(), BoardID: board.ID, Title: "Weekly",
+				Metadata: json.RawMessage(`{"recur_rule":"FREQ=WEEKLY"}`)},
+		},
+		{
+			name: "invalid recurrence rule",
+			item: domain.Item{ID: uuid.New(), BoardID: board.ID, Title: "Weekly",
+				Metadata: json.RawMessage(`{"recur_rule":"FREQ=SOMETIMES"}`)},
+			wantErr: "invalid metadata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := planImport(doc(tt.item))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want := "item " + tt.item.ID.String() + ": " + tt.wantErr
+			var appErr *domain.AppError
+			if !errors.As(err, &appErr) || appErr.Message != want {
+				t.Errorf("error = %v, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestPlanImportDropsNextOccurrence(t *testing.T) {
+	folder := domain.Folder{ID: uuid.New(), Name: "Folder"}
+	board := domain.Board{ID: uuid.New(), FolderID: folder.ID, Name: "Tasks", Type: domain.BoardTypeKanban}
+	item := domain.Item{ID: uuid.New(), BoardID: board.ID, Title: "Weekly", Status: domain.ItemStatusCompleted,
+		Metadata: json.RawMessage(`{"recur_rule":"FREQ=WEEKLY","next_occurrence_id":"` + uuid.NewString() + `"}`)}
+
+	plan, err := planImport(&domain.ExportDocument{
+		Folders: []domain.Folder{folder},
+		Boards:  []domain.Board{board},
+		Items:   []domain.Item{item},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := plan.items[0].ParseMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.NextOccurrenceID != "" || meta.RecurRule != "FREQ=WEEKLY" {
+		t.Errorf("metadata = %s, want the rule without the next occurrence", plan.items[0].Metadata)
+	}
+}
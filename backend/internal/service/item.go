@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/telegram-task-manager/backend/internal/domain"
 	"github.com/telegram-task-manager/backend/internal/repository"
+	"github.com/telegram-task-manager/backend/pkg/naturaldate"
+	"github.com/telegram-task-manager/backend/pkg/rrule"
 )
 
 type ItemService struct {
@@ -15,6 +20,7 @@ type ItemService struct {
 	reminderRepo   repository.ReminderRepository
 	activityRepo   repository.ActivityLogRepository
 	habitRepo      repository.HabitCompletionRepository
+	userRepo       repository.UserRepository
 }
 
 func NewItemService(
@@ -23,6 +29,7 @@ func NewItemService(
 	reminderRepo repository.ReminderRepository,
 	activityRepo repository.ActivityLogRepository,
 	habitRepo repository.HabitCompletionRepository,
+	userRepo repository.UserRepository,
 ) *ItemService {
 	return &ItemService{
 		itemRepo:     itemRepo,
@@ -30,6 +37,7 @@ func NewItemService(
 		reminderRepo: reminderRepo,
 		activityRepo: activityRepo,
 		habitRepo:    habitRepo,
+		userRepo:     userRepo,
 	}
 }
 
@@ -115,10 +123,18 @@ func (s *ItemService) CreateItem(ctx context.Context, userID int64, boardID uuid
 		item.Status = domain.ItemStatusPending
 	}
 
+	if err := validateItemMetadata(item); err != nil {
+		return nil, err
+	}
+
 	if err := s.itemRepo.Create(ctx, item); err != nil {
 		return nil, err
 	}
 
+	if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+		return nil, err
+	}
+
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
 		UserID:     userID,
@@ -130,6 +146,48 @@ func (s *ItemService) CreateItem(ctx context.Context, userID int64, boardID uuid
 	return item, nil
 }
 
+// QuickAddItem creates an item from free-form text. Date phrases such as
+// "завтра в 15:00" or "каждый вторник" are resolved in the user's timezone
+// into the due date and recurrence rule and removed from the title.
+func (s *ItemService) QuickAddItem(ctx context.Context, userID int64, boardID uuid.UUID, text string) (*domain.Item, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	parsed := naturaldate.Parse(text, time.Now().In(loc))
+
+	title := parsed.Text
+	if title == "" {
+		// The whole text was a date, keep it as the title
+		title = text
+	}
+
+	req := &domain.CreateItemRequest{
+		Title:   truncateRunes(title, maxItemTitleLength),
+		DueDate: parsed.Due,
+	}
+
+	fields := map[string]interface{}{}
+	if parsed.Due != nil && !parsed.HasTime {
+		fields["all_day"] = true
+	}
+	if parsed.RecurRule != "" {
+		fields["recur_rule"] = parsed.RecurRule
+	}
+	if len(fields) > 0 {
+		metadata, err := domain.MergeMetadata(nil, fields)
+		if err != nil {
+			return nil, err
+		}
+		req.Metadata = metadata
+	}
+
+	return s.CreateItem(ctx, userID, boardID, req)
+}
+
 // UpdateItem updates an existing item
 func (s *ItemService) UpdateItem(ctx context.Context, userID int64, itemID uuid.UUID, req *domain.UpdateItemRequest) (*domain.Item, error) {
 	item, err := s.itemRepo.GetByID(ctx, itemID)
@@ -147,6 +205,8 @@ func (s *ItemService) UpdateItem(ctx context.Context, userID int64, itemID uuid.
 		return nil, domain.ErrForbidden
 	}
 
+	before := *item
+
 	// Apply updates
 	if req.Title != nil {
 		item.Title = *req.Title
@@ -170,10 +230,22 @@ func (s *ItemService) UpdateItem(ctx context.Context, userID int64, itemID uuid.
 		item.CompletedAt = req.CompletedAt
 	}
 
+	if err := validateItemMetadata(item); err != nil {
+		return nil, err
+	}
+
 	if err := s.itemRepo.Update(ctx, item); err != nil {
 		return nil, err
 	}
 
+	// Reminders follow the due date, the offsets and the completion state.
+	// Other edits leave them alone, including any the scheduler just claimed.
+	if relativeRemindersChanged(&before, item) {
+		if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+			return nil, err
+		}
+	}
+
 	// Log activity
 	_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
 		UserID:     userID,
@@ -220,7 +292,9 @@ func (s *ItemService) DeleteItem(ctx context.Context, userID int64, itemID uuid.
 	return nil
 }
 
-// CompleteItem marks an item as completed or uncompleted
+// CompleteItem marks an item as completed or uncompleted. Completing a
+// recurring item creates its next occurrence, only once per item however
+// often it is reopened and completed again.
 func (s *ItemService) CompleteItem(ctx context.Context, userID int64, itemID uuid.UUID, completed bool) (*domain.Item, error) {
 	item, err := s.itemRepo.GetByID(ctx, itemID)
 	if err != nil {
@@ -237,7 +311,26 @@ func (s *ItemService) CompleteItem(ctx context.Context, userID int64, itemID uui
 		return nil, domain.ErrForbidden
 	}
 
-	if err := s.itemRepo.Complete(ctx, itemID, completed); err != nil {
+	var next *domain.Item
+	var nextReminders []domain.Reminder
+	if completed {
+		next, nextReminders, err = s.nextOccurrence(ctx, userID, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if next != nil {
+		// The repository decides whether this request completed the item, so
+		// repeated and concurrent requests don't create duplicates
+		spawned, err := s.itemRepo.CompleteRecurring(ctx, itemID, next, nextReminders)
+		if err != nil {
+			return nil, err
+		}
+		if !spawned {
+			next = nil
+		}
+	} else if err := s.itemRepo.Complete(ctx, itemID, completed); err != nil {
 		return nil, err
 	}
 
@@ -247,6 +340,22 @@ func (s *ItemService) CompleteItem(ctx context.Context, userID int64, itemID uui
 		return nil, err
 	}
 
+	// Completing removes relative reminders, reopening restores those still ahead
+	if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+		return nil, err
+	}
+
+	if next != nil {
+		item.NextOccurrence = next
+
+		_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
+			UserID:     userID,
+			Action:     "create",
+			EntityType: "item",
+			EntityID:   next.ID,
+		})
+	}
+
 	// Log activity
 	action := "complete"
 	if !completed {
@@ -262,6 +371,127 @@ func (s *ItemService) CompleteItem(ctx context.Context, userID int64, itemID uui
 	return item, nil
 }
 
+// nextOccurrence prepares the next instance of a recurring item, with its
+// subtasks and reminders, for the repository to create. Returns nil without
+// error when the item has no (supported) recurrence rule, already created its
+// next occurrence or the series has ended.
+func (s *ItemService) nextOccurrence(ctx context.Context, userID int64, item *domain.Item) (*domain.Item, []domain.Reminder, error) {
+	meta, err := item.ParseMetadata()
+	if err != nil || meta.RecurRule == "" || meta.NextOccurrenceID != "" {
+		return nil, nil, nil
+	}
+
+	rule, err := rrule.Parse(meta.RecurRule)
+	if err != nil {
+		// Rules are checked when items are saved, this one predates that
+		slog.Warn("skipping next occurrence of item with invalid recurrence rule",
+			"item_id", item.ID, "recur_rule", meta.RecurRule, "error", err)
+		return nil, nil, nil
+	}
+
+	// Weekdays and days of month must be evaluated in the user's wall clock
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	now := time.Now()
+	dtstart := now
+	if item.DueDate != nil {
+		dtstart = *item.DueDate
+	}
+	dtstart = dtstart.In(loc)
+
+	// Skip occurrences that are already in the past, so completing an overdue
+	// chore doesn't immediately produce another overdue copy
+	after := dtstart
+	if now.After(after) {
+		after = now
+	}
+
+	next, index, ok := rule.After(dtstart, after)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	fields := map[string]interface{}{"next_occurrence_id": nil}
+	if rule.Count > 0 {
+		// COUNT is carried over as the number of occurrences left in the series
+		rule.Count -= index
+		fields["recur_rule"] = rule.String()
+	}
+	metadata, err := domain.MergeMetadata(item.Metadata, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextItem := &domain.Item{
+		BoardID:  item.BoardID,
+		ParentID: item.ParentID,
+		Title:    item.Title,
+		Content:  item.Content,
+		Status:   domain.ItemStatusPending,
+		DueDate:  &next,
+		Metadata: metadata,
+	}
+
+	shift := next.Sub(dtstart)
+
+	// Carry over subtasks, reset to pending
+	withChildren, err := s.itemRepo.GetWithChildren(ctx, item.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, child := range withChildren.Children {
+		copied := domain.Item{
+			BoardID:  nextItem.BoardID,
+			Title:    child.Title,
+			Content:  child.Content,
+			Status:   domain.ItemStatusPending,
+			Position: child.Position,
+			Metadata: child.Metadata,
+		}
+		if child.DueDate != nil {
+			due := child.DueDate.Add(shift)
+			copied.DueDate = &due
+		}
+		nextItem.Children = append(nextItem.Children, copied)
+	}
+
+	// Carry over reminders, shifted by the same amount as the due date.
+	// Without a due date there is nothing to anchor them to.
+	var reminders []domain.Reminder
+	if item.DueDate != nil {
+		existing, err := s.reminderRepo.GetByItemID(ctx, item.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, reminder := range existing {
+			// Relative reminders are recreated from the offsets below
+			if reminder.Offset != nil {
+				continue
+			}
+
+			remindAt := reminder.RemindAt.Add(shift)
+			if remindAt.Before(now) {
+				continue
+			}
+
+			reminders = append(reminders, domain.Reminder{
+				UserID:   reminder.UserID,
+				RemindAt: remindAt,
+				Message:  reminder.Message,
+			})
+		}
+	}
+
+	relative, err := s.relativeReminders(ctx, userID, nextItem)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nextItem, append(reminders, relative...), nil
+}
+
 // ReorderItems updates item positions in a board
 func (s *ItemService) ReorderItems(ctx context.Context, userID int64, boardID uuid.UUID, itemIDs []uuid.UUID) error {
 	// Check board ownership
@@ -307,21 +537,260 @@ func (s *ItemService) MoveItem(ctx context.Context, userID int64, itemID uuid.UU
 	// Update item
 	item.BoardID = req.BoardID
 	item.Position = req.Position
+	if req.DueDate != nil {
+		item.DueDate = req.DueDate
+	}
 
 	if err := s.itemRepo.Update(ctx, item); err != nil {
 		return nil, err
 	}
 
+	// The due date or the board's default offsets may have changed
+	if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+		return nil, err
+	}
+
 	return item, nil
 }
 
-// SetReminder creates a reminder for an item
+// SetReminder creates a reminder for an item, either at an absolute time or
+// relative to the item's due date. A relative reminder is added to the item's
+// offsets and follows later changes of the due date.
 func (s *ItemService) SetReminder(ctx context.Context, userID int64, itemID uuid.UUID, req *domain.CreateReminderRequest) (*domain.Reminder, error) {
+	if (req.RemindAt == nil) == (req.Offset == nil) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check ownership
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, item.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	if req.Offset != nil {
+		return s.addRelativeReminder(ctx, userID, item, *req.Offset)
+	}
+
 	// Validate reminder time is in the future (with 1 minute buffer for clock skew)
 	if req.RemindAt.Before(time.Now().Add(-1 * time.Minute)) {
 		return nil, domain.ErrInvalidInput
 	}
 
+	reminder := &domain.Reminder{
+		UserID:   userID,
+		ItemID:   itemID,
+		RemindAt: *req.RemindAt,
+		Message:  req.Message,
+	}
+
+	if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// addRelativeReminder adds an offset to the item's reminder offsets, starting
+// from the board's defaults when the item has none of its own
+func (s *ItemService) addRelativeReminder(ctx context.Context, userID int64, item *domain.Item, offset domain.ReminderOffset) (*domain.Reminder, error) {
+	if err := offset.Validate(); err != nil {
+		return nil, err
+	}
+
+	if item.DueDate == nil || offset.RemindAt(*item.DueDate, userLocation(ctx, s.userRepo, userID)).Before(time.Now().Add(-1*time.Minute)) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	offsets, err := s.reminderOffsets(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(offsets, offset) {
+		offsets = append(offsets, offset)
+	}
+	if err := domain.ValidateReminderOffsets(offsets); err != nil {
+		return nil, err
+	}
+
+	metadata, err := domain.MergeMetadata(item.Metadata, map[string]interface{}{
+		"reminder_offsets": offsets,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item.Metadata = metadata
+	if err := s.itemRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.syncRelativeReminders(ctx, userID, item)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reminders {
+		if *reminders[i].Offset == offset {
+			return &reminders[i], nil
+		}
+	}
+
+	// The reminder time passed within the clock skew buffer
+	return nil, domain.ErrInvalidInput
+}
+
+// syncRelativeReminders recreates the item's relative reminders from its
+// offsets
+func (s *ItemService) syncRelativeReminders(ctx context.Context, userID int64, item *domain.Item) ([]domain.Reminder, error) {
+	if err := s.reminderRepo.DeleteRelativeByItemID(ctx, item.ID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.relativeReminders(ctx, userID, item)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reminders {
+		if err := s.reminderRepo.Create(ctx, &reminders[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return reminders, nil
+}
+
+// syncBoardRelativeReminders recreates the relative reminders of the board's
+// open items that follow the board's default offsets
+func (s *ItemService) syncBoardRelativeReminders(ctx context.Context, userID int64, boardID uuid.UUID) error {
+	items, err := s.itemRepo.GetAllByBoardID(ctx, boardID)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.DueDate == nil || item.Status == domain.ItemStatusCompleted || item.Status == domain.ItemStatusArchived {
+			continue
+		}
+		if meta, err := item.ParseMetadata(); err == nil && meta.ReminderOffsets != nil {
+			continue
+		}
+
+		if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relativeReminders returns the relative reminders the item should have.
+// Open items with a due date get a reminder for every offset that is still
+// ahead; completed and archived items and items without a due date get none.
+func (s *ItemService) relativeReminders(ctx context.Context, userID int64, item *domain.Item) ([]domain.Reminder, error) {
+	if item.DueDate == nil || item.Status == domain.ItemStatusCompleted || item.Status == domain.ItemStatusArchived {
+		return nil, nil
+	}
+
+	offsets, err := s.reminderOffsets(ctx, item)
+	if err != nil || len(offsets) == 0 {
+		return nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now()
+
+	var reminders []domain.Reminder
+	for _, offset := range offsets {
+		if offset.Validate() != nil {
+			continue
+		}
+
+		remindAt := offset.RemindAt(*item.DueDate, loc)
+		if remindAt.Before(now) {
+			continue
+		}
+
+		reminders = append(reminders, domain.Reminder{
+			UserID:   userID,
+			ItemID:   item.ID,
+			RemindAt: remindAt,
+			Offset:   &offset,
+		})
+	}
+
+	return reminders, nil
+}
+
+// relativeRemindersChanged reports whether an edit changed what the item's
+// relative reminders depend on: the due date, the status or its own offsets
+func relativeRemindersChanged(before, after *domain.Item) bool {
+	if (before.DueDate == nil) != (after.DueDate == nil) || (before.DueDate != nil && !before.DueDate.Equal(*after.DueDate)) {
+		return true
+	}
+	if before.Status != after.Status {
+		return true
+	}
+
+	// A missing list (the board's defaults) differs from an empty one (none)
+	beforeMeta, _ := before.ParseMetadata()
+	afterMeta, _ := after.ParseMetadata()
+	return (beforeMeta.ReminderOffsets == nil) != (afterMeta.ReminderOffsets == nil) ||
+		!slices.Equal(beforeMeta.ReminderOffsets, afterMeta.ReminderOffsets)
+}
+
+// reminderOffsets returns the item's own offsets, or the board's defaults when
+// the item does not set any
+func (s *ItemService) reminderOffsets(ctx context.Context, item *domain.Item) ([]domain.ReminderOffset, error) {
+	// Metadata the frontend wrote in another shape has no usable offsets
+	meta, err := item.ParseMetadata()
+	if err == nil && meta.ReminderOffsets != nil {
+		return meta.ReminderOffsets, nil
+	}
+
+	board, err := s.boardRepo.GetByID(ctx, item.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := board.ParseSettings()
+	if err != nil {
+		return nil, nil
+	}
+
+	return settings.ReminderOffsets, nil
+}
+
+// validateItemMetadata rejects invalid reminder offsets and recurrence rules
+// in the item's metadata
+func validateItemMetadata(item *domain.Item) error {
+	meta, err := item.ParseMetadata()
+	if err != nil {
+		return nil
+	}
+
+	if meta.RecurRule != "" {
+		if _, err := rrule.Parse(meta.RecurRule); err != nil {
+			return domain.ErrInvalidInput
+		}
+	}
+
+	return domain.ValidateReminderOffsets(meta.ReminderOffsets)
+}
+
+// SnoozeItem schedules a new reminder for an item after a reminder was postponed from the chat
+func (s *ItemService) SnoozeItem(ctx context.Context, userID int64, itemID uuid.UUID, option domain.SnoozeOption) (*domain.Reminder, error) {
 	item, err := s.itemRepo.GetByID(ctx, itemID)
 	if err != nil {
 		return nil, err
@@ -337,17 +806,24 @@ func (s *ItemService) SetReminder(ctx context.Context, userID int64, itemID uuid
 		return nil, domain.ErrForbidden
 	}
 
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	remindAt, ok := option.Until(time.Now(), loc)
+	if !ok {
+		return nil, domain.ErrInvalidInput
+	}
+
 	reminder := &domain.Reminder{
 		UserID:   userID,
 		ItemID:   itemID,
-		RemindAt: req.RemindAt,
-		Message:  req.Message,
+		RemindAt: remindAt,
 	}
 
 	if err := s.reminderRepo.Create(ctx, reminder); err != nil {
 		return nil, err
 	}
 
+	reminder.Item = item
 	return reminder, nil
 }
 
@@ -440,3 +916,11 @@ func (s *ItemService) GetHabitStreak(ctx context.Context, userID int64, itemID u
 func (s *ItemService) GetDueSoonItems(ctx context.Context, userID int64, within time.Duration) ([]domain.Item, error) {
 	return s.itemRepo.GetDueSoon(ctx, userID, within)
 }
+
+// userLocation returns the user's timezone, falling back to UTC
+func userLocation(ctx context.Context, userRepo repository.UserRepository, userID int64) *time.Location {
+	if user, err := userRepo.GetByID(ctx, userID); err == nil {
+		return user.Location()
+	}
+	return time.UTC
+}
@@ -0,0 +1,611 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/pkg/rrule"
+)
+
+const (
+	// MaxItemCSVImportSize is the largest CSV upload accepted, in bytes
+	MaxItemCSVImportSize = 5 << 20
+
+	// MaxItemCSVImportRows limits the number of rows in one import
+	MaxItemCSVImportRows = 5000
+
+	// csvBOM makes spreadsheet apps read the file as UTF-8
+	csvBOM = "\uFEFF"
+
+	// csvListSeparator joins the values of list metadata (labels, target days) in one cell
+	csvListSeparator = ";"
+)
+
+// itemCSVColumns are the columns of every board's CSV
+var itemCSVColumns = []string{"id", "parent_id", "title", "content", "status", "due_date", "position"}
+
+// itemCSVMetadataColumns are the metadata keys added for each board type
+var itemCSVMetadataColumns = map[domain.BoardType][]string{
+	domain.BoardTypeNotes:        {"color"},
+	domain.BoardTypeKanban:       {"column_id", "labels"},
+	domain.BoardTypeTimeManager:  {"start_time", "end_time", "time_slot"},
+	domain.BoardTypeCalendar:     {"all_day", "recur_rule", "location", "event_color"},
+	domain.BoardTypeHabitTracker: {"frequency", "target_days"},
+	domain.BoardTypeChecklist:    {"priority"},
+}
+
+// csvDateLayouts are the due date formats accepted on import besides RFC 3339,
+// read in the user's timezone
+var csvDateLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+// ExportItemsCSV returns the items of a board as CSV, nested items right
+// after their parent. Due dates are written in the user's timezone, those of
+// all-day items without a time.
+func (s *ItemService) ExportItemsCSV(ctx context.Context, userID int64, boardID uuid.UUID) (*domain.Board, []byte, error) {
+	board, err := s.ownedBoard(ctx, userID, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.itemRepo.GetAllByBoardID(ctx, boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	columns := itemCSVHeader(board.Type)
+
+	var buf bytes.Buffer
+	buf.WriteString(csvBOM)
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, nil, err
+	}
+
+	record := make([]string, len(columns))
+	for _, item := range itemTreeOrder(items) {
+		// Metadata is flattened as stored, whatever shape the frontend wrote
+		var meta map[string]interface{}
+		_ = json.Unmarshal(item.Metadata, &meta)
+
+		for i, column := range columns {
+			record[i] = itemCSVValue(item, meta, column, loc)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	w.Flush()
+	return board, buf.Bytes(), w.Error()
+}
+
+// ImportItemsCSV creates and updates board items from a CSV file in the
+// format of ExportItemsCSV. A row with an id updates that item of the board,
+// a row without one creates an item; columns missing from the file leave the
+// fields as they are. Rows that fail validation are skipped and reported, the
+// others are saved. Completing a recurring item this way does not create its
+// next occurrence.
+func (s *ItemService) ImportItemsCSV(ctx context.Context, userID int64, boardID uuid.UUID, r io.Reader) (*domain.ItemCSVImportResult, error) {
+	board, err := s.ownedBoard(ctx, userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	header, rows, err := readItemCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	allowed := itemCSVHeader(board.Type)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(allowed, name) {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("unknown column %q", name))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("duplicate column %q", name))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		if _, ok := columns["id"]; !ok {
+			return nil, domain.NewBadRequestError("an id or a title column is required")
+		}
+	}
+
+	existing, err := s.itemRepo.GetAllByBoardID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[uuid.UUID]*domain.Item, len(existing))
+	for i := range existing {
+		items[existing[i].ID] = &existing[i]
+	}
+
+	// Metadata in the board settings that cannot be read has no columns to check against
+	settings, _ := board.ParseSettings()
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	result := &domain.ItemCSVImportResult{Errors: []domain.ItemCSVImportError{}}
+	for _, row := range rows {
+		row.columns = columns
+
+		item, isNew, err := row.apply(board, &settings, items, loc)
+		if err != nil {
+			id, _ := row.get("id")
+			result.Skipped++
+			result.Errors = append(result.Errors, domain.ItemCSVImportError{Row: row.line, ID: id, Error: err.Error()})
+			continue
+		}
+
+		action := "update"
+		resync := isNew || relativeRemindersChanged(items[item.ID], item)
+		if isNew {
+			if err := s.itemRepo.Create(ctx, item); err != nil {
+				return nil, err
+			}
+			// Items are created open, the completion time is set apart
+			if item.Status == domain.ItemStatusCompleted {
+				if err := s.itemRepo.Complete(ctx, item.ID, true); err != nil {
+					return nil, err
+				}
+			}
+			items[item.ID] = item
+			action = "create"
+			result.Created++
+		} else {
+			if err := s.itemRepo.Update(ctx, item); err != nil {
+				return nil, err
+			}
+			items[item.ID] = item
+			result.Updated++
+		}
+
+		if resync {
+			if _, err := s.syncRelativeReminders(ctx, userID, item); err != nil {
+				return nil, err
+			}
+		}
+
+		_ = s.activityRepo.Create(ctx, &domain.ActivityLog{
+			UserID:     userID,
+			Action:     action,
+			EntityType: "item",
+			EntityID:   item.ID,
+		})
+	}
+
+	return result, nil
+}
+
+// ownedBoard returns the board after checking that the user owns it
+func (s *ItemService) ownedBoard(ctx context.Context, userID int64, boardID uuid.UUID) (*domain.Board, error) {
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	return s.boardRepo.GetByID(ctx, boardID)
+}
+
+func itemCSVHeader(boardType domain.BoardType) []string {
+	columns := append([]string{}, itemCSVColumns...)
+	return append(columns, itemCSVMetadataColumns[boardType]...)
+}
+
+// itemTreeOrder orders items, given parents before children, depth first
+func itemTreeOrder(items []domain.Item) []*domain.Item {
+	children := make(map[uuid.UUID][]*domain.Item)
+	var roots []*domain.Item
+	for i := range items {
+		item := &items[i]
+		if item.ParentID == nil {
+			roots = append(roots, item)
+		} else {
+			children[*item.ParentID] = append(children[*item.ParentID], item)
+		}
+	}
+
+	ordered := make([]*domain.Item, 0, len(items))
+	var walk func(list []*domain.Item)
+	walk = func(list []*domain.Item) {
+		for _, item := range list {
+			ordered = append(ordered, item)
+			walk(children[item.ID])
+		}
+	}
+	walk(roots)
+	return ordered
+}
+
+func itemCSVValue(item *domain.Item, meta map[string]interface{}, column string, loc *time.Location) string {
+	switch column {
+	case "id":
+		return item.ID.String()
+	case "parent_id":
+		if item.ParentID == nil {
+			return ""
+		}
+		return item.ParentID.String()
+	case "title":
+		return escapeCSVCell(item.Title)
+	case "content":
+		return escapeCSVCell(item.Content)
+	case "status":
+		return string(item.Status)
+	case "due_date":
+		if item.DueDate == nil {
+			return ""
+		}
+		if allDay, _ := meta["all_day"].(bool); allDay {
+			return item.DueDate.In(loc).Format("2006-01-02")
+		}
+		return item.DueDate.In(loc).Format("2006-01-02 15:04")
+	case "position":
+		return strconv.Itoa(item.Position)
+	}
+	return csvCell(meta[column])
+}
+
+// csvCell flattens a metadata value
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeCSVCell(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		// Only the start of a cell can read as a formula, so the joined
+		// cell is escaped once, as itemCSVRow.get unescapes it
+		parts := make([]string, len(v))
+		for i, part := range v {
+			if s, ok := part.(string); ok {
+				parts[i] = s
+			} else {
+				parts[i] = csvCell(part)
+			}
+		}
+		return escapeCSVCell(strings.Join(parts, csvListSeparator+" "))
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// escapeCSVCell keeps spreadsheet apps from running text as a formula by
+// prefixing an apostrophe, which they hide and unescapeCSVCell removes
+func escapeCSVCell(s string) string {
+	if isCSVFormula(s) {
+		return "'" + s
+	}
+	return s
+}
+
+func unescapeCSVCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && isCSVFormula(s[1:]) {
+		return s[1:]
+	}
+	return s
+}
+
+// isCSVFormula reports whether s reads as a formula, or as an escaped one
+// once unescaped, so that text starting with apostrophes survives a round trip
+func isCSVFormula(s string) bool {
+	s = strings.TrimLeft(s, "'")
+	return s != "" && strings.ContainsRune("=+-@", rune(s[0]))
+}
+
+// itemCSVRow is a record of an import with its line in the file
+type itemCSVRow struct {
+	line    int
+	record  []string
+	columns map[string]int
+}
+
+// readItemCSV reads the header and the rows of a CSV file, skipping blank rows
+func readItemCSV(r io.Reader) ([]string, []*itemCSVRow, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(len(csvBOM)); err == nil && string(bom) == csvBOM {
+		_, _ = br.Discard(len(csvBOM))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, domain.NewBadRequestError("the CSV file is empty")
+		}
+		return nil, nil, csvReadError(err)
+	}
+
+	var rows []*itemCSVRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, csvReadError(err)
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if len(rows) == MaxItemCSVImportRows {
+			return nil, nil, domain.NewBadRequestError(fmt.Sprintf("too many rows, at most %d can be imported at once", MaxItemCSVImportRows))
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, &itemCSVRow{line: line, record: record})
+	}
+
+	return header, rows, nil
+}
+
+func csvReadError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.NewBadRequestError(fmt.Sprintf("invalid CSV: %v", parseErr))
+	}
+	return err
+}
+
+// get returns the cell of a column, false when the file has no such column
+func (r *itemCSVRow) get(column string) (string, bool) {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return "", false
+	}
+	value := r.record[i]
+	if column != "content" {
+		value = strings.TrimSpace(value)
+	}
+	return unescapeCSVCell(value), true
+}
+
+// apply validates the row and returns the item it creates or updates
+func (r *itemCSVRow) apply(board *domain.Board, settings *domain.BoardSettings, items map[uuid.UUID]*domain.Item, loc *time.Location) (*domain.Item, bool, error) {
+	var item domain.Item
+	isNew := true
+	if value, _ := r.get("id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, false, errors.New("invalid id")
+		}
+		current := items[id]
+		if current == nil {
+			return nil, false, errors.New("no item with this id on the board")
+		}
+		item = *current
+		isNew = false
+	} else {
+		item = domain.Item{BoardID: board.ID, Status: domain.ItemStatusPending}
+	}
+
+	if value, ok := r.get("parent_id"); ok {
+		var parentID *uuid.UUID
+		if value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return nil, false, errors.New("invalid parent_id")
+			}
+			if items[id] == nil {
+				return nil, false, errors.New("no parent item with this id on the board")
+			}
+			parentID = &id
+		}
+		switch {
+		case isNew:
+			item.ParentID = parentID
+		case (parentID == nil) != (item.ParentID == nil) || (parentID != nil && *parentID != *item.ParentID):
+			return nil, false, errors.New("the parent of an existing item cannot be changed")
+		}
+	}
+
+	if value, ok := r.get("title"); ok {
+		item.Title = value
+	}
+	if strings.TrimSpace(item.Title) == "" {
+		return nil, false, errors.New("title is required")
+	}
+	if utf8.RuneCountInString(item.Title) > maxItemTitleLength {
+		return nil, false, fmt.Errorf("title is longer than %d characters", maxItemTitleLength)
+	}
+
+	if value, ok := r.get("content"); ok {
+		if utf8.RuneCountInString(value) > maxItemContentLength {
+			return nil, false, fmt.Errorf("content is longer than %d characters", maxItemContentLength)
+		}
+		item.Content = value
+	}
+
+	if value, ok := r.get("status"); ok {
+		status := domain.ItemStatus(strings.ToLower(value))
+		if status == "" {
+			status = domain.ItemStatusPending
+		}
+		if !status.IsValid() {
+			return nil, false, fmt.Errorf("invalid status %q", value)
+		}
+		item.Status = status
+	}
+
+	if value, ok := r.get("position"); ok && value != "" {
+		position, err := strconv.Atoi(value)
+		if err != nil || position < 0 {
+			return nil, false, fmt.Errorf("invalid position %q", value)
+		}
+		item.Position = position
+	}
+
+	fields := make(map[string]interface{})
+	if value, ok := r.get("due_date"); ok {
+		due, dateOnly, err := parseCSVDate(value, loc)
+		if err != nil {
+			return nil, false, err
+		}
+		item.DueDate = due
+		// A date without a time is an all-day date, unless the file says otherwise
+		if _, ok := r.columns["all_day"]; !ok {
+			fields["all_day"] = nil
+			if dateOnly {
+				fields["all_day"] = true
+			}
+		}
+	}
+
+	for _, column := range itemCSVMetadataColumns[board.Type] {
+		value, ok := r.get(column)
+		if !ok {
+			continue
+		}
+		parsed, err := parseCSVMetadata(column, value, settings)
+		if err != nil {
+			return nil, false, err
+		}
+		fields[column] = parsed
+	}
+
+	if len(fields) > 0 {
+		metadata, err := domain.MergeMetadata(item.Metadata, fields)
+		if err != nil {
+			return nil, false, errors.New("the item's metadata cannot be updated")
+		}
+		item.Metadata = metadata
+	}
+
+	if item.Status == domain.ItemStatusCompleted {
+		if item.CompletedAt == nil {
+			now := time.Now()
+			item.CompletedAt = &now
+		}
+	} else {
+		item.CompletedAt = nil
+	}
+
+	return &item, isNew, nil
+}
+
+// parseCSVDate reads a due date, reporting whether it was given without a time
+func parseCSVDate(value string, loc *time.Location) (*time.Time, bool, error) {
+	if value == "" {
+		return nil, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, false, nil
+	}
+	for _, layout := range csvDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return &t, false, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return &t, true, nil
+	}
+	return nil, false, fmt.Errorf("invalid due_date %q, expected YYYY-MM-DD or YYYY-MM-DD HH:MM", value)
+}
+
+// parseCSVMetadata reads a metadata cell; empty cells yield nil, which
+// removes the key
+func parseCSVMetadata(column, value string, settings *domain.BoardSettings) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	switch column {
+	case "labels":
+		var labels []string
+		for _, label := range strings.Split(value, csvListSeparator) {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+		return labels, nil
+
+	case "target_days":
+		var days []int
+		for _, part := range strings.Split(value, csvListSeparator) {
+			day, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || day < 0 || day > 6 {
+				return nil, fmt.Errorf("invalid target_days %q, expected weekday numbers 0-6", value)
+			}
+			days = append(days, day)
+		}
+		return days, nil
+
+	case "all_day":
+		allDay, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid all_day %q", value)
+		}
+		if !allDay {
+			return nil, nil
+		}
+		return true, nil
+
+	case "priority":
+		switch value {
+		case "low", "medium", "high":
+			return value, nil
+		}
+		return nil, fmt.Errorf("invalid priority %q, expected low, medium or high", value)
+
+	case "frequency":
+		switch value {
+		case "daily", "weekly":
+			return value, nil
+		}
+		return nil, fmt.Errorf("invalid frequency %q, expected daily or weekly", value)
+
+	case "start_time", "end_time":
+		if _, err := time.Parse("15:04", value); err != nil {
+			return nil, fmt.Errorf("invalid %s %q, expected HH:MM", column, value)
+		}
+		return value, nil
+
+	case "recur_rule":
+		if _, err := rrule.Parse(value); err != nil {
+			return nil, fmt.Errorf("invalid recur_rule: %v", err)
+		}
+		return strings.TrimPrefix(value, "RRULE:"), nil
+
+	case "column_id":
+		// Columns can be given by name too
+		if len(settings.Columns) == 0 {
+			return value, nil
+		}
+		for _, col := range settings.Columns {
+			if col.ID == value || strings.EqualFold(col.Name, value) {
+				return col.ID, nil
+			}
+		}
+		return nil, fmt.Errorf("no column %q on the board", value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+func TestParseCSVDate(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value    string
+		want     string // "2006-01-02 15:04" in Moscow, empty for no date
+		dateOnly bool
+		wantErr  bool
+	}{
+		{"", "", false, false},
+		{"2026-10-20", "2026-10-20 00:00", true, false},
+		{"2026-10-20 15:30", "2026-10-20 15:30", false, false},
+		{"2026-10-20T15:30", "2026-10-20 15:30", false, false},
+		{"2026-10-20 15:30:45", "2026-10-20 15:30", false, false},
+		{"2026-10-20T15:30:45", "2026-10-20 15:30", false, false},
+		// A time at midnight is still a time
+		{"2026-10-20 00:00", "2026-10-20 00:00", false, false},
+		// RFC 3339 keeps its own offset
+		{"2026-10-20T15:30:00+02:00", "2026-10-20 16:30", false, false},
+		{"2026-10-20T12:30:00Z", "2026-10-20 15:30", false, false},
+
+		{"20.10.2026", "", false, true},
+		{"tomorrow", "", false, true},
+		{"2026-13-01", "", false, true},
+		{"2026-10-20 25:00", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			due, dateOnly, err := parseCSVDate(tt.value, moscow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			got := ""
+			if due != nil {
+				got = due.In(moscow).Format("2006-01-02 15:04")
+			}
+			if got != tt.want {
+				t.Errorf("due = %q, want %q", got, tt.want)
+			}
+			if dateOnly != tt.dateOnly {
+				t.Errorf("date only = %v, want %v", dateOnly, tt.dateOnly)
+			}
+		})
+	}
+}
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		text    string
+		escaped string
+	}{
+		{"", ""},
+		{"Buy milk", "Buy milk"},
+		{"=SUM(A1:A9)", "'=SUM(A1:A9)"},
+		{"+7 999 123-45-67", "'+7 999 123-45-67"},
+		{"-5 degrees", "'-5 degrees"},
+		{"@channel", "'@channel"},
+		{"a = b", "a = b"},
+		{"'", "'"},
+		{"'quoted'", "'quoted'"},
+		// Text that starts like an escaped formula is escaped too
+		{"'=SUM(A1)", "''=SUM(A1)"},
+		{"''-1", "'''-1"},
+	}
+	for _, tt := range tests {
+		if got := escapeCSVCell(tt.text); got != tt.escaped {
+			t.Errorf("escapeCSVCell(%q) = %q, want %q", tt.text, got, tt.escaped)
+		}
+		if got := unescapeCSVCell(tt.escaped); got != tt.text {
+			t.Errorf("unescapeCSVCell(%q) = %q, want %q", tt.escaped, got, tt.text)
+		}
+	}
+}
+
+func TestCSVListRoundTrip(t *testing.T) {
+	tests := [][]string{
+		{"home"},
+		{"a", "=b"},
+		{"=a", "b"},
+		{"'quoted", "-1"},
+	}
+	for _, labels := range tests {
+		t.Run(strings.Join(labels, ","), func(t *testing.T) {
+			list := make([]interface{}, len(labels))
+			for i, label := range labels {
+				list[i] = label
+			}
+
+			row := csvRow("labels", csvCell(list))
+			value, _ := row.get("labels")
+			got, err := parseCSVMetadata("labels", value, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got.([]string), ",") != strings.Join(labels, ",") {
+				t.Errorf("labels = %q, want %q", got, labels)
+			}
+		})
+	}
+}
+
+// csvRow returns a row of a file with the given header
+func csvRow(header string, values ...string) *itemCSVRow {
+	columns := make(map[string]int)
+	for i, name := range strings.Split(header, ",") {
+		columns[name] = i
+	}
+	return &itemCSVRow{line: 2, record: values, columns: columns}
+}
+
+func TestItemCSVRowApply(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calendar := &domain.Board{ID: uuid.New(), Type: domain.BoardTypeCalendar}
+	kanban := &domain.Board{ID: uuid.New(), Type: domain.BoardTypeKanban}
+	columns := &domain.BoardSettings{Columns: []domain.KanbanColumn{
+		{ID: "c1", Name: "To do"},
+		{ID: "c2", Name: "Done"},
+	}}
+
+	parent := &domain.Item{ID: uuid.New(), BoardID: kanban.ID, Title: "Parent", Status: domain.ItemStatusPending}
+	other := &domain.Item{ID: uuid.New(), BoardID: kanban.ID, Title: "Other", Status: domain.ItemStatusPending}
+	child := &domain.Item{ID: uuid.New(), BoardID: kanban.ID, ParentID: &parent.ID, Title: "Child", Status: domain.ItemStatusPending,
+		Metadata: json.RawMessage(`{"column_id":"c1","labels":["home"]}`)}
+	event := &domain.Item{ID: uuid.New(), BoardID: calendar.ID, Title: "Event", Status: domain.ItemStatusPending,
+		Metadata: json.RawMessage(`{"all_day":true,"location":"Office"}`)}
+	items := map[uuid.UUID]*domain.Item{parent.ID: parent, other.ID: other, child.ID: child, event.ID: event}
+
+	tests := []struct {
+		name    string
+		board   *domain.Board
+		row     *itemCSVRow
+		wantErr string
+		isNew   bool
+		check   func(t *testing.T, item *domain.Item, meta domain.ItemMetadata)
+	}{
+		{
+			name:  "new item",
+			board: kanban,
+			row:   csvRow("title,content,status", "'=Totals", "  keeps spaces  ", ""),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if item.Title != "=Totals" || item.Content != "  keeps spaces  " || item.Status != domain.ItemStatusPending {
+					t.Errorf("item = %q, %q, %q", item.Title, item.Content, item.Status)
+				}
+				if item.BoardID != kanban.ID {
+					t.Errorf("board = %v, want %v", item.BoardID, kanban.ID)
+				}
+			},
+		},
+		{
+			name:  "all-day date",
+			board: calendar,
+			row:   csvRow("title,due_date", "Trip", "2026-10-20"),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if item.DueDate == nil || !item.DueDate.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, moscow)) {
+					t.Errorf("due = %v, want 2026-10-20 in Moscow", item.DueDate)
+				}
+				if !meta.AllDay {
+					t.Errorf("all day = false, want true")
+				}
+			},
+		},
+		{
+			name:  "date with a time clears all day",
+			board: calendar,
+			row:   csvRow("id,due_date", event.ID.String(), "2026-10-20 09:00"),
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if meta.AllDay {
+					t.Errorf("all day = true, want false")
+				}
+				if !strings.Contains(string(item.Metadata), "Office") {
+					t.Errorf("metadata = %s, want the location kept", item.Metadata)
+				}
+			},
+		},
+		{
+			name:  "all_day column wins over the date",
+			board: calendar,
+			row:   csvRow("title,due_date,all_day", "Flight", "2026-10-20 09:00", "true"),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if !meta.AllDay {
+					t.Errorf("all day = false, want true")
+				}
+			},
+		},
+		{
+			name:  "column by name",
+			board: kanban,
+			row:   csvRow("id,column_id", child.ID.String(), "Done"),
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if meta.ColumnID != "c2" {
+					t.Errorf("column = %q, want c2", meta.ColumnID)
+				}
+				if len(meta.Labels) != 1 || meta.Labels[0] != "home" {
+					t.Errorf("labels = %q, want them kept", meta.Labels)
+				}
+			},
+		},
+		{
+			name:  "column by name in another case",
+			board: kanban,
+			row:   csvRow("title,column_id", "Card", "TO DO"),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if meta.ColumnID != "c1" {
+					t.Errorf("column = %q, want c1", meta.ColumnID)
+				}
+			},
+		},
+		{
+			name:  "column by id",
+			board: kanban,
+			row:   csvRow("title,column_id", "Card", "c2"),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if meta.ColumnID != "c2" {
+					t.Errorf("column = %q, want c2", meta.ColumnID)
+				}
+			},
+		},
+		{
+			name:    "unknown column",
+			board:   kanban,
+			row:     csvRow("title,column_id", "Card", "Backlog"),
+			wantErr: `no column "Backlog" on the board`,
+		},
+		{
+			name:  "same parent",
+			board: kanban,
+			row:   csvRow("id,parent_id,title", child.ID.String(), parent.ID.String(), "Renamed"),
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if item.Title != "Renamed" || item.ParentID == nil || *item.ParentID != parent.ID {
+					t.Errorf("item = %q under %v", item.Title, item.ParentID)
+				}
+			},
+		},
+		{
+			name:    "changed parent",
+			board:   kanban,
+			row:     csvRow("id,parent_id", child.ID.String(), other.ID.String()),
+			wantErr: "the parent of an existing item cannot be changed",
+		},
+		{
+			name:    "removed parent",
+			board:   kanban,
+			row:     csvRow("id,parent_id", child.ID.String(), ""),
+			wantErr: "the parent of an existing item cannot be changed",
+		},
+		{
+			name:    "added parent",
+			board:   kanban,
+			row:     csvRow("id,parent_id", other.ID.String(), parent.ID.String()),
+			wantErr: "the parent of an existing item cannot be changed",
+		},
+		{
+			name:  "new child",
+			board: kanban,
+			row:   csvRow("parent_id,title", parent.ID.String(), "New child"),
+			isNew: true,
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if item.ParentID == nil || *item.ParentID != parent.ID {
+					t.Errorf("parent = %v, want %v", item.ParentID, parent.ID)
+				}
+			},
+		},
+		{
+			name:    "unknown parent",
+			board:   kanban,
+			row:     csvRow("parent_id,title", uuid.NewString(), "Orphan"),
+			wantErr: "no parent item with this id on the board",
+		},
+		{
+			name:    "unknown id",
+			board:   kanban,
+			row:     csvRow("id,title", uuid.NewString(), "Ghost"),
+			wantErr: "no item with this id on the board",
+		},
+		{
+			name:    "missing title",
+			board:   kanban,
+			row:     csvRow("title,content", "  ", "text"),
+			wantErr: "title is required",
+		},
+		{
+			name:    "invalid status",
+			board:   kanban,
+			row:     csvRow("title,status", "Card", "started"),
+			wantErr: `invalid status "started"`,
+		},
+		{
+			name:  "completed",
+			board: kanban,
+			row:   csvRow("id,status", other.ID.String(), "Completed"),
+			check: func(t *testing.T, item *domain.Item, meta domain.ItemMetadata) {
+				if item.Status != domain.ItemStatusCompleted || item.CompletedAt == nil {
+					t.Errorf("status = %q, completed at %v", item.Status, item.CompletedAt)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, isNew, err := tt.row.apply(tt.board, columns, items, moscow)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isNew != tt.isNew {
+				t.Errorf("new = %v, want %v", isNew, tt.isNew)
+			}
+
+			var meta domain.ItemMetadata
+			if len(item.Metadata) > 0 {
+				if err := json.Unmarshal(item.Metadata, &meta); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tt.check(t, item, meta)
+		})
+	}
+
+	// Rows work on copies, the items of the board are left alone
+	if child.Title != "Child" || other.Status != domain.ItemStatusPending {
+		t.Errorf("apply changed the items it was given")
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/telegram-task-manager/backend/internal/domain"
+)
+
+// maxMarkdownFilenameLength keeps note file names within the limits of common
+// file systems, counted in characters
+const maxMarkdownFilenameLength = 100
+
+// markdownFilenameReplacer replaces characters that file systems or wiki
+// links (Obsidian) do not allow in names
+var markdownFilenameReplacer = strings.NewReplacer(
+	"/", "-", `\`, "-", ":", "-", "*", "-", "?", "", `"`, "'",
+	"<", "(", ">", ")", "|", "-", "#", "", "^", "", "[", "(", "]", ")",
+)
+
+// MarkdownArchive holds the notes boards of a Markdown export
+type MarkdownArchive struct {
+	// Name is the name of the exported board or folder
+	Name string
+
+	boards []markdownBoard
+	loc    *time.Location
+	// nested puts the notes of each board in a directory of its own
+	nested bool
+}
+
+type markdownBoard struct {
+	board    domain.Board
+	items    []domain.Item
+	children map[uuid.UUID][]*domain.Item
+}
+
+// BoardMarkdown loads a notes board for a Markdown export
+func (s *ExportService) BoardMarkdown(ctx context.Context, userID int64, boardID uuid.UUID) (*MarkdownArchive, error) {
+	ownerID, err := s.itemRepo.GetBoardOwner(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	board, err := s.boardRepo.GetByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if board.Type != domain.BoardTypeNotes {
+		return nil, domain.ErrInvalidBoardType
+	}
+
+	archive := &MarkdownArchive{Name: board.Name, loc: userLocation(ctx, s.userRepo, userID)}
+	if err := s.addMarkdownBoard(ctx, archive, board); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// FolderMarkdown loads the notes boards of a folder for a Markdown export.
+// Boards of other types are left out; a folder without notes boards yields
+// domain.ErrInvalidBoardType.
+func (s *ExportService) FolderMarkdown(ctx context.Context, userID int64, folderID uuid.UUID) (*MarkdownArchive, error) {
+	ownerID, err := s.boardRepo.GetFolderOwner(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	folder, err := s.folderRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	boards, err := s.boardRepo.GetByFolderID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &MarkdownArchive{Name: folder.Name, loc: userLocation(ctx, s.userRepo, userID), nested: true}
+	for i := range boards {
+		if boards[i].Type != domain.BoardTypeNotes {
+			continue
+		}
+		if err := s.addMarkdownBoard(ctx, archive, &boards[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(archive.boards) == 0 {
+		return nil, domain.ErrInvalidBoardType
+	}
+	return archive, nil
+}
+
+func (s *ExportService) addMarkdownBoard(ctx context.Context, archive *MarkdownArchive, board *domain.Board) error {
+	items, err := s.itemRepo.GetAllByBoardID(ctx, board.ID)
+	if err != nil {
+		return err
+	}
+
+	// Items come parents first, ordered by position
+	children := make(map[uuid.UUID][]*domain.Item)
+	for i := range items {
+		if items[i].ParentID != nil {
+			children[*items[i].ParentID] = append(children[*items[i].ParentID], &items[i])
+		}
+	}
+
+	archive.boards = append(archive.boards, markdownBoard{board: *board, items: items, children: children})
+	return nil
+}
+
+// Write writes the archive to w as a zip with a Markdown file per top-level
+// note, named after its title. A file starts with YAML front matter holding
+// the title, status, color, labels and dates; child items follow the note's
+// content as nested bullet lists. Folder exports have a directory per board.
+func (a *MarkdownArchive) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	dirs := make(map[string]bool)
+	for _, mb := range a.boards {
+		dir := ""
+		if a.nested {
+			dir = uniqueFilename(dirs, markdownFilename(mb.board.Name), "") + "/"
+		}
+
+		names := make(map[string]bool)
+		for i := range mb.items {
+			note := &mb.items[i]
+			if note.ParentID != nil {
+				continue
+			}
+
+			header := &zip.FileHeader{
+				Name:     dir + uniqueFilename(names, markdownFilename(note.Title), ".md"),
+				Method:   zip.Deflate,
+				Modified: note.UpdatedAt,
+			}
+			f, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(markdownNote(note, mb.children, a.loc)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+// markdownNote renders a note with its front matter and children
+func markdownNote(note *domain.Item, children map[uuid.UUID][]*domain.Item, loc *time.Location) []byte {
+	// Metadata in another shape just has no color and labels
+	meta, _ := note.ParseMetadata()
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", yamlString(note.Title))
+	fmt.Fprintf(&b, "status: %s\n", note.Status)
+	if meta.Color != "" {
+		fmt.Fprintf(&b, "color: %s\n", yamlString(meta.Color))
+	}
+	if len(meta.Labels) > 0 {
+		b.WriteString("labels:\n")
+		for _, label := range meta.Labels {
+			fmt.Fprintf(&b, "  - %s\n", yamlString(label))
+		}
+	}
+	fmt.Fprintf(&b, "created: %s\n", note.CreatedAt.In(loc).Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated: %s\n", note.UpdatedAt.In(loc).Format(time.RFC3339))
+	if note.DueDate != nil {
+		fmt.Fprintf(&b, "due: %s\n", note.DueDate.In(loc).Format(time.RFC3339))
+	}
+	if note.CompletedAt != nil {
+		fmt.Fprintf(&b, "completed: %s\n", note.CompletedAt.In(loc).Format(time.RFC3339))
+	}
+	b.WriteString("---\n")
+
+	if content := strings.TrimSpace(note.Content); content != "" {
+		b.WriteString("\n" + content + "\n")
+	}
+
+	if len(children[note.ID]) > 0 {
+		b.WriteString("\n")
+		writeMarkdownList(&b, children, note.ID, "")
+	}
+
+	return b.Bytes()
+}
+
+// writeMarkdownList writes the children of parentID as a bullet list, their
+// content indented below them and their own children as nested lists
+func writeMarkdownList(b *bytes.Buffer, children map[uuid.UUID][]*domain.Item, parentID uuid.UUID, indent string) {
+	for _, child := range children[parentID] {
+		title := strings.Join(strings.Fields(child.Title), " ")
+		fmt.Fprintf(b, "%s- %s\n", indent, title)
+
+		if content := strings.TrimSpace(child.Content); content != "" {
+			for _, line := range strings.Split(content, "\n") {
+				if line = strings.TrimRight(line, " \t\r"); line == "" {
+					b.WriteString("\n")
+					continue
+				}
+				fmt.Fprintf(b, "%s  %s\n", indent, line)
+			}
+		}
+
+		writeMarkdownList(b, children, child.ID, indent+"  ")
+	}
+}
+
+// yamlString quotes s as a YAML scalar; JSON strings are valid YAML
+func yamlString(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// markdownFilename turns a title into a file name without extension
+func markdownFilename(title string) string {
+	name := markdownFilenameReplacer.Replace(title)
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.TrimLeft(name, ".")
+	name = strings.TrimRight(truncateRunes(name, maxMarkdownFilenameLength), " .")
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// uniqueFilename appends a number to name when it is taken in used, comparing
+// case-insensitively for case-insensitive file systems
+func uniqueFilename(used map[string]bool, name, ext string) string {
+	candidate := name + ext
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s %d%s", name, n, ext)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
@@ -2,21 +2,49 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"html"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/telegram-task-manager/backend/internal/domain"
 	"github.com/telegram-task-manager/backend/internal/repository"
+	"github.com/telegram-task-manager/backend/pkg/datefmt"
+	"github.com/telegram-task-manager/backend/pkg/i18n"
 	"github.com/telegram-task-manager/backend/pkg/telegram"
 )
 
+// Delivery of queued notifications
+const (
+	outboxBatchSize = 50
+	// outboxLease is how long a claimed entry is hidden from other workers
+	// before it is retried, e.g. after a crash mid-delivery
+	outboxLease       = 2 * time.Minute
+	outboxMaxAttempts = 10
+	outboxBaseDelay   = 30 * time.Second
+	outboxMaxDelay    = 2 * time.Hour
+	// outboxRetention is how long sent entries are kept; dead ones stay for
+	// the user's list of failed notifications
+	outboxRetention = 7 * 24 * time.Hour
+
+	failedNotificationsLimit = 100
+
+	// digestSectionLimit keeps the digest well below Telegram's message size limit
+	digestSectionLimit = 10
+)
+
 type NotificationService struct {
-	bot            *telegram.Bot
-	userRepo       repository.UserRepository
-	itemRepo       repository.ItemRepository
-	reminderRepo   repository.ReminderRepository
-	appURL         string
-	logger         *slog.Logger
+	bot          *telegram.Bot
+	userRepo     repository.UserRepository
+	itemRepo     repository.ItemRepository
+	reminderRepo repository.ReminderRepository
+	outboxRepo   repository.OutboxRepository
+	appURL       string
+	miniAppLink  string
+	logger       *slog.Logger
 }
 
 func NewNotificationService(
@@ -24,7 +52,9 @@ func NewNotificationService(
 	userRepo repository.UserRepository,
 	itemRepo repository.ItemRepository,
 	reminderRepo repository.ReminderRepository,
+	outboxRepo repository.OutboxRepository,
 	appURL string,
+	miniAppLink string,
 	logger *slog.Logger,
 ) *NotificationService {
 	return &NotificationService{
@@ -32,36 +62,49 @@ func NewNotificationService(
 		userRepo:     userRepo,
 		itemRepo:     itemRepo,
 		reminderRepo: reminderRepo,
+		outboxRepo:   outboxRepo,
 		appURL:       appURL,
+		miniAppLink:  miniAppLink,
 		logger:       logger,
 	}
 }
 
-// SendReminder sends a task reminder notification
-func (s *NotificationService) SendReminder(ctx context.Context, reminder *domain.Reminder) error {
+// QueueReminder queues a task reminder notification and marks the reminder sent
+func (s *NotificationService) QueueReminder(ctx context.Context, reminder *domain.Reminder) error {
+	user := s.recipient(ctx, reminder.UserID)
+	lang := i18n.Parse(user.LanguageCode)
+
 	// Build message
 	var message string
 	if reminder.Message != "" {
-		message = fmt.Sprintf("Reminder: %s", reminder.Message)
+		message = i18n.T(lang, "reminder.message", html.EscapeString(reminder.Message))
 	} else if reminder.Item != nil {
-		message = fmt.Sprintf("Reminder: <b>%s</b>", reminder.Item.Title)
+		message = i18n.T(lang, "reminder.item", html.EscapeString(reminder.Item.Title))
 		if reminder.Item.DueDate != nil {
-			message += fmt.Sprintf("\nDue: %s", reminder.Item.DueDate.Format("Jan 2, 2006 15:04"))
+			message += i18n.T(lang, "reminder.due", formatDue(user, *reminder.Item.DueDate))
 		}
 	} else {
-		message = "You have a reminder!"
+		message = i18n.T(lang, "reminder.generic")
 	}
 
-	// Send notification via Telegram
-	_, err := s.bot.SendReminderMessage(
+	entry, err := s.newEntry(user, domain.NotificationReminder, telegram.ReminderMessage(
 		reminder.UserID,
 		message,
 		s.appURL,
 		reminder.ItemID.String(),
-	)
+		lang,
+	))
+	if err != nil {
+		return err
+	}
 
+	reminderID, itemID := reminder.ID, reminder.ItemID
+	entry.ReminderID = &reminderID
+	entry.ItemID = &itemID
+
+	queued, err := s.outboxRepo.EnqueueReminder(ctx, entry)
 	if err != nil {
-		s.logger.Error("failed to send reminder",
+		s.logger.Error("failed to queue reminder",
 			"user_id", reminder.UserID,
 			"reminder_id", reminder.ID,
 			"error", err,
@@ -69,43 +112,32 @@ func (s *NotificationService) SendReminder(ctx context.Context, reminder *domain
 		return err
 	}
 
-	// Mark reminder as sent
-	if err := s.reminderRepo.MarkSent(ctx, reminder.ID); err != nil {
-		s.logger.Error("failed to mark reminder as sent",
+	if queued {
+		s.logger.Info("reminder queued",
+			"user_id", reminder.UserID,
 			"reminder_id", reminder.ID,
-			"error", err,
 		)
-		return err
 	}
 
-	s.logger.Info("reminder sent",
-		"user_id", reminder.UserID,
-		"reminder_id", reminder.ID,
-	)
-
 	return nil
 }
 
-// SendInactivityReminder sends a reminder to inactive users
-func (s *NotificationService) SendInactivityReminder(ctx context.Context, userID int64) error {
-	_, err := s.bot.SendInactivityReminder(userID, s.appURL)
-	if err != nil {
-		s.logger.Error("failed to send inactivity reminder",
-			"user_id", userID,
-			"error", err,
-		)
+// QueueInactivityReminder queues a reminder to an inactive user
+func (s *NotificationService) QueueInactivityReminder(ctx context.Context, userID int64) error {
+	user := s.recipient(ctx, userID)
+	if err := s.enqueue(ctx, user, domain.NotificationInactivity, nil,
+		telegram.InactivityReminderMessage(userID, s.appURL, i18n.Parse(user.LanguageCode))); err != nil {
 		return err
 	}
 
 	// Update last active to prevent immediate re-notification
 	_ = s.userRepo.UpdateLastActive(ctx, userID)
 
-	s.logger.Info("inactivity reminder sent", "user_id", userID)
 	return nil
 }
 
-// SendOverdueTasksNotification sends notification about overdue tasks
-func (s *NotificationService) SendOverdueTasksNotification(ctx context.Context, userID int64, tasks []domain.OverdueTask) error {
+// QueueOverdueTasksNotification queues a notification about overdue tasks
+func (s *NotificationService) QueueOverdueTasksNotification(ctx context.Context, userID int64, tasks []domain.OverdueTask) error {
 	if len(tasks) == 0 {
 		return nil
 	}
@@ -119,52 +151,376 @@ func (s *NotificationService) SendOverdueTasksNotification(ctx context.Context,
 		}
 	}
 
-	_, err := s.bot.SendOverdueTasksNotification(userID, taskInfos, s.appURL)
+	user := s.recipient(ctx, userID)
+	return s.enqueue(ctx, user, domain.NotificationOverdue, nil,
+		telegram.OverdueTasksMessage(userID, taskInfos, s.appURL, i18n.Parse(user.LanguageCode)))
+}
+
+// QueueDueSoonNotification queues a notification about a task due soon
+func (s *NotificationService) QueueDueSoonNotification(ctx context.Context, userID int64, item *domain.Item) error {
+	user := s.recipient(ctx, userID)
+	lang := i18n.Parse(user.LanguageCode)
+
+	message := i18n.T(lang, "due_soon.item", html.EscapeString(item.Title))
+	if item.DueDate != nil {
+		message += i18n.T(lang, "reminder.due", formatDue(user, *item.DueDate))
+	}
+
+	itemID := item.ID
+	return s.enqueue(ctx, user, domain.NotificationDueSoon, &itemID,
+		telegram.ReminderMessage(userID, message, s.appURL, item.ID.String(), lang))
+}
+
+// QueueDigest queues the morning digest with the user's agenda
+func (s *NotificationService) QueueDigest(ctx context.Context, userID int64, digest *domain.Digest) error {
+	user := s.recipient(ctx, userID)
+	lang := i18n.Parse(user.LanguageCode)
+	return s.enqueue(ctx, user, domain.NotificationDigest, nil,
+		telegram.DigestMessage(userID, s.renderDigest(digest, lang), s.appURL, lang))
+}
+
+// renderDigest formats the digest as HTML. Item titles link into the Mini App
+// when its direct link is configured.
+func (s *NotificationService) renderDigest(digest *domain.Digest, lang i18n.Lang) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "digest.title", datefmt.LongDate(digest.Date, lang)) + "\n")
+
+	if len(digest.Events) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "digest.events") + "\n")
+		for i, event := range digest.Events {
+			if i == digestSectionLimit {
+				writeMore(&b, lang, len(digest.Events)-i)
+				break
+			}
+			when := i18n.T(lang, "digest.all_day")
+			if !event.AllDay {
+				when = event.Start.Format("15:04")
+			}
+			fmt.Fprintf(&b, "• %s %s\n", when, s.itemLink(event.ItemID, event.Title))
+		}
+	}
+
+	if len(digest.Boards) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "digest.due_today") + "\n")
+		for _, board := range digest.Boards {
+			fmt.Fprintf(&b, "<i>%s</i>\n", html.EscapeString(board.Name))
+			for i, item := range board.Items {
+				if i == digestSectionLimit {
+					writeMore(&b, lang, len(board.Items)-i)
+					break
+				}
+				b.WriteString("• ")
+				if meta, _ := item.ParseMetadata(); item.DueDate != nil && !meta.AllDay {
+					b.WriteString(item.DueDate.Format("15:04") + " ")
+				}
+				b.WriteString(s.itemLink(item.ID, item.Title) + "\n")
+			}
+		}
+	}
+
+	if len(digest.Overdue) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "digest.overdue") + "\n")
+		for i, item := range digest.Overdue {
+			if i == digestSectionLimit {
+				writeMore(&b, lang, len(digest.Overdue)-i)
+				break
+			}
+			fmt.Fprintf(&b, "• %s (%s)\n", s.itemLink(item.ID, item.Title), datefmt.Date(*item.DueDate, digest.Date, lang))
+		}
+	}
+
+	if len(digest.Habits) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "digest.habits") + "\n")
+		for i, item := range digest.Habits {
+			if i == digestSectionLimit {
+				writeMore(&b, lang, len(digest.Habits)-i)
+				break
+			}
+			fmt.Fprintf(&b, "• %s\n", s.itemLink(item.ID, item.Title))
+		}
+	}
+
+	return b.String()
+}
+
+// QueueWeeklyReport queues the weekly productivity report
+func (s *NotificationService) QueueWeeklyReport(ctx context.Context, userID int64, report *domain.WeeklyReport) error {
+	user := s.recipient(ctx, userID)
+	lang := i18n.Parse(user.LanguageCode)
+	return s.enqueue(ctx, user, domain.NotificationReport, nil,
+		telegram.WeeklyReportMessage(userID, renderWeeklyReport(report, lang), s.appURL, lang))
+}
+
+func renderWeeklyReport(report *domain.WeeklyReport, lang i18n.Lang) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "report.title",
+		datefmt.ShortDate(report.From, lang), datefmt.ShortDate(report.To.AddDate(0, 0, -1), lang)) + "\n\n")
+
+	b.WriteString(i18n.T(lang, "report.totals", report.Completed, report.Created) + "\n")
+
+	if report.BestDay != nil {
+		if day, err := time.Parse("2006-01-02", report.BestDay.Date); err == nil {
+			b.WriteString(i18n.T(lang, "report.best_day",
+				datefmt.Weekday(day, lang), i18n.N(lang, "tasks", report.BestDay.Completed)) + "\n")
+		}
+	}
+
+	b.WriteString(i18n.T(lang, "report.overdue", report.Overdue))
+	switch diff := report.Overdue - report.OverdueLastWeek; {
+	case diff > 0:
+		b.WriteString(i18n.T(lang, "report.overdue_up", diff))
+	case diff < 0:
+		b.WriteString(i18n.T(lang, "report.overdue_down", -diff))
+	}
+	b.WriteString("\n")
+
+	if len(report.Habits) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "report.habits") + "\n")
+		for i, habit := range report.Habits {
+			if i == digestSectionLimit {
+				writeMore(&b, lang, len(report.Habits)-i)
+				break
+			}
+			title := html.EscapeString(habit.Title)
+			switch {
+			case habit.Streak == 0:
+				b.WriteString(i18n.T(lang, "report.streak_lost", title, i18n.N(lang, "days", habit.Previous)))
+			case habit.Streak > habit.Previous:
+				b.WriteString(i18n.T(lang, "report.streak_gained", title, i18n.N(lang, "days", habit.Streak), habit.Streak-habit.Previous))
+			default:
+				b.WriteString(i18n.T(lang, "report.streak", title, i18n.N(lang, "days", habit.Streak)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(report.TopBoards) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "report.top_boards") + "\n")
+		for i, board := range report.TopBoards {
+			fmt.Fprintf(&b, "%d. %s: %s\n", i+1, html.EscapeString(board.Name), i18n.N(lang, "actions", board.Count))
+		}
+	}
+
+	return b.String()
+}
+
+// itemLink returns the escaped title, linked to the item when deep links are configured
+func (s *NotificationService) itemLink(itemID uuid.UUID, title string) string {
+	if s.miniAppLink == "" {
+		return html.EscapeString(title)
+	}
+	link := telegram.StartAppLink(s.miniAppLink, "item_"+itemID.String())
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), html.EscapeString(title))
+}
+
+func writeMore(b *strings.Builder, lang i18n.Lang, n int) {
+	b.WriteString(i18n.T(lang, "list.more", n) + "\n")
+}
+
+func (s *NotificationService) enqueue(ctx context.Context, user *domain.User, kind domain.NotificationKind, itemID *uuid.UUID, msg telegram.SendMessageRequest) error {
+	entry, err := s.newEntry(user, kind, msg)
 	if err != nil {
-		s.logger.Error("failed to send overdue tasks notification",
-			"user_id", userID,
-			"task_count", len(tasks),
+		return err
+	}
+
+	entry.ItemID = itemID
+	if err := s.outboxRepo.Enqueue(ctx, entry); err != nil {
+		s.logger.Error("failed to queue notification",
+			"user_id", user.ID,
+			"kind", kind,
 			"error", err,
 		)
 		return err
 	}
 
-	s.logger.Info("overdue tasks notification sent",
-		"user_id", userID,
-		"task_count", len(tasks),
+	s.logger.Info("notification queued",
+		"user_id", user.ID,
+		"kind", kind,
+		"outbox_id", entry.ID,
+		"next_attempt_at", entry.NextAttemptAt,
 	)
 
 	return nil
 }
 
-// SendDueSoonNotification sends notification about tasks due soon
-func (s *NotificationService) SendDueSoonNotification(ctx context.Context, userID int64, item *domain.Item) error {
-	message := fmt.Sprintf("Task due soon: <b>%s</b>", item.Title)
-	if item.DueDate != nil {
-		message += fmt.Sprintf("\nDue: %s", item.DueDate.Format("Jan 2, 2006 15:04"))
+// recipient loads the user a notification goes to. Without the user's settings
+// the notification is still sent, in UTC and the default language and ignoring quiet hours.
+func (s *NotificationService) recipient(ctx context.Context, userID int64) *domain.User {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		s.logger.Warn("failed to load notification recipient", "user_id", userID, "error", err)
+		return &domain.User{ID: userID, TelegramID: userID}
 	}
+	return user
+}
+
+// formatDue renders a due date in the user's timezone and language
+func formatDue(user *domain.User, due time.Time) string {
+	loc := user.Location()
+	return datefmt.DateTime(due.In(loc), time.Now().In(loc), i18n.Parse(user.LanguageCode))
+}
 
-	_, err := s.bot.SendReminderMessage(userID, message, s.appURL, item.ID.String())
+// newEntry builds an outbox entry for msg, held back by the user's quiet hours
+func (s *NotificationService) newEntry(user *domain.User, kind domain.NotificationKind, msg telegram.SendMessageRequest) (*domain.OutboxEntry, error) {
+	entry := &domain.OutboxEntry{
+		UserID:        user.ID,
+		Kind:          kind,
+		NextAttemptAt: quietHold(user, kind, &msg),
+	}
+
+	payload, err := json.Marshal(msg)
 	if err != nil {
-		s.logger.Error("failed to send due soon notification",
-			"user_id", userID,
-			"item_id", item.ID,
+		return nil, err
+	}
+	entry.Payload = payload
+
+	return entry, nil
+}
+
+// quietHold applies the user's quiet hours to msg. Inside them, non-urgent
+// messages are held back until the window ends, which quietHold returns, unless
+// the user chose silent delivery; everything else is sent without sound.
+// The zero time means msg can be sent right away.
+func quietHold(user *domain.User, kind domain.NotificationKind, msg *telegram.SendMessageRequest) time.Time {
+	if user.QuietHours == nil {
+		return time.Time{}
+	}
+
+	until, quiet := user.QuietHours.Until(time.Now(), user.Location())
+	if !quiet {
+		return time.Time{}
+	}
+	if kind.Urgent() || user.QuietHours.Mode == domain.QuietHoursSilent {
+		msg.DisableNotification = true
+		return time.Time{}
+	}
+	return until
+}
+
+func (s *NotificationService) DeliverPending(ctx context.Context) (int, error) {
+	entries, err := s.outboxRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	users := make(map[int64]*domain.User)
+	delivered := 0
+	for i := range entries {
+		// Entries not attempted are picked up again when their lease expires
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		user, ok := users[entries[i].UserID]
+		if !ok {
+			user = s.recipient(ctx, entries[i].UserID)
+			users[user.ID] = user
+		}
+
+		if s.deliver(ctx, &entries[i], user) {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver sends a claimed entry and records the outcome. Quiet hours are
+// checked again, since a retry or a change of settings may fall inside them.
+func (s *NotificationService) deliver(ctx context.Context, entry *domain.OutboxEntry, user *domain.User) bool {
+	var msg telegram.SendMessageRequest
+	err := json.Unmarshal(entry.Payload, &msg)
+	if err == nil {
+		if until := quietHold(user, entry.Kind, &msg); !until.IsZero() {
+			if err := s.outboxRepo.Hold(context.WithoutCancel(ctx), entry.ID, until); err != nil {
+				s.logger.Error("failed to hold notification", "outbox_id", entry.ID, "error", err)
+			}
+			return false
+		}
+		_, err = s.bot.SendMessageContext(ctx, msg)
+	}
+
+	// Record the outcome even if shutdown starts meanwhile
+	ctx = context.WithoutCancel(ctx)
+
+	if err == nil {
+		if err := s.outboxRepo.MarkSent(ctx, entry.ID); err != nil {
+			s.logger.Error("failed to mark notification sent", "outbox_id", entry.ID, "error", err)
+		}
+		s.logger.Info("notification sent",
+			"user_id", entry.UserID,
+			"kind", entry.Kind,
+			"outbox_id", entry.ID,
+		)
+		return true
+	}
+
+	s.disableIfBlocked(ctx, entry.UserID, err)
+
+	if telegram.IsPermanent(err) || entry.Attempts >= outboxMaxAttempts {
+		s.logger.Error("notification delivery failed permanently",
+			"user_id", entry.UserID,
+			"kind", entry.Kind,
+			"outbox_id", entry.ID,
+			"attempts", entry.Attempts,
 			"error", err,
 		)
-		return err
+		if err := s.outboxRepo.MarkDead(ctx, entry.ID, err.Error()); err != nil {
+			s.logger.Error("failed to mark notification dead", "outbox_id", entry.ID, "error", err)
+		}
+		return false
 	}
 
-	s.logger.Info("due soon notification sent",
-		"user_id", userID,
-		"item_id", item.ID,
+	retryAt := time.Now().Add(outboxRetryDelay(entry.Attempts))
+	s.logger.Warn("notification delivery failed",
+		"user_id", entry.UserID,
+		"kind", entry.Kind,
+		"outbox_id", entry.ID,
+		"attempts", entry.Attempts,
+		"retry_at", retryAt,
+		"error", err,
 	)
+	if err := s.outboxRepo.MarkFailed(ctx, entry.ID, err.Error(), retryAt); err != nil {
+		s.logger.Error("failed to reschedule notification", "outbox_id", entry.ID, "error", err)
+	}
 
-	return nil
+	return false
+}
+
+// outboxRetryDelay doubles the delay with every attempt: 30s, 1m, 2m, ... up to outboxMaxDelay
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxBaseDelay
+	for i := 1; i < attempts && delay < outboxMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxDelay)
+}
+
+// GetFailedNotifications returns the user's notifications that could not be
+// delivered yet or were given up on
+func (s *NotificationService) GetFailedNotifications(ctx context.Context, userID int64) ([]domain.OutboxEntry, error) {
+	entries, err := s.outboxRepo.GetFailedByUserID(ctx, userID, failedNotificationsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []domain.OutboxEntry{}
+	}
+
+	return entries, nil
+}
+
+// PurgeSent deletes sent notifications older than the retention period and
+// returns how many were deleted
+func (s *NotificationService) PurgeSent(ctx context.Context) (int64, error) {
+	return s.outboxRepo.DeleteSentBefore(ctx, time.Now().Add(-outboxRetention))
 }
 
 // NotifyTaskCompleted sends a celebratory message when a task is completed
 func (s *NotificationService) NotifyTaskCompleted(ctx context.Context, userID int64, taskTitle string) error {
-	message := fmt.Sprintf("Great job! You completed: <b>%s</b>", taskTitle)
+	user := s.recipient(ctx, userID)
+	message := i18n.T(i18n.Parse(user.LanguageCode), "task.completed", html.EscapeString(taskTitle))
 
 	_, err := s.bot.SendMessage(userID, message)
 	if err != nil {
@@ -172,9 +528,26 @@ func (s *NotificationService) NotifyTaskCompleted(ctx context.Context, userID in
 			"user_id", userID,
 			"error", err,
 		)
+		s.disableIfBlocked(ctx, userID, err)
 		// Don't return error - this is a non-critical notification
 		return nil
 	}
 
 	return nil
 }
+
+// disableIfBlocked turns off notifications for users who blocked the bot,
+// so the scheduler stops messaging them. Reports whether the user blocked the bot.
+func (s *NotificationService) disableIfBlocked(ctx context.Context, userID int64, err error) bool {
+	if !telegram.IsBlocked(err) {
+		return false
+	}
+
+	if err := s.userRepo.DisableNotifications(ctx, userID); err != nil {
+		s.logger.Error("failed to disable notifications", "user_id", userID, "error", err)
+	} else {
+		s.logger.Info("user blocked the bot, notifications disabled", "user_id", userID)
+	}
+
+	return true
+}
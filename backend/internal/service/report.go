@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+const (
+	reportDays      = 7
+	reportTopBoards = 3
+	// reportStreakDays bounds how far back habit streaks are counted
+	reportStreakDays = 366
+)
+
+// WeeklyReportService computes the weekly productivity report
+type WeeklyReportService struct {
+	folderRepo   repository.FolderRepository
+	itemRepo     repository.ItemRepository
+	habitRepo    repository.HabitCompletionRepository
+	activityRepo repository.ActivityLogRepository
+}
+
+func NewWeeklyReportService(
+	folderRepo repository.FolderRepository,
+	itemRepo repository.ItemRepository,
+	habitRepo repository.HabitCompletionRepository,
+	activityRepo repository.ActivityLogRepository,
+) *WeeklyReportService {
+	return &WeeklyReportService{
+		folderRepo:   folderRepo,
+		itemRepo:     itemRepo,
+		habitRepo:    habitRepo,
+		activityRepo: activityRepo,
+	}
+}
+
+// Build computes the report for the seven days ending with the day of now in
+// the user's timezone
+func (s *WeeklyReportService) Build(ctx context.Context, user *domain.User, now time.Time) (*domain.WeeklyReport, error) {
+	loc := user.Location()
+	local := now.In(loc)
+	to := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	from := to.AddDate(0, 0, -reportDays)
+
+	report := &domain.WeeklyReport{From: from, To: to}
+
+	days, err := s.itemRepo.GetCompletionStats(ctx, user.ID, reportDays, loc)
+	if err != nil {
+		return nil, err
+	}
+	report.Days = days
+
+	for i := range days {
+		report.Completed += days[i].Completed
+		report.Created += days[i].Created
+		if days[i].Completed > 0 && (report.BestDay == nil || days[i].Completed > report.BestDay.Completed) {
+			report.BestDay = &days[i]
+		}
+	}
+
+	if report.Habits, err = s.habitStreaks(ctx, user.ID, local); err != nil {
+		return nil, err
+	}
+
+	if report.Overdue, err = s.itemRepo.CountOverdueAt(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	if report.OverdueLastWeek, err = s.itemRepo.CountOverdueAt(ctx, user.ID, now.AddDate(0, 0, -reportDays)); err != nil {
+		return nil, err
+	}
+
+	if report.TopBoards, err = s.activityRepo.CountByBoard(ctx, user.ID, from, to, reportTopBoards); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// habitStreaks compares the streak of every habit with the one a week ago,
+// leaving out habits without either
+func (s *WeeklyReportService) habitStreaks(ctx context.Context, userID int64, local time.Time) ([]domain.HabitStreakChange, error) {
+	folders, err := s.folderRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completions are stored by calendar date
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	weekAgo := today.AddDate(0, 0, -reportDays)
+
+	var changes []domain.HabitStreakChange
+	for i := range folders {
+		for _, board := range folders[i].Boards {
+			if board.Type != domain.BoardTypeHabitTracker {
+				continue
+			}
+
+			items, err := s.itemRepo.GetByBoardID(ctx, board.ID, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range items {
+				if item.ParentID != nil || item.Status == domain.ItemStatusArchived {
+					continue
+				}
+
+				completions, err := s.habitRepo.GetByItemID(ctx, item.ID, today.AddDate(0, 0, -reportStreakDays), today)
+				if err != nil {
+					return nil, err
+				}
+
+				done := make(map[time.Time]bool, len(completions))
+				for _, c := range completions {
+					done[time.Date(c.CompletedDate.Year(), c.CompletedDate.Month(), c.CompletedDate.Day(), 0, 0, 0, 0, time.UTC)] = true
+				}
+
+				change := domain.HabitStreakChange{
+					ItemID:   item.ID,
+					Title:    item.Title,
+					Streak:   streakAt(done, today),
+					Previous: streakAt(done, weekAgo),
+				}
+				if change.Streak > 0 || change.Previous > 0 {
+					changes = append(changes, change)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b domain.HabitStreakChange) int {
+		return b.Streak - a.Streak
+	})
+
+	return changes, nil
+}
+
+// streakAt counts the consecutive completed days up to day. A day that is not
+// completed (yet) does not break the streak of the days before it.
+func streakAt(done map[time.Time]bool, day time.Time) int {
+	if !done[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for done[day] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
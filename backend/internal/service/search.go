@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/telegram-task-manager/backend/internal/domain"
+	"github.com/telegram-task-manager/backend/internal/repository"
+)
+
+const (
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
+	maxSearchQueryLength = 200
+)
+
+// searchMarks turns the match markers of highlights into HTML
+var searchMarks = strings.NewReplacer(domain.SearchMatchStart, "<mark>", domain.SearchMatchStop, "</mark>")
+
+type SearchService struct {
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(searchRepo repository.SearchRepository) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search looks for the query words in the titles and content of all of the
+// user's items, in Russian and English. Words are matched by their stem, so
+// "задачи" finds "задача" and "running" finds "run".
+func (s *SearchService) Search(ctx context.Context, userID int64, query domain.SearchQuery) (*domain.SearchResult, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, domain.NewBadRequestError("search query is required")
+	}
+	if utf8.RuneCountInString(query.Text) > maxSearchQueryLength {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("search query is longer than %d characters", maxSearchQueryLength))
+	}
+
+	for _, t := range query.BoardTypes {
+		if !t.IsValid() {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("invalid board type %q", t))
+		}
+	}
+	for _, status := range query.Statuses {
+		if !status.IsValid() {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("invalid status %q", status))
+		}
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	query.Limit = min(query.Limit, maxSearchLimit)
+	query.Offset = max(query.Offset, 0)
+
+	hits, total, err := s.searchRepo.Search(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range hits {
+		hits[i].TitleHighlight = highlightHTML(hits[i].TitleHighlight)
+		hits[i].Snippet = highlightHTML(hits[i].Snippet)
+	}
+
+	if hits == nil {
+		hits = []domain.SearchHit{}
+	}
+
+	return &domain.SearchResult{
+		Hits:   hits,
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+// highlightHTML escapes a highlight and wraps its matches in <mark> tags
+func highlightHTML(s string) string {
+	return searchMarks.Replace(html.EscapeString(s))
+}
@@ -0,0 +1,4 @@
+-- Migration: 003_calendar_feeds (rollback)
+-- Description: Remove iCalendar subscription feeds
+
+DROP TABLE IF EXISTS calendar_feeds;
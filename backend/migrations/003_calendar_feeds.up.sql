@@ -0,0 +1,17 @@
+-- Migration: 003_calendar_feeds
+-- Description: Secret iCalendar subscription feeds for calendar boards
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,  -- SHA-256 of the feed token, the token itself is never stored
+    include_tasks BOOLEAN DEFAULT false,
+    last_accessed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user_id ON calendar_feeds(user_id);
+
+COMMENT ON TABLE calendar_feeds IS 'Token-protected .ics subscription feeds, revocable by deleting the row';
+COMMENT ON COLUMN calendar_feeds.include_tasks IS 'Also export dated tasks from the user''s other boards as VTODOs';
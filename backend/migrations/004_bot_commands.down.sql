@@ -0,0 +1,5 @@
+-- Migration: 004_bot_commands (rollback)
+-- Description: Remove inbox board and bot listings
+
+DROP TABLE IF EXISTS bot_listings;
+ALTER TABLE users DROP COLUMN IF EXISTS inbox_board_id;
@@ -0,0 +1,15 @@
+-- Migration: 004_bot_commands
+-- Description: Inbox board for quick capture and the last item listing shown by the bot
+
+-- Board that /add puts new items into (falls back to the first checklist board)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS inbox_board_id UUID REFERENCES boards(id) ON DELETE SET NULL;
+
+-- Items of the last /today or /overdue listing, so /done <n> can refer to them
+CREATE TABLE IF NOT EXISTS bot_listings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    item_ids UUID[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON COLUMN users.inbox_board_id IS 'Board used by the /add bot command';
+COMMENT ON TABLE bot_listings IS 'Last numbered item listing sent by the bot to each user';
@@ -0,0 +1,4 @@
+-- Migration: 005_telegram_polling (rollback)
+-- Description: Remove the getUpdates offset table
+
+DROP TABLE IF EXISTS telegram_update_offsets;
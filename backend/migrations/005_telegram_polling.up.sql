@@ -0,0 +1,10 @@
+-- Migration: 005_telegram_polling
+-- Description: Persist the getUpdates offset for long polling mode
+
+CREATE TABLE IF NOT EXISTS telegram_update_offsets (
+    bot_id BIGINT PRIMARY KEY,
+    update_offset BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE telegram_update_offsets IS 'Next getUpdates offset per bot, so restarts neither lose nor repeat updates';
@@ -0,0 +1,4 @@
+-- Migration: 006_notification_outbox (rollback)
+-- Description: Remove the notification outbox
+
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,30 @@
+-- Migration: 006_notification_outbox
+-- Description: Outbox of Telegram notifications delivered at-least-once by a background worker
+
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    reminder_id UUID REFERENCES reminders(id) ON DELETE SET NULL,
+    item_id UUID REFERENCES items(id) ON DELETE SET NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_due ON notification_outbox(next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_user ON notification_outbox(user_id, created_at DESC);
+
+-- A reminder is queued at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_outbox_reminder ON notification_outbox(reminder_id)
+    WHERE reminder_id IS NOT NULL;
+
+COMMENT ON TABLE notification_outbox IS 'Queued Telegram messages with delivery attempts and dead-letter state';
+COMMENT ON COLUMN notification_outbox.payload IS 'Telegram sendMessage request';
+COMMENT ON COLUMN notification_outbox.next_attempt_at IS 'When the entry is due; also the lease of an entry being delivered';
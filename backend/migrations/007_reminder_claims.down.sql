@@ -0,0 +1,4 @@
+-- Migration: 007_reminder_claims (rollback)
+-- Description: Remove the reminder lease column
+
+ALTER TABLE reminders DROP COLUMN IF EXISTS claimed_until;
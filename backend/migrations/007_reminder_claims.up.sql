@@ -0,0 +1,6 @@
+-- Migration: 007_reminder_claims
+-- Description: Lease column so that one of several backend instances claims each due reminder
+
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN reminders.claimed_until IS 'Set while an instance queues the reminder; other instances skip it until then';
@@ -0,0 +1,16 @@
+-- Migration: 008_relative_reminders (rollback)
+-- Description: Remove relative reminders
+
+-- Guarded so the file is harmless when the column was never added
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'reminders' AND column_name = 'relative_offset'
+    ) THEN
+        DELETE FROM reminders WHERE relative_offset IS NOT NULL;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_reminders_relative;
+ALTER TABLE reminders DROP COLUMN IF EXISTS relative_offset;
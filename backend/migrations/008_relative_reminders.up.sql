@@ -0,0 +1,9 @@
+-- Migration: 008_relative_reminders
+-- Description: Reminders defined as an offset from the item's due date
+
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS relative_offset JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_reminders_relative ON reminders(item_id)
+    WHERE relative_offset IS NOT NULL;
+
+COMMENT ON COLUMN reminders.relative_offset IS 'Offset from the due date ({"minutes": 30} or {"days_before": 1, "at": "09:00"}); NULL for absolute reminders';
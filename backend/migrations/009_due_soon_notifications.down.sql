@@ -0,0 +1,5 @@
+-- Migration: 009_due_soon_notifications (rollback)
+-- Description: Remove due-soon notification settings and markers
+
+DROP TABLE IF EXISTS due_soon_notifications;
+ALTER TABLE users DROP COLUMN IF EXISTS due_soon_minutes;
@@ -0,0 +1,18 @@
+-- Migration: 009_due_soon_notifications
+-- Description: Per-user due-soon window and markers of items already announced
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS due_soon_minutes INTEGER NOT NULL DEFAULT 0
+    CHECK (due_soon_minutes >= 0);
+
+-- One notification per item and due date; moving the due date announces it again
+CREATE TABLE IF NOT EXISTS due_soon_notifications (
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (item_id, due_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_due_soon_notifications_due ON due_soon_notifications(due_date);
+
+COMMENT ON COLUMN users.due_soon_minutes IS 'How long before the due date items are announced, 0 (the default) turns it off';
+COMMENT ON TABLE due_soon_notifications IS 'Items already announced as due soon, keyed by due date';
@@ -0,0 +1,4 @@
+-- Migration: 010_quiet_hours (rollback)
+-- Description: Remove quiet hours
+
+ALTER TABLE users DROP COLUMN IF EXISTS quiet_hours;
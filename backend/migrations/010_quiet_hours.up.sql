@@ -0,0 +1,6 @@
+-- Migration: 010_quiet_hours
+-- Description: Per-user quiet hours for notifications
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quiet_hours JSONB;
+
+COMMENT ON COLUMN users.quiet_hours IS 'Daily do-not-disturb window in the user''s timezone ({"start": "23:00", "end": "08:00", "mode": "hold"}); NULL when off';
@@ -0,0 +1,7 @@
+-- Migration: 011_morning_digest (rollback)
+-- Description: Remove the daily digest
+
+DROP INDEX IF EXISTS idx_users_digest_time;
+
+ALTER TABLE users DROP COLUMN IF EXISTS digest_sent_on;
+ALTER TABLE users DROP COLUMN IF EXISTS digest_time;
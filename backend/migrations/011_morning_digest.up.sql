@@ -0,0 +1,10 @@
+-- Migration: 011_morning_digest
+-- Description: Daily digest of the day's agenda at a user-chosen local time
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS digest_time VARCHAR(5);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS digest_sent_on DATE;
+
+CREATE INDEX IF NOT EXISTS idx_users_digest_time ON users(digest_time) WHERE digest_time IS NOT NULL;
+
+COMMENT ON COLUMN users.digest_time IS 'Local time ("HH:MM") of the daily digest; NULL when off';
+COMMENT ON COLUMN users.digest_sent_on IS 'Local date of the last digest, so each day gets at most one';
@@ -0,0 +1,5 @@
+-- Migration: 012_weekly_report (rollback)
+-- Description: Remove the weekly productivity report
+
+ALTER TABLE users DROP COLUMN IF EXISTS weekly_report_sent_on;
+ALTER TABLE users DROP COLUMN IF EXISTS weekly_report;
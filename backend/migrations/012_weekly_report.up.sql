@@ -0,0 +1,8 @@
+-- Migration: 012_weekly_report
+-- Description: Opt-in weekly productivity report sent on Sunday evenings
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS weekly_report BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS weekly_report_sent_on DATE;
+
+COMMENT ON COLUMN users.weekly_report IS 'Whether the user receives the weekly productivity report';
+COMMENT ON COLUMN users.weekly_report_sent_on IS 'Local date of the last weekly report, so each week gets at most one';
@@ -0,0 +1,4 @@
+-- Migration: 013_overdue_local_morning (rollback)
+-- Description: Remove the local overdue notification date
+
+ALTER TABLE users DROP COLUMN IF EXISTS overdue_notified_on;
@@ -0,0 +1,6 @@
+-- Migration: 013_overdue_local_morning
+-- Description: Send overdue notifications in each user's local morning
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS overdue_notified_on DATE;
+
+COMMENT ON COLUMN users.overdue_notified_on IS 'Local date of the last overdue tasks notification, so each day gets at most one';
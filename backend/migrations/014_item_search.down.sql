@@ -0,0 +1,5 @@
+-- Migration: 014_item_search (rollback)
+-- Description: Remove full-text search over items
+
+DROP INDEX IF EXISTS idx_items_search;
+ALTER TABLE items DROP COLUMN IF EXISTS search_vector;
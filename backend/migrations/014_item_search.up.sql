@@ -0,0 +1,16 @@
+-- Migration: 014_item_search
+-- Description: Full-text search over item titles and content in Russian and English
+
+-- Both configurations stem the same text, so a query in either language finds
+-- it; titles weigh more than content in the ranking
+ALTER TABLE items ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(content, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_items_search ON items USING GIN (search_vector);
+
+COMMENT ON COLUMN items.search_vector IS 'Russian and English lexemes of the title (weight A) and content (weight B), kept up to date by Postgres';
@@ -0,0 +1,114 @@
+// Package datefmt renders dates for chat messages in the languages of package i18n,
+// relative to the current day where that reads better ("today 15:00",
+// "завтра 09:30"). Dates are formatted in their own location, so callers
+// convert them to the user's timezone first.
+package datefmt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/telegram-task-manager/backend/pkg/i18n"
+)
+
+// Languages without their own names here are rendered in English
+var (
+	monthsShort = map[i18n.Lang][12]string{
+		i18n.English: {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		i18n.Russian: {"янв", "фев", "мар", "апр", "мая", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"},
+	}
+	monthsLong = map[i18n.Lang][12]string{
+		i18n.English: {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		i18n.Russian: {"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	}
+	weekdays = map[i18n.Lang][7]string{
+		i18n.English: {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		i18n.Russian: {"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+	}
+	relativeDays = map[i18n.Lang]map[int]string{
+		i18n.English: {-1: "yesterday", 0: "today", 1: "tomorrow"},
+		i18n.Russian: {-1: "вчера", 0: "сегодня", 1: "завтра"},
+	}
+)
+
+// DateTime renders t with its time of day: "today 15:00", "Oct 20, 15:00",
+// "20 окт 2027, 15:00". The time is left out at midnight, see Date.
+func DateTime(t, now time.Time, lang i18n.Lang) string {
+	date := Date(t, now, lang)
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return date
+	}
+
+	// "today 15:00" and "Oct 20, 2027 15:00", but "Oct 20, 15:00"
+	if _, relative := relativeDay(t, now); relative || strings.Contains(date, ",") {
+		return date + " " + t.Format("15:04")
+	}
+	return date + ", " + t.Format("15:04")
+}
+
+// Date renders the day of t: "today", "tomorrow", "Oct 20", "20 окт 2027".
+// The year is only shown when it differs from the year of now.
+func Date(t, now time.Time, lang i18n.Lang) string {
+	lang = supported(lang)
+	if days, ok := relativeDay(t, now); ok {
+		return relativeDays[lang][days]
+	}
+
+	month := monthsShort[lang][t.Month()-1]
+	sameYear := t.Year() == now.In(t.Location()).Year()
+
+	switch {
+	case lang == i18n.Russian && sameYear:
+		return fmt.Sprintf("%d %s", t.Day(), month)
+	case lang == i18n.Russian:
+		return fmt.Sprintf("%d %s %d", t.Day(), month, t.Year())
+	case sameYear:
+		return fmt.Sprintf("%s %d", month, t.Day())
+	default:
+		return fmt.Sprintf("%s %d, %d", month, t.Day(), t.Year())
+	}
+}
+
+// ShortDate renders the day of t without relative phrasing: "Oct 20", "20 окт"
+func ShortDate(t time.Time, lang i18n.Lang) string {
+	lang = supported(lang)
+	month := monthsShort[lang][t.Month()-1]
+	if lang == i18n.Russian {
+		return fmt.Sprintf("%d %s", t.Day(), month)
+	}
+	return fmt.Sprintf("%s %d", month, t.Day())
+}
+
+// LongDate renders the weekday and day of t: "Friday, October 16", "пятница, 16 октября"
+func LongDate(t time.Time, lang i18n.Lang) string {
+	lang = supported(lang)
+	month := monthsLong[lang][t.Month()-1]
+	if lang == i18n.Russian {
+		return fmt.Sprintf("%s, %d %s", Weekday(t, lang), t.Day(), month)
+	}
+	return fmt.Sprintf("%s, %s %d", Weekday(t, lang), month, t.Day())
+}
+
+// Weekday returns the name of the weekday of t
+func Weekday(t time.Time, lang i18n.Lang) string {
+	return weekdays[supported(lang)][t.Weekday()]
+}
+
+// relativeDay returns how many calendar days t is from now, if it is close
+// enough to be phrased relatively
+func relativeDay(t, now time.Time) (int, bool) {
+	now = now.In(t.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := int(day.Sub(today).Hours() / 24)
+	return days, days >= -1 && days <= 1
+}
+
+func supported(lang i18n.Lang) i18n.Lang {
+	if _, ok := weekdays[lang]; ok {
+		return lang
+	}
+	return i18n.English
+}
@@ -0,0 +1,144 @@
+package datefmt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/telegram-task-manager/backend/pkg/i18n"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestRelativeDay(t *testing.T) {
+	moscow := mustLoadLocation(t, "Europe/Moscow")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name     string
+		t, now   time.Time
+		days     int
+		relative bool
+	}{
+		{"same day", time.Date(2026, 10, 16, 23, 59, 0, 0, moscow), time.Date(2026, 10, 16, 0, 0, 0, 0, moscow), 0, true},
+		{"tomorrow", time.Date(2026, 10, 17, 0, 0, 0, 0, moscow), time.Date(2026, 10, 16, 23, 59, 0, 0, moscow), 1, true},
+		{"yesterday", time.Date(2026, 10, 15, 23, 59, 0, 0, moscow), time.Date(2026, 10, 16, 0, 1, 0, 0, moscow), -1, true},
+		{"two days", time.Date(2026, 10, 18, 9, 0, 0, 0, moscow), time.Date(2026, 10, 16, 23, 0, 0, 0, moscow), 2, false},
+		{"two days ago", time.Date(2026, 10, 14, 9, 0, 0, 0, moscow), time.Date(2026, 10, 16, 8, 0, 0, 0, moscow), -2, false},
+		{"new year", time.Date(2027, 1, 1, 9, 0, 0, 0, moscow), time.Date(2026, 12, 31, 23, 30, 0, 0, moscow), 1, true},
+		{"old year", time.Date(2026, 12, 31, 22, 0, 0, 0, moscow), time.Date(2027, 1, 1, 0, 30, 0, 0, moscow), -1, true},
+		{"leap day", time.Date(2028, 3, 1, 9, 0, 0, 0, moscow), time.Date(2028, 2, 28, 9, 0, 0, 0, moscow), 2, false},
+
+		// now is taken in the location of t
+		{"now in UTC", time.Date(2027, 1, 1, 9, 0, 0, 0, moscow), time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC), 0, true},
+		{"t in UTC", time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 0, 30, 0, 0, moscow), 0, true},
+
+		// Days of 23 and 25 hours still count as one day
+		{"spring forward", time.Date(2026, 3, 29, 12, 0, 0, 0, berlin), time.Date(2026, 3, 28, 12, 0, 0, 0, berlin), 1, true},
+		{"spring forward midnight", time.Date(2026, 3, 30, 0, 0, 0, 0, berlin), time.Date(2026, 3, 29, 23, 59, 0, 0, berlin), 1, true},
+		{"fall back", time.Date(2026, 10, 26, 0, 0, 0, 0, berlin), time.Date(2026, 10, 24, 23, 30, 0, 0, berlin), 2, false},
+		{"fall back same day", time.Date(2026, 10, 25, 23, 30, 0, 0, berlin), time.Date(2026, 10, 25, 0, 30, 0, 0, berlin), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, relative := relativeDay(tt.t, tt.now)
+			if days != tt.days || relative != tt.relative {
+				t.Errorf("relativeDay = %d, %v, want %d, %v", days, relative, tt.days, tt.relative)
+			}
+		})
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	moscow := mustLoadLocation(t, "Europe/Moscow")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	newYearsEve := time.Date(2026, 12, 31, 23, 30, 0, 0, moscow)
+	tests := []struct {
+		t, now time.Time
+		en, ru string
+	}{
+		// Relative days take the time after a space
+		{time.Date(2026, 12, 31, 9, 5, 0, 0, moscow), newYearsEve, "today 09:05", "сегодня 09:05"},
+		{time.Date(2027, 1, 1, 15, 0, 0, 0, moscow), newYearsEve, "tomorrow 15:00", "завтра 15:00"},
+		{time.Date(2026, 12, 30, 18, 45, 0, 0, moscow), newYearsEve, "yesterday 18:45", "вчера 18:45"},
+
+		// Dates of this year take it after a comma
+		{time.Date(2026, 12, 20, 15, 0, 0, 0, moscow), newYearsEve, "Dec 20, 15:00", "20 дек, 15:00"},
+		{time.Date(2026, 5, 1, 0, 1, 0, 0, moscow), newYearsEve, "May 1, 00:01", "1 мая, 00:01"},
+
+		// English dates of another year already have a comma
+		{time.Date(2027, 1, 5, 15, 0, 0, 0, moscow), newYearsEve, "Jan 5, 2027 15:00", "5 янв 2027, 15:00"},
+		{time.Date(2025, 12, 31, 23, 59, 0, 0, moscow), newYearsEve, "Dec 31, 2025 23:59", "31 дек 2025, 23:59"},
+
+		// Midnight has no time
+		{time.Date(2027, 1, 1, 0, 0, 0, 0, moscow), newYearsEve, "tomorrow", "завтра"},
+		{time.Date(2026, 12, 20, 0, 0, 0, 0, moscow), newYearsEve, "Dec 20", "20 дек"},
+		{time.Date(2027, 2, 3, 0, 0, 0, 0, moscow), newYearsEve, "Feb 3, 2027", "3 фев 2027"},
+
+		// The year and the day are those of now in the location of t
+		{time.Date(2027, 1, 2, 10, 0, 0, 0, moscow), time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC), "tomorrow 10:00", "завтра 10:00"},
+		{time.Date(2027, 1, 20, 10, 0, 0, 0, moscow), time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC), "Jan 20, 10:00", "20 янв, 10:00"},
+
+		// Across daylight saving changes
+		{time.Date(2026, 3, 29, 12, 0, 0, 0, berlin), time.Date(2026, 3, 28, 12, 0, 0, 0, berlin), "tomorrow 12:00", "завтра 12:00"},
+		{time.Date(2026, 3, 29, 3, 0, 0, 0, berlin), time.Date(2026, 3, 29, 1, 59, 0, 0, berlin), "today 03:00", "сегодня 03:00"},
+		{time.Date(2026, 10, 26, 0, 30, 0, 0, berlin), time.Date(2026, 10, 24, 23, 30, 0, 0, berlin), "Oct 26, 00:30", "26 окт, 00:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.en, func(t *testing.T) {
+			if got := DateTime(tt.t, tt.now, i18n.English); got != tt.en {
+				t.Errorf("DateTime(en) = %q, want %q", got, tt.en)
+			}
+			if got := DateTime(tt.t, tt.now, i18n.Russian); got != tt.ru {
+				t.Errorf("DateTime(ru) = %q, want %q", got, tt.ru)
+			}
+		})
+	}
+}
+
+func TestUnsupportedLanguage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 10, 20, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"DateTime", DateTime(day, now, "de"), "Oct 20, 15:00"},
+		{"Date", Date(now, now, "de"), "today"},
+		{"ShortDate", ShortDate(day, "de"), "Oct 20"},
+		{"LongDate", LongDate(day, "de"), "Tuesday, October 20"},
+		{"Weekday", Weekday(day, "de"), "Tuesday"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLongDate(t *testing.T) {
+	tests := []struct {
+		t      time.Time
+		en, ru string
+	}{
+		{time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "Friday, October 16", "пятница, 16 октября"},
+		{time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), "Saturday, May 1", "суббота, 1 мая"},
+	}
+	for _, tt := range tests {
+		if got := LongDate(tt.t, i18n.English); got != tt.en {
+			t.Errorf("LongDate(en) = %q, want %q", got, tt.en)
+		}
+		if got := LongDate(tt.t, i18n.Russian); got != tt.ru {
+			t.Errorf("LongDate(ru) = %q, want %q", got, tt.ru)
+		}
+	}
+}
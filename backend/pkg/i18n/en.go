@@ -0,0 +1,99 @@
+package i18n
+
+var english = Catalog{
+	Messages: map[string]string{
+		// Buttons
+		"button.done":            "✅ Done",
+		"button.snooze_15m":      "⏰ 15m",
+		"button.snooze_1h":       "⏰ 1h",
+		"button.snooze_tomorrow": "📅 Tomorrow",
+		"button.open_task":       "Open Task",
+		"button.open_app":        "Open Task Manager",
+		"button.view_tasks":      "View Tasks",
+		"button.view_analytics":  "View Analytics",
+
+		// Notifications
+		"reminder.message":   "Reminder: %s",
+		"reminder.item":      "Reminder: <b>%s</b>",
+		"reminder.generic":   "You have a reminder!",
+		"reminder.due":       "\nDue: %s",
+		"due_soon.item":      "Task due soon: <b>%s</b>",
+		"task.completed":     "Great job! You completed: <b>%s</b>",
+		"inactivity.message": "Hey! It's been a while since you checked your tasks. Take a moment to review your progress and stay on track!",
+		"overdue.single":     "You have an overdue task:\n\n<b>%s</b>\nIn board: %s",
+		"overdue.item":       "- <b>%s</b> (%s)\n",
+		"list.more":          "...and %d more",
+
+		// Morning digest
+		"digest.title":     "☀️ <b>Good morning! Your agenda for %s</b>",
+		"digest.events":    "📅 <b>Events</b>",
+		"digest.all_day":   "All day",
+		"digest.due_today": "✅ <b>Due today</b>",
+		"digest.overdue":   "⚠️ <b>Overdue</b>",
+		"digest.habits":    "🔁 <b>Habits</b>",
+
+		// Weekly report
+		"report.title":         "📊 <b>Your week: %s – %s</b>",
+		"report.totals":        "✅ Completed: <b>%d</b> · Created: <b>%d</b>",
+		"report.best_day":      "🏆 Most productive day: <b>%s</b> (%s)",
+		"report.overdue":       "⚠️ Overdue: <b>%d</b>",
+		"report.overdue_up":    " (↑%d from last week)",
+		"report.overdue_down":  " (↓%d from last week)",
+		"report.habits":        "🔁 <b>Habits</b>",
+		"report.streak":        "• %s: 🔥 %s",
+		"report.streak_gained": "• %s: 🔥 %s (+%d)",
+		"report.streak_lost":   "• %s: streak lost (was %s)",
+		"report.top_boards":    "📋 <b>Top boards</b>",
+
+		// Bot commands
+		"bot.start.anonymous": "there",
+		"bot.start.welcome":   "Hi, %s! 👋\n\nI'll help you organize your tasks and reminders.\n\n📱 Open the mini app to manage your tasks:",
+		"bot.start.button":    "📋 Open Task Manager",
+		"bot.help": `📖 <b>Help</b>
+
+<b>Commands:</b>
+/start - Get started and open the mini app
+/add text - Add a task to the Inbox
+/today - Tasks for today
+/overdue - Overdue tasks
+/done number - Mark a task from the list as done
+/help - Show this message
+
+<b>Features:</b>
+• 📁 Create folders to stay organized
+• 📋 Add boards of different types (Kanban, checklists, notes and more)
+• ⏰ Set reminders
+• 📊 Track your progress
+
+Open the mini app with the menu button or the /start command!`,
+		"bot.add.usage":       "Send the task text: <code>/add Buy milk tomorrow at 18:00</code>",
+		"bot.add.added":       "✅ Added to “%s”: %s",
+		"bot.done.usage":      "Give the number of a task from /today or /overdue: <code>/done 2</code>",
+		"bot.done.no_listing": "Get a task list with /today or /overdue first",
+		"bot.done.no_item":    "The last list has no task with this number",
+		"bot.done.completed":  "✅ Completed: %s",
+		"bot.next":            "\nNext: %s",
+		"bot.today.title":     "📅 <b>Today</b>",
+		"bot.today.empty":     "Nothing due today 🎉",
+		"bot.overdue.title":   "⚠️ <b>Overdue</b>",
+		"bot.overdue.empty":   "No overdue tasks 👍",
+		"bot.list.hint":       "\n\nMark as done: <code>/done number</code>",
+		"bot.error.no_board":  "Couldn't find a board for tasks. Open the mini app with /start and create a checklist or choose an Inbox board in the settings.",
+		"bot.error.not_found": "Task not found",
+		"bot.error.invalid":   "Invalid request",
+		"bot.error.generic":   "Something went wrong, please try again",
+
+		// Reminder buttons
+		"callback.unknown": "Unknown action",
+		"callback.done":    "✅ <b>Done</b>",
+		"callback.marked":  "Marked as done",
+		"callback.snoozed": "⏰ Snoozed until %s",
+		"callback.until":   "Snoozed until %s",
+	},
+	Plurals: map[string]Forms{
+		"overdue.count": {One: "You have %d overdue task:\n\n", Other: "You have %d overdue tasks:\n\n"},
+		"tasks":         {One: "%d task", Other: "%d tasks"},
+		"days":          {One: "%d day", Other: "%d days"},
+		"actions":       {One: "%d action", Other: "%d actions"},
+	},
+}
@@ -0,0 +1,115 @@
+// Package i18n holds the texts of bot and notification messages in every
+// supported language. Messages are looked up by key and formatted with
+// fmt.Sprintf; plural messages have a variant per plural form of the language.
+//
+// To add a language, add a file with its catalog (see ru.go) and register it
+// in languages together with its plural rule: Ukrainian, for example, shares
+// the Russian rule and Kazakh the English one. Month and weekday names live in
+// package datefmt. Missing keys fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang is a supported message language
+type Lang string
+
+const (
+	English Lang = "en"
+	Russian Lang = "ru"
+
+	// Default is used for unsupported languages and missing messages
+	Default = English
+)
+
+// Catalog maps message keys to texts
+type Catalog struct {
+	Messages map[string]string
+	Plurals  map[string]Forms
+}
+
+// Forms holds the variants of a plural message. Other is required, the
+// others are used by the languages that have them.
+type Forms struct {
+	One   string
+	Few   string
+	Many  string
+	Other string
+}
+
+type language struct {
+	plural  PluralRule
+	catalog *Catalog
+}
+
+var languages = map[Lang]language{
+	English: {plural: PluralOneOther, catalog: &english},
+	Russian: {plural: PluralEastSlavic, catalog: &russian},
+}
+
+// Parse returns the supported language of an IETF language tag such as
+// Telegram's language_code ("ru", "en-US"), or Default
+func Parse(code string) Lang {
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	if _, ok := languages[Lang(base)]; ok {
+		return Lang(base)
+	}
+	return Default
+}
+
+// T formats the message key in lang with args
+func T(lang Lang, key string, args ...any) string {
+	text, ok := lookup(lang).catalog.Messages[key]
+	if !ok {
+		if text, ok = languages[Default].catalog.Messages[key]; !ok {
+			return key
+		}
+	}
+	return sprintf(text, args...)
+}
+
+// N formats the plural message key in lang for the count n. The count is
+// the first formatting argument, followed by args.
+func N(lang Lang, key string, n int, args ...any) string {
+	l := lookup(lang)
+	forms, ok := l.catalog.Plurals[key]
+	if !ok {
+		l = languages[Default]
+		if forms, ok = l.catalog.Plurals[key]; !ok {
+			return key
+		}
+	}
+	return sprintf(forms.get(l.plural(n)), append([]any{n}, args...)...)
+}
+
+func lookup(lang Lang) language {
+	if l, ok := languages[lang]; ok {
+		return l
+	}
+	return languages[Default]
+}
+
+func (f Forms) get(form Form) string {
+	var text string
+	switch form {
+	case One:
+		text = f.One
+	case Few:
+		text = f.Few
+	case Many:
+		text = f.Many
+	}
+	if text == "" {
+		return f.Other
+	}
+	return text
+}
+
+func sprintf(text string, args ...any) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
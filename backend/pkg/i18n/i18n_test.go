@@ -0,0 +1,172 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestPluralEastSlavic(t *testing.T) {
+	tests := []struct {
+		n    int
+		want Form
+	}{
+		{0, Many},
+		{1, One},
+		{2, Few},
+		{4, Few},
+		{5, Many},
+		{11, Many},
+		{12, Many},
+		{14, Many},
+		{21, One},
+		{22, Few},
+		{25, Many},
+		{101, One},
+		{111, Many},
+		{112, Many},
+		{122, Few},
+		{-1, One},
+		{-3, Few},
+	}
+	for _, tt := range tests {
+		if got := PluralEastSlavic(tt.n); got != tt.want {
+			t.Errorf("PluralEastSlavic(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		lang Lang
+		n    int
+		want string
+	}{
+		{English, 0, "0 tasks"},
+		{English, 1, "1 task"},
+		{English, 2, "2 tasks"},
+		{English, 21, "21 tasks"},
+		{Russian, 0, "0 задач"},
+		{Russian, 1, "1 задача"},
+		{Russian, 2, "2 задачи"},
+		{Russian, 5, "5 задач"},
+		{Russian, 11, "11 задач"},
+		{Russian, 12, "12 задач"},
+		{Russian, 14, "14 задач"},
+		{Russian, 21, "21 задача"},
+		{Russian, 22, "22 задачи"},
+		{Russian, 25, "25 задач"},
+		{Russian, 111, "111 задач"},
+		{Russian, 112, "112 задач"},
+		// Unsupported languages use English
+		{Lang("de"), 2, "2 tasks"},
+	}
+	for _, tt := range tests {
+		if got := N(tt.lang, "tasks", tt.n); got != tt.want {
+			t.Errorf("N(%q, tasks, %d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFallbackToEnglish(t *testing.T) {
+	english.Messages["test.only_english"] = "Hello, %s"
+	english.Plurals["test.only_english"] = Forms{One: "%d apple", Other: "%d apples"}
+	t.Cleanup(func() {
+		delete(english.Messages, "test.only_english")
+		delete(english.Plurals, "test.only_english")
+	})
+
+	if got, want := T(Russian, "test.only_english", "Anna"), "Hello, Anna"; got != want {
+		t.Errorf("T(ru) = %q, want %q", got, want)
+	}
+	// The English plural rule picks the form of the English text
+	if got, want := N(Russian, "test.only_english", 21), "21 apples"; got != want {
+		t.Errorf("N(ru, 21) = %q, want %q", got, want)
+	}
+	if got, want := N(Russian, "test.only_english", 1), "1 apple"; got != want {
+		t.Errorf("N(ru, 1) = %q, want %q", got, want)
+	}
+
+	// Unknown keys are returned as they are
+	if got := T(Russian, "test.missing"); got != "test.missing" {
+		t.Errorf("T(missing) = %q, want the key", got)
+	}
+	if got := N(English, "test.missing", 3); got != "test.missing" {
+		t.Errorf("N(missing) = %q, want the key", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code string
+		want Lang
+	}{
+		{"ru", Russian},
+		{"RU", Russian},
+		{"ru-RU", Russian},
+		{"en", English},
+		{"en-US", English},
+		{"de", Default},
+		{"", Default},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.code); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+// verb matches a fmt formatting verb, and %% which is none
+var verb = regexp.MustCompile(`%[-+# 0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*)?)?[a-zA-Z%]`)
+
+func verbs(text string) int {
+	n := 0
+	for _, v := range verb.FindAllString(text, -1) {
+		if v != "%%" {
+			n++
+		}
+	}
+	return n
+}
+
+// TestCatalogsMatchEnglish checks that every English message is translated
+// and takes as many arguments as the English one
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for lang, l := range languages {
+		if lang == English {
+			continue
+		}
+
+		for key, text := range english.Messages {
+			translated, ok := l.catalog.Messages[key]
+			if !ok {
+				t.Errorf("%s: message %q is missing", lang, key)
+				continue
+			}
+			if got, want := verbs(translated), verbs(text); got != want {
+				t.Errorf("%s: message %q has %d format verbs, want %d", lang, key, got, want)
+			}
+		}
+		for key := range l.catalog.Messages {
+			if _, ok := english.Messages[key]; !ok {
+				t.Errorf("%s: message %q is not in English", lang, key)
+			}
+		}
+
+		for key, forms := range english.Plurals {
+			translated, ok := l.catalog.Plurals[key]
+			if !ok {
+				t.Errorf("%s: plural %q is missing", lang, key)
+				continue
+			}
+			if translated.Other == "" {
+				t.Errorf("%s: plural %q has no Other form", lang, key)
+			}
+			want := verbs(forms.Other)
+			for _, text := range []string{translated.One, translated.Few, translated.Many, translated.Other} {
+				if text != "" && verbs(text) != want {
+					t.Errorf("%s: plural %q form %q has %d format verbs, want %d", lang, key, text, verbs(text), want)
+				}
+			}
+		}
+	}
+}
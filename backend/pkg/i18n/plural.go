@@ -0,0 +1,41 @@
+package i18n
+
+// Form is a plural form as defined by the Unicode CLDR plural rules
+type Form int
+
+const (
+	Other Form = iota
+	One
+	Few
+	Many
+)
+
+// PluralRule returns the plural form of a language for a count
+type PluralRule func(n int) Form
+
+// PluralOneOther is the rule of English, German, Kazakh and others:
+// 1 task, 2 tasks
+func PluralOneOther(n int) Form {
+	if n == 1 {
+		return One
+	}
+	return Other
+}
+
+// PluralEastSlavic is the rule of Russian, Ukrainian and Belarusian:
+// 1 задача, 2 задачи, 5 задач, 21 задача
+func PluralEastSlavic(n int) Form {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
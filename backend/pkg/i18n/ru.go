@@ -0,0 +1,103 @@
+package i18n
+
+var russian = Catalog{
+	Messages: map[string]string{
+		// Buttons
+		"button.done":            "✅ Готово",
+		"button.snooze_15m":      "⏰ 15 мин",
+		"button.snooze_1h":       "⏰ 1 ч",
+		"button.snooze_tomorrow": "📅 Завтра",
+		"button.open_task":       "Открыть задачу",
+		"button.open_app":        "Открыть Task Manager",
+		"button.view_tasks":      "Посмотреть задачи",
+		"button.view_analytics":  "Открыть статистику",
+
+		// Notifications
+		"reminder.message":   "Напоминание: %s",
+		"reminder.item":      "Напоминание: <b>%s</b>",
+		"reminder.generic":   "У тебя есть напоминание!",
+		"reminder.due":       "\nСрок: %s",
+		"due_soon.item":      "Скоро срок: <b>%s</b>",
+		"task.completed":     "Отлично! Выполнено: <b>%s</b>",
+		"inactivity.message": "Привет! Задачи давно тебя ждут. Найди минутку, чтобы проверить прогресс и ничего не упустить!",
+		"overdue.single":     "У тебя просроченная задача:\n\n<b>%s</b>\nНа доске: %s",
+		"overdue.item":       "- <b>%s</b> (%s)\n",
+		"list.more":          "...и ещё %d",
+
+		// Morning digest
+		"digest.title":     "☀️ <b>Доброе утро! План на %s</b>",
+		"digest.events":    "📅 <b>События</b>",
+		"digest.all_day":   "Весь день",
+		"digest.due_today": "✅ <b>На сегодня</b>",
+		"digest.overdue":   "⚠️ <b>Просроченные</b>",
+		"digest.habits":    "🔁 <b>Привычки</b>",
+
+		// Weekly report
+		"report.title":         "📊 <b>Твоя неделя: %s – %s</b>",
+		"report.totals":        "✅ Выполнено: <b>%d</b> · Создано: <b>%d</b>",
+		"report.best_day":      "🏆 Самый продуктивный день: <b>%s</b> (%s)",
+		"report.overdue":       "⚠️ Просрочено: <b>%d</b>",
+		"report.overdue_up":    " (↑%d за неделю)",
+		"report.overdue_down":  " (↓%d за неделю)",
+		"report.habits":        "🔁 <b>Привычки</b>",
+		"report.streak":        "• %s: 🔥 %s",
+		"report.streak_gained": "• %s: 🔥 %s (+%d)",
+		"report.streak_lost":   "• %s: серия прервана (была %s)",
+		"report.top_boards":    "📋 <b>Самые активные доски</b>",
+
+		// Bot commands
+		"bot.start.anonymous": "пользователь",
+		"bot.start.welcome":   "Привет, %s! 👋\n\nЯ помогу тебе организовать задачи и напоминания.\n\n📱 Открой мини-приложение для управления задачами:",
+		"bot.start.button":    "📋 Открыть Task Manager",
+		"bot.help": `📖 <b>Помощь</b>
+
+<b>Команды:</b>
+/start - Начать работу и открыть мини-приложение
+/add текст - Добавить задачу во «Входящие»
+/today - Задачи на сегодня
+/overdue - Просроченные задачи
+/done номер - Отметить задачу из списка выполненной
+/help - Показать это сообщение
+
+<b>Возможности:</b>
+• 📁 Создавай папки для организации
+• 📋 Добавляй доски разных типов (Kanban, чек-листы, заметки и др.)
+• ⏰ Устанавливай напоминания
+• 📊 Отслеживай прогресс
+
+Открой мини-приложение через кнопку меню или команду /start!`,
+		"bot.add.usage":       "Напиши текст задачи: <code>/add Купить молоко завтра в 18:00</code>",
+		"bot.add.added":       "✅ Добавлено в «%s»: %s",
+		"bot.done.usage":      "Укажи номер задачи из списка /today или /overdue: <code>/done 2</code>",
+		"bot.done.no_listing": "Сначала получи список задач командой /today или /overdue",
+		"bot.done.no_item":    "В последнем списке нет задачи с таким номером",
+		"bot.done.completed":  "✅ Выполнено: %s",
+		"bot.next":            "\nСледующее: %s",
+		"bot.today.title":     "📅 <b>Сегодня</b>",
+		"bot.today.empty":     "На сегодня задач нет 🎉",
+		"bot.overdue.title":   "⚠️ <b>Просроченные</b>",
+		"bot.overdue.empty":   "Просроченных задач нет 👍",
+		"bot.list.hint":       "\n\nОтметить выполненной: <code>/done номер</code>",
+		"bot.error.no_board":  "Не нашёл доску для задач. Открой мини-приложение через /start и создай чек-лист или выбери доску «Входящие» в настройках.",
+		"bot.error.not_found": "Задача не найдена",
+		"bot.error.invalid":   "Некорректный запрос",
+		"bot.error.generic":   "Что-то пошло не так, попробуй ещё раз",
+
+		// Reminder buttons
+		"callback.unknown": "Неизвестное действие",
+		"callback.done":    "✅ <b>Готово</b>",
+		"callback.marked":  "Отмечено как выполненное",
+		"callback.snoozed": "⏰ Отложено до %s",
+		"callback.until":   "Отложено до %s",
+	},
+	Plurals: map[string]Forms{
+		"overdue.count": {
+			One:   "У тебя %d просроченная задача:\n\n",
+			Few:   "У тебя %d просроченные задачи:\n\n",
+			Other: "У тебя %d просроченных задач:\n\n",
+		},
+		"tasks":   {One: "%d задача", Few: "%d задачи", Other: "%d задач"},
+		"days":    {One: "%d день", Few: "%d дня", Other: "%d дней"},
+		"actions": {One: "%d действие", Few: "%d действия", Other: "%d действий"},
+	},
+}
@@ -0,0 +1,238 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineBytes bounds a single unfolded content line
+const maxLineBytes = 1 << 20
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+// Decode parses an iCalendar stream and returns its first top-level component,
+// normally VCALENDAR. Folded lines are joined and both CRLF and LF endings are accepted.
+func Decode(r io.Reader) (*Component, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+
+	var (
+		lines   []string
+		lineNum []int
+		n       int
+	)
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		lineNum = append(lineNum, n)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+		}
+		return nil, err
+	}
+
+	var (
+		root  *Component
+		stack []*Component
+	)
+	for i, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, lineNum[i], err)
+		}
+
+		switch strings.ToUpper(prop.Name) {
+		case "BEGIN":
+			c := NewComponent(strings.ToUpper(prop.Value))
+			if len(stack) > 0 {
+				stack[len(stack)-1].AddComponent(c)
+			} else if root == nil {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, lineNum[i], prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return root, nil
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: line %d: property outside of a component", ErrInvalidCalendar, lineNum[i])
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: no components found", ErrInvalidCalendar)
+	}
+	return nil, fmt.Errorf("%w: missing END:%s", ErrInvalidCalendar, stack[len(stack)-1].Name)
+}
+
+// parseLine splits a content line into name, parameters and value.
+// Separators inside double-quoted parameter values are ignored.
+func parseLine(line string) (Property, error) {
+	var (
+		prop   Property
+		fields []string
+		start  int
+		quoted bool
+		end    = -1
+	)
+
+	for i := 0; i < len(line) && end < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				fields = append(fields, line[start:i])
+				start = i + 1
+			}
+		case ':':
+			if !quoted {
+				fields = append(fields, line[start:i])
+				end = i
+			}
+		}
+	}
+
+	if end < 0 {
+		return prop, errors.New("missing ':'")
+	}
+
+	prop.Name = strings.ToUpper(strings.TrimSpace(fields[0]))
+	if prop.Name == "" {
+		return prop, errors.New("missing property name")
+	}
+	prop.Value = line[end+1:]
+
+	for _, field := range fields[1:] {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return prop, fmt.Errorf("invalid parameter %q", field)
+		}
+		prop.Params = append(prop.Params, Param{
+			Name:  strings.ToUpper(name),
+			Value: strings.Trim(value, `"`),
+		})
+	}
+
+	return prop, nil
+}
+
+// UnescapeText reverses EscapeText
+func UnescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// Text returns the unescaped value of a TEXT property
+func (p *Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Time parses a DATE or DATE-TIME value. UTC values keep UTC; values with a
+// TZID are resolved in that timezone; floating values and unknown timezones
+// use fallback. dateOnly reports a VALUE=DATE (all-day) value.
+func (p *Property) Time(fallback *time.Location) (t time.Time, dateOnly bool, err error) {
+	times, dateOnly, err := p.Times(fallback)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return times[0], dateOnly, nil
+}
+
+// Times parses a comma-separated list of DATE or DATE-TIME values, as used by EXDATE
+func (p *Property) Times(fallback *time.Location) ([]time.Time, bool, error) {
+	loc := fallback
+	if tzid := p.Param("TZID"); tzid != "" {
+		loc = ResolveTZID(tzid, fallback)
+	}
+
+	values := strings.Split(p.Value, ",")
+	times := make([]time.Time, 0, len(values))
+	dateOnly := strings.EqualFold(p.Param("VALUE"), "DATE")
+
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		var (
+			t   time.Time
+			err error
+		)
+		switch {
+		case len(v) == len(DateFormat):
+			t, err = time.ParseInLocation(DateFormat, v, loc)
+			dateOnly = true
+		case strings.HasSuffix(v, "Z"):
+			t, err = time.Parse(UTCFormat, v)
+		default:
+			t, err = time.ParseInLocation(DateTimeFormat, v, loc)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid %s value %q", p.Name, v)
+		}
+		times = append(times, t)
+	}
+
+	if len(times) == 0 {
+		return nil, false, fmt.Errorf("empty %s value", p.Name)
+	}
+	return times, dateOnly, nil
+}
+
+// ResolveTZID maps a TZID parameter to a location. Besides plain IANA names it
+// accepts prefixed forms such as "/mozilla.org/20050126_1/Europe/Berlin".
+func ResolveTZID(tzid string, fallback *time.Location) *time.Location {
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		return loc
+	}
+
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		if loc, err := time.LoadLocation(strings.Join(parts[i:], "/")); err == nil {
+			return loc
+		}
+	}
+
+	return fallback
+}
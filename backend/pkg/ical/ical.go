@@ -0,0 +1,199 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxLineOctets is the maximum line length before folding (RFC 5545, section 3.1)
+	maxLineOctets = 75
+
+	DateFormat     = "20060102"
+	DateTimeFormat = "20060102T150405"
+	UTCFormat      = "20060102T150405Z"
+)
+
+// Param is a property parameter such as VALUE=DATE or TZID=Europe/Moscow
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Property is a single content line
+type Property struct {
+	Name   string
+	Params []Param
+	Value  string
+}
+
+// Param returns the value of a parameter, or an empty string
+func (p *Property) Param(name string) string {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Component is a calendar component such as VCALENDAR, VEVENT or VTODO
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// NewComponent creates an empty component
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// NewCalendar creates a VCALENDAR with the required VERSION and PRODID properties
+func NewCalendar(prodID string) *Component {
+	cal := NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", prodID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	return cal
+}
+
+// Add appends a raw property value
+func (c *Component) Add(name, value string, params ...Param) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// AddText appends a TEXT property, escaping its value
+func (c *Component) AddText(name, value string) {
+	c.Add(name, EscapeText(value))
+}
+
+// AddUTC appends a DATE-TIME property in UTC form
+func (c *Component) AddUTC(name string, t time.Time) {
+	c.Add(name, t.UTC().Format(UTCFormat))
+}
+
+// AddDate appends a DATE property
+func (c *Component) AddDate(name string, t time.Time) {
+	c.Add(name, t.Format(DateFormat), Param{Name: "VALUE", Value: "DATE"})
+}
+
+// AddLocal appends a DATE-TIME property with a TZID parameter, so recurrence
+// rules are evaluated in the wall clock of that timezone
+func (c *Component) AddLocal(name string, t time.Time) {
+	loc := t.Location()
+	if loc == time.UTC || loc.String() == "Local" {
+		c.AddUTC(name, t)
+		return
+	}
+	c.Add(name, t.Format(DateTimeFormat), Param{Name: "TZID", Value: loc.String()})
+}
+
+// AddComponent appends a sub-component
+func (c *Component) AddComponent(sub *Component) {
+	c.Components = append(c.Components, sub)
+}
+
+// Get returns the first property with the given name
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if strings.EqualFold(c.Properties[i].Name, name) {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// GetAll returns all properties with the given name
+func (c *Component) GetAll(name string) []Property {
+	var props []Property
+	for _, p := range c.Properties {
+		if strings.EqualFold(p.Name, name) {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Encode writes the component in iCalendar format with CRLF line endings and folding
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := c.encode(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (c *Component) encode(w *bufio.Writer) error {
+	if err := writeLine(w, "BEGIN:"+c.Name); err != nil {
+		return err
+	}
+
+	for _, p := range c.Properties {
+		var line strings.Builder
+		line.WriteString(p.Name)
+		for _, param := range p.Params {
+			line.WriteString(";")
+			line.WriteString(param.Name)
+			line.WriteString("=")
+			line.WriteString(quoteParam(param.Value))
+		}
+		line.WriteString(":")
+		line.WriteString(p.Value)
+
+		if err := writeLine(w, line.String()); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range c.Components {
+		if err := sub.encode(w); err != nil {
+			return err
+		}
+	}
+
+	return writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds lines longer than 75 octets without splitting UTF-8 sequences
+func writeLine(w *bufio.Writer, line string) error {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, err := w.WriteString(line[:cut] + "\r\n "); err != nil {
+			return err
+		}
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+
+	_, err := w.WriteString(line + "\r\n")
+	return err
+}
+
+func quoteParam(value string) string {
+	if strings.ContainsAny(value, ";:,") {
+		return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// EscapeText escapes a TEXT value
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Timezone builds a VTIMEZONE component for loc from the transitions in effect
+// during the given year. Transitions are written as yearly rules starting in 1970,
+// so the component also covers recurring events that started in earlier years.
+// Zones without daylight saving time get a single STANDARD observance.
+func Timezone(loc *time.Location, year int) *Component {
+	tz := NewComponent("VTIMEZONE")
+	tz.Add("TZID", loc.String())
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+
+	for t := start; t.Before(end); {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		tz.AddComponent(observance(loc, next))
+		t = next
+	}
+
+	if len(tz.Components) == 0 {
+		name, offset := start.Zone()
+		std := NewComponent("STANDARD")
+		std.Add("DTSTART", "19700101T000000")
+		std.Add("TZOFFSETFROM", formatOffset(offset))
+		std.Add("TZOFFSETTO", formatOffset(offset))
+		std.Add("TZNAME", name)
+		tz.AddComponent(std)
+	}
+
+	return tz
+}
+
+// observance describes the transition happening at the instant t
+func observance(loc *time.Location, t time.Time) *Component {
+	after := t.In(loc)
+	name, offsetTo := after.Zone()
+	_, offsetFrom := t.Add(-time.Second).In(loc).Zone()
+
+	kind := "STANDARD"
+	if after.IsDST() {
+		kind = "DAYLIGHT"
+	}
+
+	// DTSTART of an observance is the wall clock time before the transition
+	wall := t.UTC().Add(time.Duration(offsetFrom) * time.Second)
+
+	n := (wall.Day()-1)/7 + 1
+	if wall.Day()+7 > daysIn(wall.Year(), wall.Month()) {
+		n = -1
+	}
+
+	first := nthWeekday(1970, wall.Month(), n, wall.Weekday())
+	first = first.Add(time.Duration(wall.Hour())*time.Hour + time.Duration(wall.Minute())*time.Minute + time.Duration(wall.Second())*time.Second)
+
+	c := NewComponent(kind)
+	c.Add("DTSTART", first.Format(DateTimeFormat))
+	c.Add("RRULE", fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(wall.Month()), n, weekdayCodes[wall.Weekday()]))
+	c.Add("TZOFFSETFROM", formatOffset(offsetFrom))
+	c.Add("TZOFFSETTO", formatOffset(offsetTo))
+	c.Add("TZNAME", name)
+	return c
+}
+
+// nthWeekday returns the n-th weekday of a month at midnight UTC; n = -1 is the last one
+func nthWeekday(year int, month time.Month, n int, wd time.Weekday) time.Time {
+	if n < 0 {
+		last := time.Date(year, month, daysIn(year, month), 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM (or +HHMMSS)
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if s != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, h, m)
+}
@@ -0,0 +1,799 @@
+// Package naturaldate extracts due dates and recurrence rules from free-form
+// task text in Russian and English, e.g. "завтра в 15:00", "в пятницу",
+// "next monday 9am", "через 2 часа", "каждый вторник" or "every weekday".
+package naturaldate
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/telegram-task-manager/backend/pkg/rrule"
+)
+
+// Result is the outcome of Parse
+type Result struct {
+	// Text is the input with the recognised date phrases removed
+	Text string
+
+	// Due is the resolved date in the location of now, nil when the input has none
+	Due *time.Time
+
+	// HasTime reports whether a time of day was given; otherwise Due is at midnight
+	HasTime bool
+
+	// RecurRule is an RRULE value for phrases like "каждый вторник", empty otherwise.
+	// Due is then the first occurrence.
+	RecurRule string
+}
+
+type parser struct {
+	now   time.Time
+	today time.Time
+
+	words []string
+	used  []bool
+
+	date *time.Time
+
+	hour, minute int
+	hasTime      bool
+
+	dayPart    int
+	hasDayPart bool
+
+	offset    time.Duration
+	hasOffset bool
+
+	rule *rrule.Rule
+}
+
+// Parse looks for date, time and recurrence phrases in text and resolves them
+// relative to now, in now's location. Words it does not recognise are kept in Result.Text.
+//
+// A bare weekday ("в пятницу", "friday") means the next such day after today;
+// "this friday" includes today. A time without a date means today, or tomorrow
+// once that time has passed.
+func Parse(text string, now time.Time) Result {
+	fields := strings.Fields(text)
+
+	p := &parser{
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		words: make([]string, len(fields)),
+		used:  make([]bool, len(fields)),
+	}
+	for i, f := range fields {
+		p.words[i] = normalize(f)
+	}
+
+	matchers := []func(int) int{
+		p.matchRecurrence,
+		p.matchRelative,
+		p.matchDayWord,
+		p.matchWeekday,
+		p.matchDate,
+		p.matchTime,
+		p.matchDayPart,
+	}
+
+	for i := 0; i < len(p.words); {
+		n := 0
+		for _, match := range matchers {
+			if n = match(i); n > 0 {
+				break
+			}
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		for j := i; j < i+n; j++ {
+			p.used[j] = true
+		}
+		i += n
+	}
+
+	var rest []string
+	for i, f := range fields {
+		if !p.used[i] {
+			rest = append(rest, f)
+		}
+	}
+
+	result := Result{Text: strings.Trim(strings.Join(rest, " "), " ,;:-–—")}
+	p.resolve(&result)
+
+	return result
+}
+
+func normalize(word string) string {
+	word = strings.ToLower(word)
+	word = strings.ReplaceAll(word, "ё", "е")
+	return strings.Trim(word, `,;!?()"'«»`+".")
+}
+
+func (p *parser) word(i int) string {
+	if i < 0 || i >= len(p.words) {
+		return ""
+	}
+	return p.words[i]
+}
+
+// withPrep runs match at i, or after a preposition at i. match receives
+// whether a preposition was present.
+func (p *parser) withPrep(i int, match func(j int, prep bool) int) int {
+	if prepositions[p.word(i)] {
+		if n := match(i+1, true); n > 0 {
+			return n + 1
+		}
+		return 0
+	}
+	return match(i, false)
+}
+
+// resolve combines the matched parts into Result.Due
+func (p *parser) resolve(result *Result) {
+	if p.hasOffset {
+		t := p.now.Add(p.offset)
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+		result.Due = &t
+		result.HasTime = true
+		return
+	}
+
+	if p.hasDayPart {
+		if !p.hasTime {
+			p.hour, p.minute, p.hasTime = p.dayPart, 0, true
+		} else if p.dayPart >= 12 && p.hour < 12 {
+			// "вечером в 7", "tomorrow evening at 8"
+			p.hour += 12
+		}
+	}
+
+	at := func(day time.Time) time.Time {
+		if !p.hasTime {
+			return day
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(), p.hour, p.minute, 0, 0, day.Location())
+	}
+
+	var due time.Time
+	switch {
+	case p.date != nil:
+		due = at(*p.date)
+	case p.rule != nil:
+		due = at(p.firstRuleDay())
+		if p.hasTime && due.Before(p.now) {
+			if next, _, ok := p.rule.After(due, p.now); ok {
+				due = next
+			}
+		}
+	case p.hasTime:
+		due = at(p.today)
+		if due.Before(p.now) {
+			due = at(p.today.AddDate(0, 0, 1))
+		}
+	default:
+		return
+	}
+
+	result.Due = &due
+	result.HasTime = p.hasTime
+	if p.rule != nil {
+		result.RecurRule = p.rule.String()
+	}
+}
+
+// firstRuleDay returns the first day from today that matches the rule's weekdays
+// or day of the month
+func (p *parser) firstRuleDay() time.Time {
+	if len(p.rule.ByMonthDay) > 0 {
+		md := p.rule.ByMonthDay[0]
+		for k := 0; k < 12; k++ {
+			// Months without that day, such as February for the 30th, are skipped
+			day := time.Date(p.today.Year(), p.today.Month()+time.Month(k), md, 0, 0, 0, 0, p.today.Location())
+			if day.Day() == md && !day.Before(p.today) {
+				return day
+			}
+		}
+		return p.today
+	}
+
+	day := p.today
+	if len(p.rule.ByDay) == 0 {
+		return day
+	}
+	for i := 0; i < 7; i++ {
+		for _, d := range p.rule.ByDay {
+			if day.Weekday() == d.Weekday {
+				return day
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return p.today
+}
+
+// matchRecurrence handles "каждый вторник", "каждые 2 недели", "каждый месяц 15 числа",
+// "ежедневно", "по будням", "every weekday", "daily" and "on mondays"
+func (p *parser) matchRecurrence(i int) int {
+	if p.rule != nil {
+		return 0
+	}
+
+	w := p.word(i)
+	switch w {
+	case "ежедневно", "daily", "everyday":
+		p.setRule(rrule.Daily, 1)
+		return 1
+	case "еженедельно", "weekly":
+		p.setRule(rrule.Weekly, 1)
+		return 1
+	case "ежемесячно", "monthly":
+		p.setRule(rrule.Monthly, 1)
+		return 1
+	case "ежегодно", "yearly", "annually":
+		p.setRule(rrule.Yearly, 1)
+		return 1
+	case "по", "on":
+		next := p.word(i + 1)
+		switch next {
+		case "будням", "weekdays":
+			p.setRule(rrule.Weekly, 1, workdays...)
+			return 2
+		case "выходным", "weekends":
+			p.setRule(rrule.Weekly, 1, time.Saturday, time.Sunday)
+			return 2
+		}
+		if days, n := p.weekdayList(i+1, weekdayPlurals); n > 0 {
+			p.setRule(rrule.Weekly, 1, days...)
+			return n + 1
+		}
+		return 0
+	}
+
+	if !everyWords[w] {
+		return 0
+	}
+
+	j := i + 1
+	interval := 1
+	if n, k := p.number(j); k > 0 && units[p.word(j+k)] != 0 {
+		interval, j = n, j+k
+	}
+
+	switch p.word(j) {
+	case "будний", "рабочий":
+		if p.word(j+1) != "день" || interval != 1 {
+			return 0
+		}
+		p.setRule(rrule.Weekly, 1, workdays...)
+		return j + 2 - i
+	case "weekday", "workday":
+		if interval != 1 {
+			return 0
+		}
+		p.setRule(rrule.Weekly, 1, workdays...)
+		return j + 1 - i
+	case "weekend", "выходные":
+		if interval != 1 {
+			return 0
+		}
+		p.setRule(rrule.Weekly, 1, time.Saturday, time.Sunday)
+		return j + 1 - i
+	}
+
+	switch units[p.word(j)] {
+	case unitDay:
+		p.setRule(rrule.Daily, interval)
+		return j + 1 - i
+	case unitWeek:
+		p.setRule(rrule.Weekly, interval)
+		return j + 1 - i
+	case unitMonth:
+		p.setRule(rrule.Monthly, interval)
+		// "каждый месяц 15 числа"
+		if day, ok := parseDayNumber(p.word(j + 1)); ok && p.word(j+2) == "числа" {
+			p.rule.ByMonthDay = []int{day}
+			return j + 3 - i
+		}
+		return j + 1 - i
+	case unitYear:
+		p.setRule(rrule.Yearly, interval)
+		return j + 1 - i
+	}
+
+	if days, n := p.weekdayList(j, weekdays, weekdayAbbrevs); n > 0 {
+		p.setRule(rrule.Weekly, interval, days...)
+		return j + n - i
+	}
+
+	return 0
+}
+
+var workdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+func (p *parser) setRule(freq rrule.Frequency, interval int, days ...time.Weekday) {
+	rule := &rrule.Rule{Freq: freq, Interval: interval, WeekStart: time.Monday}
+
+	// Keep BYDAY in week order starting on Monday
+	days = append([]time.Weekday(nil), days...)
+	sort.Slice(days, func(a, b int) bool {
+		return (days[a]+6)%7 < (days[b]+6)%7
+	})
+	for _, d := range days {
+		rule.ByDay = append(rule.ByDay, rrule.WeekdayNum{Weekday: d})
+	}
+
+	p.rule = rule
+}
+
+// weekdayList matches "вторник", "вторник и четверг", "mon, wed and fri"
+func (p *parser) weekdayList(i int, dicts ...map[string]time.Weekday) ([]time.Weekday, int) {
+	lookup := func(w string) (time.Weekday, bool) {
+		for _, dict := range dicts {
+			if d, ok := dict[w]; ok {
+				return d, true
+			}
+		}
+		return 0, false
+	}
+
+	var days []time.Weekday
+	n := 0
+	for {
+		k := n
+		if n > 0 && (p.word(i+k) == "и" || p.word(i+k) == "and") {
+			k++
+		}
+		d, ok := lookup(p.word(i + k))
+		if !ok {
+			break
+		}
+		days = append(days, d)
+		n = k + 1
+	}
+
+	return days, n
+}
+
+// number matches "2", "два" or "a"
+func (p *parser) number(i int) (int, int) {
+	w := p.word(i)
+	if n, ok := numbers[w]; ok {
+		return n, 1
+	}
+	if n, err := strconv.Atoi(w); err == nil && n > 0 && n < 1000 {
+		return n, 1
+	}
+	return 0, 0
+}
+
+// matchRelative handles "через 2 часа", "через неделю", "через полчаса", "in 15 minutes"
+func (p *parser) matchRelative(i int) int {
+	if p.hasOffset || p.date != nil {
+		return 0
+	}
+
+	w := p.word(i)
+	if w != "через" && w != "in" {
+		return 0
+	}
+
+	if w == "через" && p.word(i+1) == "полчаса" {
+		p.offset, p.hasOffset = 30*time.Minute, true
+		return 2
+	}
+
+	j := i + 1
+	n, k := p.number(j)
+	if k == 0 {
+		// "через час", "через неделю"
+		if w != "через" {
+			return 0
+		}
+		n = 1
+	}
+	j += k
+
+	switch units[p.word(j)] {
+	case unitMinute:
+		p.offset, p.hasOffset = time.Duration(n)*time.Minute, true
+	case unitHour:
+		p.offset, p.hasOffset = time.Duration(n)*time.Hour, true
+	case unitDay:
+		p.setDate(p.today.AddDate(0, 0, n))
+	case unitWeek:
+		p.setDate(p.today.AddDate(0, 0, 7*n))
+	case unitMonth:
+		p.setDate(p.today.AddDate(0, n, 0))
+	case unitYear:
+		p.setDate(p.today.AddDate(n, 0, 0))
+	default:
+		return 0
+	}
+
+	return j + 1 - i
+}
+
+func (p *parser) setDate(t time.Time) {
+	p.date = &t
+}
+
+// matchDayWord handles "сегодня", "завтра", "послезавтра", "tomorrow", "tonight"
+func (p *parser) matchDayWord(i int) int {
+	if p.date != nil {
+		return 0
+	}
+
+	return p.withPrep(i, func(j int, _ bool) int {
+		switch p.word(j) {
+		case "сегодня", "today":
+			p.setDate(p.today)
+		case "завтра", "tomorrow", "tmrw", "tmr":
+			p.setDate(p.today.AddDate(0, 0, 1))
+		case "послезавтра":
+			p.setDate(p.today.AddDate(0, 0, 2))
+		case "tonight":
+			p.setDate(p.today)
+			if !p.hasDayPart {
+				p.dayPart, p.hasDayPart = tonightHour, true
+			}
+		case "day":
+			if p.word(j+1) != "after" || p.word(j+2) != "tomorrow" {
+				return 0
+			}
+			p.setDate(p.today.AddDate(0, 0, 2))
+			return 3
+		default:
+			return 0
+		}
+		return 1
+	})
+}
+
+// matchWeekday handles "в пятницу", "в среду и пятницу", "в следующий вторник", "next monday",
+// "this friday", "на следующей неделе" and "next month"
+func (p *parser) matchWeekday(i int) int {
+	if p.date != nil {
+		return 0
+	}
+
+	return p.withPrep(i, func(j int, prep bool) int {
+		start := j
+		next, this := nextWords[p.word(j)], thisWords[p.word(j)]
+		if next || this {
+			j++
+		}
+
+		if next {
+			switch units[p.word(j)] {
+			case unitWeek:
+				// Monday of the following week
+				p.setDate(p.today.AddDate(0, 0, 7-int(p.today.Weekday()+6)%7))
+				return j + 1 - start
+			case unitMonth:
+				p.setDate(time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, p.today.Location()))
+				return j + 1 - start
+			}
+			switch p.word(j) {
+			case "неделе":
+				p.setDate(p.today.AddDate(0, 0, 7-int(p.today.Weekday()+6)%7))
+				return j + 1 - start
+			case "месяце":
+				p.setDate(time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, p.today.Location()))
+				return j + 1 - start
+			}
+		}
+
+		dicts := []map[string]time.Weekday{weekdays}
+		if prep || next || this {
+			dicts = append(dicts, weekdayAbbrevs)
+		}
+		wds, n := p.weekdayList(j, dicts...)
+		if n == 0 {
+			return 0
+		}
+
+		// "в среду и пятницу" is due on whichever comes first
+		days := 7
+		for _, wd := range wds {
+			d := (int(wd) - int(p.today.Weekday()) + 7) % 7
+			if d == 0 && !this {
+				d = 7
+			}
+			days = min(days, d)
+		}
+		p.setDate(p.today.AddDate(0, 0, days))
+
+		return j + n - start
+	})
+}
+
+// matchDate handles "25.12", "до 5.11", "25.12.2026", "25 декабря", "dec 25th" and
+// "25 december 2026". Dates without a year that have already passed refer to next year.
+func (p *parser) matchDate(i int) int {
+	if p.date != nil {
+		return 0
+	}
+
+	return p.withPrep(i, func(j int, prep bool) int {
+		if day, month, year, ok := parseNumericDate(p.word(j)); ok {
+			// "1.5 литра", "на 1.5 часа" and "Python 3.12" are numbers. A bare
+			// date needs a year or the full "dd.mm" form.
+			if !prep && year == 0 && len(p.word(j)) != len("dd.mm") {
+				return 0
+			}
+			if next := p.word(j + 1); year == 0 && measures[next] || units[next] == unitMinute || units[next] == unitHour {
+				return 0
+			}
+			return p.setDayMonth(day, month, year, 1)
+		}
+
+		if day, ok := parseDayNumber(p.word(j)); ok {
+			if month, ok := months[p.word(j+1)]; ok {
+				year, k := p.year(j + 2)
+				return p.setDayMonth(day, month, year, 2+k)
+			}
+		}
+
+		if month, ok := months[p.word(j)]; ok {
+			if day, ok := parseDayNumber(p.word(j + 1)); ok {
+				year, k := p.year(j + 2)
+				return p.setDayMonth(day, month, year, 2+k)
+			}
+		}
+
+		return 0
+	})
+}
+
+func (p *parser) setDayMonth(day int, month time.Month, year, n int) int {
+	explicitYear := year != 0
+	if !explicitYear {
+		year = p.today.Year()
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, p.today.Location())
+	if t.Day() != day {
+		// 31.02 and the like
+		return 0
+	}
+	if !explicitYear && t.Before(p.today) {
+		t = t.AddDate(1, 0, 0)
+	}
+
+	p.setDate(t)
+	return n
+}
+
+func (p *parser) year(i int) (int, int) {
+	w := strings.TrimSuffix(strings.TrimSuffix(p.word(i), "г"), "года")
+	if len(w) != 4 {
+		return 0, 0
+	}
+	year, err := strconv.Atoi(w)
+	if err != nil {
+		return 0, 0
+	}
+	return year, 1
+}
+
+// parseNumericDate parses "dd.mm", "dd.mm.yy" and "dd.mm.yyyy"
+func parseNumericDate(w string) (day int, month time.Month, year int, ok bool) {
+	parts := strings.Split(w, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, false
+	}
+
+	day, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts[0]) > 2 || day < 1 || day > 31 {
+		return 0, 0, 0, false
+	}
+
+	m, err := strconv.Atoi(parts[1])
+	if err != nil || len(parts[1]) > 2 || m < 1 || m > 12 {
+		return 0, 0, 0, false
+	}
+
+	if len(parts) == 3 {
+		year, err = strconv.Atoi(parts[2])
+		switch {
+		case err != nil:
+			return 0, 0, 0, false
+		case len(parts[2]) == 2:
+			year += 2000
+		case len(parts[2]) != 4:
+			return 0, 0, 0, false
+		}
+	}
+
+	return day, time.Month(m), year, true
+}
+
+// parseDayNumber parses a day of the month such as "25", "25th" or "25-го"
+func parseDayNumber(w string) (int, bool) {
+	for _, suffix := range []string{"-го", "го", "st", "nd", "rd", "th"} {
+		if strings.HasSuffix(w, suffix) {
+			w = strings.TrimSuffix(w, suffix)
+			break
+		}
+	}
+
+	day, err := strconv.Atoi(w)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
+
+// matchTime handles "15:00", "в 15", "в 9 утра", "в 3 дня", "в 7 часов вечера",
+// "9am", "at 9:30 pm", "noon" and "полночь". A bare hour needs a preposition.
+func (p *parser) matchTime(i int) int {
+	if p.hasTime {
+		return 0
+	}
+
+	return p.withPrep(i, func(j int, prep bool) int {
+		switch p.word(j) {
+		case "полдень", "noon":
+			p.hour, p.minute, p.hasTime = 12, 0, true
+			return 1
+		case "полночь", "midnight":
+			p.hour, p.minute, p.hasTime = 0, 0, true
+			return 1
+		}
+
+		hour, minute, explicit, ok := parseClock(p.word(j))
+		if !ok {
+			return 0
+		}
+
+		n := 1
+		if !strings.ContainsAny(p.word(j), "apm") {
+			// "в 3 часа" is a time only thanks to the preposition, "3 часа" alone is a duration
+			if w := p.word(j + n); w == "час" || w == "часа" || w == "часов" || w == "o'clock" {
+				explicit = explicit || w == "o'clock"
+				n++
+			}
+
+			switch p.word(j + n) {
+			case "am", "a.m":
+				if hour > 12 {
+					return 0
+				}
+				hour %= 12
+				explicit = true
+				n++
+			case "pm", "p.m":
+				if hour > 12 {
+					return 0
+				}
+				hour = hour%12 + 12
+				explicit = true
+				n++
+			case "утра":
+				explicit = true
+				n++
+			case "ночи":
+				if hour == 12 {
+					hour = 0
+				}
+				explicit = true
+				n++
+			case "вечера":
+				if hour < 12 {
+					hour += 12
+				}
+				explicit = true
+				n++
+			case "дня":
+				// "3 дня" alone usually means three days
+				if prep {
+					if hour < 12 {
+						hour += 12
+					}
+					explicit = true
+					n++
+				}
+			}
+		}
+
+		if !explicit && !prep {
+			return 0
+		}
+		// "на 10 минут" and "на 3 часа" say how long, not when
+		if !explicit && p.word(j-1) == "на" {
+			if u := units[p.word(j+1)]; u == unitMinute || u == unitHour {
+				return 0
+			}
+		}
+		if hour > 23 || minute > 59 {
+			return 0
+		}
+
+		p.hour, p.minute, p.hasTime = hour, minute, true
+		return n
+	})
+}
+
+// parseClock parses "15:00", "9.30", "9", "9am" and "9:30pm". explicit is false
+// for a bare number or "9.30", which may just as well be a quantity or a version.
+func parseClock(w string) (hour, minute int, explicit, ok bool) {
+	w = strings.ReplaceAll(w, "a.m", "am")
+	w = strings.ReplaceAll(w, "p.m", "pm")
+
+	suffix := ""
+	if strings.HasSuffix(w, "am") || strings.HasSuffix(w, "pm") {
+		suffix = w[len(w)-2:]
+		w = w[:len(w)-2]
+	}
+
+	h, m, colon := strings.Cut(w, ":")
+	hasMinutes := colon
+	if !hasMinutes {
+		h, m, hasMinutes = strings.Cut(w, ".")
+	}
+
+	hour, err := strconv.Atoi(h)
+	if err != nil || len(h) > 2 {
+		return 0, 0, false, false
+	}
+
+	if hasMinutes {
+		if len(m) != 2 {
+			return 0, 0, false, false
+		}
+		if minute, err = strconv.Atoi(m); err != nil {
+			return 0, 0, false, false
+		}
+	}
+
+	switch suffix {
+	case "am":
+		if hour > 12 {
+			return 0, 0, false, false
+		}
+		hour %= 12
+	case "pm":
+		if hour > 12 {
+			return 0, 0, false, false
+		}
+		hour = hour%12 + 12
+	}
+
+	return hour, minute, colon || suffix != "", true
+}
+
+// matchDayPart handles "утром", "вечером", "in the morning" and "this evening".
+// A bare English word is only taken right after a date ("tomorrow morning"),
+// since titles like "morning run" are common.
+func (p *parser) matchDayPart(i int) int {
+	if p.hasDayPart {
+		return 0
+	}
+
+	j := i
+	switch {
+	case p.word(j) == "in" && p.word(j+1) == "the":
+		j += 2
+	case thisWords[p.word(j)]:
+		j++
+	}
+
+	w := p.word(j)
+	hour, ok := dayParts[w]
+	if !ok {
+		return 0
+	}
+
+	russian := w != "" && w[0] >= 0x80
+	if j == i && !russian && (i == 0 || !p.used[i-1]) {
+		return 0
+	}
+
+	p.dayPart, p.hasDayPart = hour, true
+	return j + 1 - i
+}
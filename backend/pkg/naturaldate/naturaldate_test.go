@@ -0,0 +1,195 @@
+package naturaldate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Friday
+	now := time.Date(2026, time.October, 16, 10, 30, 0, 0, moscow)
+
+	tests := []struct {
+		input   string
+		text    string
+		due     string // "2006-01-02 15:04" in Moscow, empty for no date
+		hasTime bool
+		rule    string
+	}{
+		// No date
+		{"Купить молоко", "Купить молоко", "", false, ""},
+		{"Сходить в магазин", "Сходить в магазин", "", false, ""},
+		{"Купить 2 билета", "Купить 2 билета", "", false, ""},
+		{"Morning run", "Morning run", "", false, ""},
+		{"по дороге купить хлеб", "по дороге купить хлеб", "", false, ""},
+		{"Медитация на 10 минут", "Медитация на 10 минут", "", false, ""},
+		{"Позвонить на 15 минут раньше", "Позвонить на 15 минут раньше", "", false, ""},
+		{"поставить на 3 часа духовку", "поставить на 3 часа духовку", "", false, ""},
+		{"Поспать 2 часа", "Поспать 2 часа", "", false, ""},
+		{"Купить 1.5 литра молока", "Купить 1.5 литра молока", "", false, ""},
+		{"Python 3.12 upgrade", "Python 3.12 upgrade", "", false, ""},
+		{"Version 2.10 release", "Version 2.10 release", "", false, ""},
+		{"Встреча на 1.5 часа", "Встреча на 1.5 часа", "", false, ""},
+		{"Налить 10.05 л", "Налить 10.05 л", "", false, ""},
+
+		// Day words
+		{"Позвонить маме завтра в 15:00", "Позвонить маме", "2026-10-17 15:00", true, ""},
+		{"сегодня", "", "2026-10-16 00:00", false, ""},
+		{"Отчёт на завтра", "Отчёт", "2026-10-17 00:00", false, ""},
+		{"послезавтра в 9 утра", "", "2026-10-18 09:00", true, ""},
+		{"Dinner tomorrow at 7pm", "Dinner", "2026-10-17 19:00", true, ""},
+		{"day after tomorrow", "", "2026-10-18 00:00", false, ""},
+		{"tonight", "", "2026-10-16 20:00", true, ""},
+		{"завтра вечером", "", "2026-10-17 19:00", true, ""},
+		{"tomorrow morning", "", "2026-10-17 09:00", true, ""},
+		{"Завтра, Ёлка", "Ёлка", "2026-10-17 00:00", false, ""},
+
+		// Weekdays
+		{"Сдать отчёт в пятницу", "Сдать отчёт", "2026-10-23 00:00", false, ""},
+		{"в понедельник", "", "2026-10-19 00:00", false, ""},
+		{"во вторник в 18:30", "", "2026-10-20 18:30", true, ""},
+		{"в следующий вторник", "", "2026-10-20 00:00", false, ""},
+		{"Тренировка в среду и пятницу", "Тренировка", "2026-10-21 00:00", false, ""},
+		{"this fri and mon", "", "2026-10-16 00:00", false, ""},
+		{"next monday 9am", "", "2026-10-19 09:00", true, ""},
+		{"this friday 6pm", "", "2026-10-16 18:00", true, ""},
+		{"call Bob on wed", "call Bob", "2026-10-21 00:00", false, ""},
+		{"на следующей неделе", "", "2026-10-19 00:00", false, ""},
+		{"next month", "", "2026-11-01 00:00", false, ""},
+
+		// Relative
+		{"Выпить таблетки через 2 часа", "Выпить таблетки", "2026-10-16 12:30", true, ""},
+		{"через полчаса созвон", "созвон", "2026-10-16 11:00", true, ""},
+		{"через час", "", "2026-10-16 11:30", true, ""},
+		{"через пять минут", "", "2026-10-16 10:35", true, ""},
+		{"через 3 дня", "", "2026-10-19 00:00", false, ""},
+		{"через неделю в 10", "", "2026-10-23 10:00", true, ""},
+		{"in 15 minutes", "", "2026-10-16 10:45", true, ""},
+		{"in an hour", "", "2026-10-16 11:30", true, ""},
+		{"in 2 weeks", "", "2026-10-30 00:00", false, ""},
+
+		// Dates
+		{"Встреча 25 декабря в 18:30", "Встреча", "2026-12-25 18:30", true, ""},
+		{"Оплатить до 5.11", "Оплатить", "2026-11-05 00:00", false, ""},
+		{"Оплатить 05.11", "Оплатить", "2026-11-05 00:00", false, ""},
+		{"Оплатить 5.11.26", "Оплатить", "2026-11-05 00:00", false, ""},
+		{"Оплатить 01.03", "Оплатить", "2027-03-01 00:00", false, ""},
+		{"до 31.12.2026", "", "2026-12-31 00:00", false, ""},
+		{"Dec 25th", "", "2026-12-25 00:00", false, ""},
+		{"on 3 march 2027", "", "2027-03-03 00:00", false, ""},
+		{"30 февраля", "30 февраля", "", false, ""},
+
+		// Times
+		{"в 9 утра", "", "2026-10-17 09:00", true, ""},
+		{"в 3 дня", "", "2026-10-16 15:00", true, ""},
+		{"в 15.30", "", "2026-10-16 15:30", true, ""},
+		{"в 7 часов вечера", "", "2026-10-16 19:00", true, ""},
+		{"в 3 часа", "", "2026-10-17 03:00", true, ""},
+		{"на 7 часов вечера", "", "2026-10-16 19:00", true, ""},
+		{"Созвон на 11", "Созвон", "2026-10-16 11:00", true, ""},
+		{"вечером в 8", "", "2026-10-16 20:00", true, ""},
+		{"at noon", "", "2026-10-16 12:00", true, ""},
+		{"11:00", "", "2026-10-16 11:00", true, ""},
+		{"10:00", "", "2026-10-17 10:00", true, ""},
+		{"at 12am", "", "2026-10-17 00:00", true, ""},
+
+		// Recurrence
+		{"Полить цветы каждый вторник", "Полить цветы", "2026-10-20 00:00", false, "FREQ=WEEKLY;BYDAY=TU"},
+		{"каждую пятницу в 19:00", "", "2026-10-16 19:00", true, "FREQ=WEEKLY;BYDAY=FR"},
+		{"Stand-up every weekday at 10:00", "Stand-up", "2026-10-19 10:00", true, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{"каждый будний день", "", "2026-10-16 00:00", false, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{"каждый день в 9", "", "2026-10-17 09:00", true, "FREQ=DAILY"},
+		{"ежедневно", "", "2026-10-16 00:00", false, "FREQ=DAILY"},
+		{"по понедельникам и средам", "", "2026-10-19 00:00", false, "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"по выходным", "", "2026-10-17 00:00", false, "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{"каждые 2 недели", "", "2026-10-16 00:00", false, "FREQ=WEEKLY;INTERVAL=2"},
+		{"every month", "", "2026-10-16 00:00", false, "FREQ=MONTHLY"},
+		{"Оплатить аренду каждый месяц 15 числа", "Оплатить аренду", "2026-11-15 00:00", false, "FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"каждый месяц 16 числа в 12:00", "", "2026-10-16 12:00", true, "FREQ=MONTHLY;BYMONTHDAY=16"},
+		{"каждый месяц 16 числа в 9:00", "", "2026-11-16 09:00", true, "FREQ=MONTHLY;BYMONTHDAY=16"},
+		{"every wed and fri 8am", "", "2026-10-21 08:00", true, "FREQ=WEEKLY;BYDAY=WE,FR"},
+		{"every sunday, monday", "", "2026-10-18 00:00", false, "FREQ=WEEKLY;BYDAY=MO,SU"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := Parse(tt.input, now)
+
+			if got.Text != tt.text {
+				t.Errorf("Text = %q, want %q", got.Text, tt.text)
+			}
+			if got.RecurRule != tt.rule {
+				t.Errorf("RecurRule = %q, want %q", got.RecurRule, tt.rule)
+			}
+			if got.HasTime != tt.hasTime {
+				t.Errorf("HasTime = %v, want %v", got.HasTime, tt.hasTime)
+			}
+
+			if tt.due == "" {
+				if got.Due != nil {
+					t.Errorf("Due = %v, want nil", got.Due)
+				}
+				return
+			}
+
+			want, err := time.ParseInLocation("2006-01-02 15:04", tt.due, moscow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Due == nil {
+				t.Fatalf("Due = nil, want %v", want)
+			}
+			if !got.Due.Equal(want) || got.Due.Location() != moscow {
+				t.Errorf("Due = %v, want %v", got.Due, want)
+			}
+		})
+	}
+}
+
+func TestParseUsesLocationOfNow(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:30 UTC on the 16th is already the 17th in Tokyo
+	now := time.Date(2026, time.October, 16, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		loc  *time.Location
+		want time.Time
+	}{
+		{time.UTC, time.Date(2026, time.October, 17, 15, 0, 0, 0, time.UTC)},
+		{tokyo, time.Date(2026, time.October, 18, 15, 0, 0, 0, tokyo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.loc.String(), func(t *testing.T) {
+			got := Parse("завтра в 15:00", now.In(tt.loc))
+			if got.Due == nil || !got.Due.Equal(tt.want) {
+				t.Errorf("Due = %v, want %v", got.Due, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcrossDaylightSavingChange(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clocks go back on Sunday, October 25, 2026
+	now := time.Date(2026, time.October, 24, 9, 0, 0, 0, berlin)
+
+	got := Parse("завтра в 9:00", now)
+	want := time.Date(2026, time.October, 25, 9, 0, 0, 0, berlin)
+	if got.Due == nil || !got.Due.Equal(want) {
+		t.Errorf("Due = %v, want %v", got.Due, want)
+	}
+}
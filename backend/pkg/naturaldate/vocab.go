@@ -0,0 +1,290 @@
+package naturaldate
+
+import "time"
+
+// Words are matched after lowercasing and replacing "ё" with "е"
+
+var weekdays = map[string]time.Weekday{
+	"понедельник": time.Monday,
+	"вторник":     time.Tuesday,
+	"среда":       time.Wednesday,
+	"среду":       time.Wednesday,
+	"четверг":     time.Thursday,
+	"пятница":     time.Friday,
+	"пятницу":     time.Friday,
+	"суббота":     time.Saturday,
+	"субботу":     time.Saturday,
+	"воскресенье": time.Sunday,
+
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// weekdayAbbrevs are only recognised after a preposition, "next" or "every",
+// since on their own they are too easily part of a title
+var weekdayAbbrevs = map[string]time.Weekday{
+	"пн": time.Monday,
+	"вт": time.Tuesday,
+	"ср": time.Wednesday,
+	"чт": time.Thursday,
+	"пт": time.Friday,
+	"сб": time.Saturday,
+	"вс": time.Sunday,
+
+	"mon":   time.Monday,
+	"tue":   time.Tuesday,
+	"tues":  time.Tuesday,
+	"wed":   time.Wednesday,
+	"thu":   time.Thursday,
+	"thur":  time.Thursday,
+	"thurs": time.Thursday,
+	"fri":   time.Friday,
+	"sat":   time.Saturday,
+	"sun":   time.Sunday,
+}
+
+// weekdayPlurals are used in "по понедельникам" and "on mondays"
+var weekdayPlurals = map[string]time.Weekday{
+	"понедельникам": time.Monday,
+	"вторникам":     time.Tuesday,
+	"средам":        time.Wednesday,
+	"четвергам":     time.Thursday,
+	"пятницам":      time.Friday,
+	"субботам":      time.Saturday,
+	"воскресеньям":  time.Sunday,
+
+	"mondays":    time.Monday,
+	"tuesdays":   time.Tuesday,
+	"wednesdays": time.Wednesday,
+	"thursdays":  time.Thursday,
+	"fridays":    time.Friday,
+	"saturdays":  time.Saturday,
+	"sundays":    time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"января":   time.January,
+	"февраля":  time.February,
+	"марта":    time.March,
+	"апреля":   time.April,
+	"мая":      time.May,
+	"июня":     time.June,
+	"июля":     time.July,
+	"августа":  time.August,
+	"сентября": time.September,
+	"октября":  time.October,
+	"ноября":   time.November,
+	"декабря":  time.December,
+
+	"january":   time.January,
+	"jan":       time.January,
+	"february":  time.February,
+	"feb":       time.February,
+	"march":     time.March,
+	"mar":       time.March,
+	"april":     time.April,
+	"apr":       time.April,
+	"may":       time.May,
+	"june":      time.June,
+	"jun":       time.June,
+	"july":      time.July,
+	"jul":       time.July,
+	"august":    time.August,
+	"aug":       time.August,
+	"september": time.September,
+	"sep":       time.September,
+	"sept":      time.September,
+	"october":   time.October,
+	"oct":       time.October,
+	"november":  time.November,
+	"nov":       time.November,
+	"december":  time.December,
+	"dec":       time.December,
+}
+
+var numbers = map[string]int{
+	"один":       1,
+	"одну":       1,
+	"одна":       1,
+	"два":        2,
+	"две":        2,
+	"пару":       2,
+	"три":        3,
+	"четыре":     4,
+	"пять":       5,
+	"шесть":      6,
+	"семь":       7,
+	"восемь":     8,
+	"девять":     9,
+	"десять":     10,
+	"пятнадцать": 15,
+	"двадцать":   20,
+	"тридцать":   30,
+
+	"a":       1,
+	"an":      1,
+	"one":     1,
+	"two":     2,
+	"three":   3,
+	"four":    4,
+	"five":    5,
+	"six":     6,
+	"seven":   7,
+	"eight":   8,
+	"nine":    9,
+	"ten":     10,
+	"fifteen": 15,
+	"twenty":  20,
+	"thirty":  30,
+}
+
+type unit int
+
+const (
+	unitMinute unit = iota + 1
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+var units = map[string]unit{
+	"минуту":  unitMinute,
+	"минуты":  unitMinute,
+	"минут":   unitMinute,
+	"мин":     unitMinute,
+	"час":     unitHour,
+	"часа":    unitHour,
+	"часов":   unitHour,
+	"день":    unitDay,
+	"дня":     unitDay,
+	"дней":    unitDay,
+	"неделю":  unitWeek,
+	"недели":  unitWeek,
+	"недель":  unitWeek,
+	"месяц":   unitMonth,
+	"месяца":  unitMonth,
+	"месяцев": unitMonth,
+	"год":     unitYear,
+	"года":    unitYear,
+	"лет":     unitYear,
+
+	"minute":  unitMinute,
+	"minutes": unitMinute,
+	"min":     unitMinute,
+	"mins":    unitMinute,
+	"hour":    unitHour,
+	"hours":   unitHour,
+	"hr":      unitHour,
+	"hrs":     unitHour,
+	"day":     unitDay,
+	"days":    unitDay,
+	"week":    unitWeek,
+	"weeks":   unitWeek,
+	"month":   unitMonth,
+	"months":  unitMonth,
+	"year":    unitYear,
+	"years":   unitYear,
+}
+
+// measures follow quantities like "1.5 литра" that would otherwise read as dates
+var measures = map[string]bool{
+	"л":         true,
+	"литр":      true,
+	"литра":     true,
+	"литров":    true,
+	"мл":        true,
+	"кг":        true,
+	"г":         true,
+	"гр":        true,
+	"грамм":     true,
+	"граммов":   true,
+	"км":        true,
+	"м":         true,
+	"см":        true,
+	"мм":        true,
+	"раза":      true,
+	"процента":  true,
+	"процентов": true,
+	"руб":       true,
+	"рубля":     true,
+	"рублей":    true,
+
+	"l":       true,
+	"liter":   true,
+	"liters":  true,
+	"litre":   true,
+	"litres":  true,
+	"ml":      true,
+	"kg":      true,
+	"g":       true,
+	"lb":      true,
+	"lbs":     true,
+	"oz":      true,
+	"km":      true,
+	"m":       true,
+	"cm":      true,
+	"mm":      true,
+	"mi":      true,
+	"miles":   true,
+	"percent": true,
+	"%":       true,
+}
+
+// prepositions may precede a date or time and are dropped together with it
+var prepositions = map[string]bool{
+	"в":  true,
+	"во": true,
+	"на": true,
+	"к":  true,
+	"до": true,
+	"on": true,
+	"at": true,
+	"by": true,
+}
+
+var everyWords = map[string]bool{
+	"каждый": true,
+	"каждую": true,
+	"каждое": true,
+	"каждые": true,
+	"every":  true,
+	"each":   true,
+}
+
+// nextWords and thisWords may precede a weekday. "next friday" is the same
+// day as a bare "friday"; "this friday" also allows today.
+var nextWords = map[string]bool{
+	"следующий": true,
+	"следующую": true,
+	"следующее": true,
+	"следующей": true,
+	"следующем": true,
+	"next":      true,
+}
+
+var thisWords = map[string]bool{
+	"этот": true,
+	"эту":  true,
+	"это":  true,
+	"this": true,
+}
+
+// dayParts map "утром", "вечером" etc. to an hour of the day
+var dayParts = map[string]int{
+	"утром":     9,
+	"днем":      13,
+	"вечером":   19,
+	"morning":   9,
+	"afternoon": 13,
+	"evening":   19,
+}
+
+// tonightHour is used for "tonight" without an explicit time
+const tonightHour = 20
@@ -0,0 +1,534 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the RRULE FREQ value
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxIterations bounds the number of periods scanned when looking for occurrences,
+// so that rules which never produce a match (e.g. BYMONTHDAY=31 with FREQ=MONTHLY;INTERVAL=2
+// starting in a short month) cannot loop forever
+const maxIterations = 50000
+
+var (
+	ErrInvalidRule      = errors.New("invalid recurrence rule")
+	ErrUnsupportedField = errors.New("unsupported recurrence rule field")
+)
+
+// WeekdayNum is a BYDAY entry, e.g. "MO", "2TU" or "-1FR".
+// N is zero when the entry has no ordinal.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is a parsed iCal RRULE (RFC 5545, section 3.3.10).
+// Only the subset needed for everyday chores is supported:
+// FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL and WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+	WeekStart  time.Weekday
+
+	// untilFloating is set when UNTIL had no "Z" suffix, in which case it is
+	// interpreted in the location of DTSTART
+	untilFloating bool
+	untilDate     bool
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse parses an RRULE value. A leading "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return nil, ErrInvalidRule
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(value)
+			default:
+				return nil, fmt.Errorf("%w: FREQ=%s", ErrUnsupportedField, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL=%s", ErrInvalidRule, value)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT=%s", ErrInvalidRule, value)
+			}
+			rule.Count = n
+		case "UNTIL":
+			if err := rule.parseUntil(value); err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(v)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("%w: BYMONTHDAY=%s", ErrInvalidRule, v)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			wd, ok := weekdayCodes[value]
+			if !ok {
+				return nil, fmt.Errorf("%w: WKST=%s", ErrInvalidRule, value)
+			}
+			rule.WeekStart = wd
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedField, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, s)
+	}
+
+	wd, ok := weekdayCodes[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, s)
+	}
+
+	var n int
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+"))
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("%w: BYDAY=%s", ErrInvalidRule, s)
+		}
+	}
+
+	return WeekdayNum{Weekday: wd, N: n}, nil
+}
+
+func (r *Rule) parseUntil(value string) error {
+	layouts := []struct {
+		layout   string
+		floating bool
+		date     bool
+	}{
+		{"20060102T150405Z", false, false},
+		{"20060102T150405", true, false},
+		{"20060102", true, true},
+	}
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l.layout, value); err == nil {
+			r.Until = &t
+			r.untilFloating = l.floating
+			r.untilDate = l.date
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: UNTIL=%s", ErrInvalidRule, value)
+}
+
+// String serializes the rule back to RRULE value format (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayNames[d.Weekday]
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		switch {
+		case r.untilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		case r.untilFloating:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// StringFor serializes the rule for an event whose DTSTART is a DATE (allDay) or
+// a DATE-TIME in loc. RFC 5545 requires UNTIL to have the same value type as
+// DTSTART, and to be in UTC when DTSTART carries a timezone.
+func (r *Rule) StringFor(allDay bool, loc *time.Location) string {
+	if r.Until == nil {
+		return r.String()
+	}
+
+	out := *r
+	until := r.untilIn(loc).In(loc)
+	if allDay {
+		d := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.UTC)
+		out.Until, out.untilDate, out.untilFloating = &d, true, true
+	} else {
+		out.Until, out.untilDate, out.untilFloating = &until, false, false
+	}
+	return out.String()
+}
+
+// untilIn returns the UNTIL bound resolved in the given location.
+// Date-only values are inclusive, so they cover the whole day.
+func (r *Rule) untilIn(loc *time.Location) *time.Time {
+	if r.Until == nil {
+		return nil
+	}
+
+	if !r.untilFloating {
+		return r.Until
+	}
+
+	u := r.Until
+	t := time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, loc)
+	if r.untilDate {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t
+}
+
+// Iterator walks the occurrences of a rule in chronological order.
+// The first occurrence is always DTSTART itself, as required by RFC 5545.
+type Iterator struct {
+	rule    *Rule
+	dtstart time.Time
+	until   *time.Time
+
+	// period is the index of the last expanded period; period 0 is the one
+	// containing DTSTART
+	period  int
+	pending []time.Time
+	emitted int
+	started bool
+	done    bool
+}
+
+// Iterator returns an iterator over the occurrences of the rule starting at dtstart.
+// Occurrences are computed in dtstart's location, so weekdays and days of month
+// follow the wall clock of that location.
+func (r *Rule) Iterator(dtstart time.Time) *Iterator {
+	return &Iterator{
+		rule:    r,
+		dtstart: dtstart,
+		until:   r.untilIn(dtstart.Location()),
+		period:  -1,
+	}
+}
+
+// Next returns the next occurrence and false when the series is exhausted
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done {
+		return time.Time{}, false
+	}
+
+	if it.rule.Count > 0 && it.emitted >= it.rule.Count {
+		it.done = true
+		return time.Time{}, false
+	}
+
+	if !it.started {
+		it.started = true
+		return it.emit(it.dtstart)
+	}
+
+	for len(it.pending) == 0 {
+		if it.period >= maxIterations {
+			it.done = true
+			return time.Time{}, false
+		}
+		it.period++
+
+		for _, t := range it.rule.expand(it.dtstart, it.period) {
+			if t.After(it.dtstart) {
+				it.pending = append(it.pending, t)
+			}
+		}
+
+		// The candidates of a period are sorted, so if the first one is past
+		// UNTIL the whole series is over
+		if len(it.pending) > 0 && it.until != nil && it.pending[0].After(*it.until) {
+			it.done = true
+			return time.Time{}, false
+		}
+	}
+
+	t := it.pending[0]
+	it.pending = it.pending[1:]
+	return it.emit(t)
+}
+
+// Index returns how many occurrences have been returned so far
+func (it *Iterator) Index() int {
+	return it.emitted
+}
+
+func (it *Iterator) emit(t time.Time) (time.Time, bool) {
+	if it.until != nil && t.After(*it.until) {
+		it.done = true
+		return time.Time{}, false
+	}
+	it.emitted++
+	return t, true
+}
+
+// After returns the first occurrence strictly after t, together with its zero-based
+// index in the series. ok is false when the series ends before t.
+func (r *Rule) After(dtstart, t time.Time) (next time.Time, index int, ok bool) {
+	it := r.Iterator(dtstart)
+	for {
+		occ, ok := it.Next()
+		if !ok {
+			return time.Time{}, 0, false
+		}
+		if occ.After(t) {
+			return occ, it.Index() - 1, true
+		}
+	}
+}
+
+// Between returns all occurrences in the inclusive range [from, to]
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var result []time.Time
+
+	it := r.Iterator(dtstart)
+	for {
+		occ, ok := it.Next()
+		if !ok || occ.After(to) {
+			break
+		}
+		if !occ.Before(from) {
+			result = append(result, occ)
+		}
+	}
+
+	return result
+}
+
+// expand returns the sorted candidate occurrences for the n-th period after DTSTART
+func (r *Rule) expand(dtstart time.Time, n int) []time.Time {
+	loc := dtstart.Location()
+	hour, min, sec := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, dtstart.Nanosecond(), loc)
+	}
+
+	var candidates []time.Time
+
+	switch r.Freq {
+	case Daily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+n*r.Interval)
+		if r.matchesDayFilters(day) {
+			candidates = append(candidates, day)
+		}
+
+	case Weekly:
+		// Align to the start of DTSTART's week, then step whole weeks
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+7*n*r.Interval)
+
+		if len(r.ByDay) == 0 {
+			candidates = append(candidates, weekStart.AddDate(0, 0, offset))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if r.hasWeekday(day.Weekday()) && r.matchesMonthDay(day) {
+				candidates = append(candidates, day)
+			}
+		}
+
+	case Monthly:
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, loc)
+		candidates = r.expandMonth(first.Year(), first.Month(), dtstart.Day(), at)
+
+	case Yearly:
+		year := dtstart.Year() + n*r.Interval
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			// Skip years where the day does not exist (e.g. Feb 29)
+			if dtstart.Day() <= daysIn(year, dtstart.Month()) {
+				candidates = append(candidates, at(year, dtstart.Month(), dtstart.Day()))
+			}
+			break
+		}
+		candidates = r.expandMonth(year, dtstart.Month(), dtstart.Day(), at)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// expandMonth returns the days of a month selected by BYMONTHDAY and BYDAY.
+// Without either, the day of month of DTSTART is used; months that are too
+// short for it are skipped, as RFC 5545 requires.
+func (r *Rule) expandMonth(year int, month time.Month, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	days := daysIn(year, month)
+
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if defaultDay > days {
+			return nil
+		}
+		return []time.Time{at(year, month, defaultDay)}
+	}
+
+	var result []time.Time
+	for d := 1; d <= days; d++ {
+		day := at(year, month, d)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesMonthlyByDay(day, days) {
+			continue
+		}
+		result = append(result, day)
+	}
+
+	return result
+}
+
+// matchesDayFilters applies BYDAY and BYMONTHDAY as filters (used for DAILY)
+func (r *Rule) matchesDayFilters(day time.Time) bool {
+	if len(r.ByDay) > 0 && !r.hasWeekday(day.Weekday()) {
+		return false
+	}
+	return r.matchesMonthDay(day)
+}
+
+func (r *Rule) hasWeekday(wd time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	days := daysIn(day.Year(), day.Month())
+	for _, md := range r.ByMonthDay {
+		if md > 0 && day.Day() == md {
+			return true
+		}
+		if md < 0 && day.Day() == days+md+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthlyByDay checks BYDAY entries within a month, honoring ordinals
+// such as "2TU" (second Tuesday) or "-1FR" (last Friday)
+func (r *Rule) matchesMonthlyByDay(day time.Time, days int) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday != day.Weekday() {
+			continue
+		}
+		if d.N == 0 {
+			return true
+		}
+		if d.N > 0 && (day.Day()-1)/7+1 == d.N {
+			return true
+		}
+		if d.N < 0 && (days-day.Day())/7+1 == -d.N {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
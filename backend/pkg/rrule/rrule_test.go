@@ -0,0 +1,444 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // String() of the parsed rule
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,WE", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"rrule:freq=weekly;byday=fr", "FREQ=WEEKLY;BYDAY=FR"},
+		{" FREQ=DAILY;INTERVAL=1 ", "FREQ=DAILY"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "FREQ=MONTHLY;BYDAY=2TU"},
+		{"FREQ=MONTHLY;BYDAY=+1MO,-1FR", "FREQ=MONTHLY;BYDAY=1MO,-1FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15,-1", "FREQ=MONTHLY;BYMONTHDAY=1,15,-1"},
+		{"FREQ=DAILY;COUNT=10", "FREQ=DAILY;COUNT=10"},
+		{"FREQ=DAILY;UNTIL=20261231T235959Z", "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{"FREQ=DAILY;UNTIL=20261231T090000", "FREQ=DAILY;UNTIL=20261231T090000"},
+		{"FREQ=DAILY;UNTIL=20261231", "FREQ=DAILY;UNTIL=20261231"},
+		{"FREQ=WEEKLY;WKST=SU;BYDAY=SA", "FREQ=WEEKLY;BYDAY=SA;WKST=SU"},
+		{"FREQ=WEEKLY;WKST=MO", "FREQ=WEEKLY"},
+		{"FREQ=YEARLY;;", "FREQ=YEARLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// The serialized rule parses back to the same rule
+			again, err := Parse(rule.String())
+			if err != nil {
+				t.Fatalf("Parse(String()) error = %v", err)
+			}
+			if got := again.String(); got != tt.want {
+				t.Errorf("round trip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"", ErrInvalidRule},
+		{"RRULE:", ErrInvalidRule},
+		{"INTERVAL=2", ErrInvalidRule},
+		{"FREQ=DAILY;INTERVAL", ErrInvalidRule},
+		{"FREQ=HOURLY", ErrUnsupportedField},
+		{"FREQ=DAILY;BYHOUR=9", ErrUnsupportedField},
+		{"FREQ=DAILY;INTERVAL=0", ErrInvalidRule},
+		{"FREQ=DAILY;INTERVAL=x", ErrInvalidRule},
+		{"FREQ=DAILY;COUNT=0", ErrInvalidRule},
+		{"FREQ=DAILY;COUNT=3;UNTIL=20261231", ErrInvalidRule},
+		{"FREQ=DAILY;UNTIL=2026-12-31", ErrInvalidRule},
+		{"FREQ=WEEKLY;BYDAY=XX", ErrInvalidRule},
+		{"FREQ=WEEKLY;BYDAY=M", ErrInvalidRule},
+		{"FREQ=MONTHLY;BYDAY=0MO", ErrInvalidRule},
+		{"FREQ=MONTHLY;BYDAY=54MO", ErrInvalidRule},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", ErrInvalidRule},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", ErrInvalidRule},
+		{"FREQ=MONTHLY;BYMONTHDAY=-32", ErrInvalidRule},
+		{"FREQ=WEEKLY;WKST=XX", ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Friday
+	dtstart := time.Date(2026, time.October, 16, 9, 0, 0, 0, moscow)
+	from := dtstart
+	to := time.Date(2027, time.March, 1, 0, 0, 0, 0, moscow)
+
+	tests := []struct {
+		rule    string
+		dtstart time.Time // dtstart when zero
+		limit   int       // only the first occurrences are compared when set
+		want    []string  // "2006-01-02 15:04" in Moscow
+	}{
+		{
+			rule:  "FREQ=DAILY",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-10-17 09:00", "2026-10-18 09:00"},
+		},
+		{
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-10-19 09:00", "2026-10-22 09:00"},
+		},
+		{
+			rule:  "FREQ=DAILY;BYDAY=SA,SU",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-10-17 09:00", "2026-10-18 09:00"},
+		},
+		{
+			rule: "FREQ=DAILY;COUNT=3",
+			want: []string{"2026-10-16 09:00", "2026-10-17 09:00", "2026-10-18 09:00"},
+		},
+		{
+			rule: "FREQ=DAILY;UNTIL=20261018",
+			want: []string{"2026-10-16 09:00", "2026-10-17 09:00", "2026-10-18 09:00"},
+		},
+		{
+			// 06:00 UTC is 09:00 in Moscow, the bound is inclusive
+			rule: "FREQ=DAILY;UNTIL=20261018T060000Z",
+			want: []string{"2026-10-16 09:00", "2026-10-17 09:00", "2026-10-18 09:00"},
+		},
+		{
+			rule: "FREQ=DAILY;UNTIL=20261018T085959",
+			want: []string{"2026-10-16 09:00", "2026-10-17 09:00"},
+		},
+		{
+			// DTSTART always comes first, even when it does not match BYDAY
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE",
+			limit: 5,
+			want:  []string{"2026-10-16 09:00", "2026-10-19 09:00", "2026-10-21 09:00", "2026-10-26 09:00", "2026-10-28 09:00"},
+		},
+		{
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-10-30 09:00", "2026-11-13 09:00"},
+		},
+		{
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH",
+			limit: 4,
+			want:  []string{"2026-10-16 09:00", "2026-10-27 09:00", "2026-10-29 09:00", "2026-11-10 09:00"},
+		},
+		{
+			// With weeks starting on Sunday, Sunday the 18th is in the next week
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU;WKST=SU",
+			limit: 2,
+			want:  []string{"2026-10-16 09:00", "2026-10-25 09:00"},
+		},
+		{
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU",
+			limit: 2,
+			want:  []string{"2026-10-16 09:00", "2026-10-18 09:00"},
+		},
+		{
+			rule: "FREQ=WEEKLY;BYDAY=FR;COUNT=3",
+			want: []string{"2026-10-16 09:00", "2026-10-23 09:00", "2026-10-30 09:00"},
+		},
+		{
+			rule:  "FREQ=MONTHLY",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-11-16 09:00", "2026-12-16 09:00"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+			limit: 4,
+			want:  []string{"2026-10-16 09:00", "2026-10-31 09:00", "2026-11-01 09:00", "2026-11-30 09:00"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=2TU",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-11-10 09:00", "2026-12-08 09:00"},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			limit: 3,
+			want:  []string{"2026-10-16 09:00", "2026-10-30 09:00", "2026-11-27 09:00"},
+		},
+		{
+			// Friday the 13th
+			rule:  "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			limit: 2,
+			want:  []string{"2026-10-16 09:00", "2026-11-13 09:00"},
+		},
+		{
+			// Months without a 31st are skipped
+			rule:    "FREQ=MONTHLY",
+			dtstart: time.Date(2026, time.October, 31, 9, 0, 0, 0, moscow),
+			want:    []string{"2026-10-31 09:00", "2026-12-31 09:00", "2027-01-31 09:00"},
+		},
+		{
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: time.Date(2027, time.January, 31, 9, 0, 0, 0, moscow),
+			want:    []string{"2027-01-31 09:00", "2027-02-28 09:00"},
+		},
+		{
+			rule:  "FREQ=YEARLY",
+			limit: 1,
+			want:  []string{"2026-10-16 09:00"},
+		},
+		{
+			rule:    "FREQ=YEARLY;BYDAY=-1SU",
+			dtstart: time.Date(2026, time.January, 25, 9, 0, 0, 0, moscow),
+			limit:   2,
+			want:    []string{"2026-01-25 09:00", "2027-01-31 09:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := dtstart
+			if !tt.dtstart.IsZero() {
+				start = tt.dtstart
+			}
+			rangeFrom := from
+			if start.Before(rangeFrom) {
+				rangeFrom = start
+			}
+
+			got := rule.Between(start, rangeFrom, to)
+			if tt.limit > 0 && len(got) > tt.limit {
+				got = got[:tt.limit]
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %v", len(got), format(got, moscow), tt.want)
+			}
+			for i := range got {
+				if s := got[i].In(moscow).Format("2006-01-02 15:04"); s != tt.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, s, tt.want[i])
+				}
+				if got[i].Location() != moscow {
+					t.Errorf("occurrence %d location = %v, want %v", i, got[i].Location(), moscow)
+				}
+			}
+		})
+	}
+}
+
+func TestYearlyOnLeapDay(t *testing.T) {
+	rule, err := Parse("FREQ=YEARLY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dtstart := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+	got := rule.Between(dtstart, dtstart, time.Date(2040, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	want := []string{"2024-02-29", "2028-02-29", "2032-02-29"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", format(got, time.UTC), want)
+	}
+	for i := range got {
+		if s := got[i].Format("2006-01-02"); s != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, s, want[i])
+		}
+	}
+}
+
+func TestAcrossDaylightSavingChange(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		want    []time.Time
+	}{
+		{
+			// Clocks go back on Sunday, October 25, 2026; the wall clock time is kept
+			name:    "fall back",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2026, time.October, 24, 9, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2026, time.October, 24, 7, 0, 0, 0, time.UTC),
+				time.Date(2026, time.October, 25, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, time.October, 26, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Clocks go forward on Sunday, March 29, 2026
+			name:    "spring forward",
+			rule:    "FREQ=WEEKLY;BYDAY=SU;COUNT=2",
+			dtstart: time.Date(2026, time.March, 22, 9, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2026, time.March, 22, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 29, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// 02:30 does not exist on March 29, 2026 and is moved forward by an hour
+			name:    "skipped hour",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2026, time.March, 28, 2, 30, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2026, time.March, 28, 1, 30, 0, 0, time.UTC),
+				time.Date(2026, time.March, 29, 1, 30, 0, 0, time.UTC),
+				time.Date(2026, time.March, 30, 0, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := rule.Between(tt.dtstart, tt.dtstart, tt.dtstart.AddDate(0, 1, 0))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i].UTC(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	dtstart := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule  string
+		after time.Time
+		want  time.Time
+		index int
+		ok    bool
+	}{
+		{"FREQ=DAILY", dtstart, dtstart.AddDate(0, 0, 1), 1, true},
+		{"FREQ=DAILY", dtstart.Add(-time.Hour), dtstart, 0, true},
+		{"FREQ=DAILY", dtstart.AddDate(0, 0, 10), dtstart.AddDate(0, 0, 11), 11, true},
+		{"FREQ=WEEKLY;BYDAY=MO", dtstart, time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC), 1, true},
+		{"FREQ=DAILY;COUNT=3", dtstart.AddDate(0, 0, 1), dtstart.AddDate(0, 0, 2), 2, true},
+		{"FREQ=DAILY;COUNT=3", dtstart.AddDate(0, 0, 2), time.Time{}, 0, false},
+		{"FREQ=DAILY;UNTIL=20261020T090000Z", dtstart.AddDate(0, 0, 4), time.Time{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+" after "+tt.after.Format(time.RFC3339), func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, index, ok := rule.After(dtstart, tt.after)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !got.Equal(tt.want) || index != tt.index {
+				t.Errorf("After() = %v, %d, want %v, %d", got, index, tt.want, tt.index)
+			}
+		})
+	}
+}
+
+func TestIteratorStopsAfterMaxIterations(t *testing.T) {
+	// Every 12 months from February is always February, which never has a 30th
+	rule, err := Parse("FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dtstart := time.Date(2026, time.February, 1, 9, 0, 0, 0, time.UTC)
+	it := rule.Iterator(dtstart)
+
+	if got, ok := it.Next(); !ok || !got.Equal(dtstart) {
+		t.Fatalf("first occurrence = %v, %v, want DTSTART", got, ok)
+	}
+	if got, ok := it.Next(); ok {
+		t.Fatalf("Next() = %v, want the series to end", got)
+	}
+	if it.period != maxIterations {
+		t.Errorf("scanned %d periods, want %d", it.period, maxIterations)
+	}
+
+	// The iterator stays exhausted
+	if _, ok := it.Next(); ok {
+		t.Error("Next() after the end returned an occurrence")
+	}
+	if _, _, ok := rule.After(dtstart, dtstart); ok {
+		t.Error("After() found an occurrence")
+	}
+}
+
+func TestStringFor(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rule   string
+		allDay bool
+		want   string
+	}{
+		{"FREQ=DAILY;COUNT=3", false, "FREQ=DAILY;COUNT=3"},
+		{"FREQ=DAILY;UNTIL=20261231", false, "FREQ=DAILY;UNTIL=20261231T205959Z"},
+		{"FREQ=DAILY;UNTIL=20261231T120000", false, "FREQ=DAILY;UNTIL=20261231T090000Z"},
+		{"FREQ=DAILY;UNTIL=20261231T220000Z", true, "FREQ=DAILY;UNTIL=20270101"},
+		{"FREQ=DAILY;UNTIL=20261231", true, "FREQ=DAILY;UNTIL=20261231"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.StringFor(tt.allDay, moscow); got != tt.want {
+				t.Errorf("StringFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func format(times []time.Time, loc *time.Location) []string {
+	out := make([]string, len(times))
+	for i, t := range times {
+		out[i] = t.In(loc).Format("2006-01-02 15:04")
+	}
+	return out
+}
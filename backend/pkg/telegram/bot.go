@@ -2,22 +2,36 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/telegram-task-manager/backend/pkg/i18n"
 )
 
 const (
 	BaseURL    = "https://api.telegram.org/bot"
 	APITimeout = 30 * time.Second
+
+	// Retries of 429 and 5xx responses
+	maxAttempts    = 5
+	retryBaseDelay = time.Second
+	maxRetryDelay  = 30 * time.Second
+	// maxRetryAfter bounds how long a caller is held back by a 429 before giving up
+	maxRetryAfter = time.Minute
 )
 
 type Bot struct {
 	token      string
 	httpClient *http.Client
 	baseURL    string
+	limiter    *rateLimiter
 }
 
 func NewBot(token string) *Bot {
@@ -27,9 +41,16 @@ func NewBot(token string) *Bot {
 			Timeout: APITimeout,
 		},
 		baseURL: BaseURL + token,
+		limiter: newRateLimiter(),
 	}
 }
 
+// chatMessage is implemented by requests that post to a chat and count
+// towards the flood limits
+type chatMessage interface {
+	targetChatID() int64
+}
+
 // SendMessageRequest represents Telegram sendMessage request
 type SendMessageRequest struct {
 	ChatID                int64       `json:"chat_id"`
@@ -40,6 +61,8 @@ type SendMessageRequest struct {
 	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
 }
 
+func (r SendMessageRequest) targetChatID() int64 { return r.ChatID }
+
 // InlineKeyboardMarkup for message buttons
 type InlineKeyboardMarkup struct {
 	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
@@ -60,15 +83,17 @@ type WebAppInfo struct {
 
 // APIResponse represents Telegram API response
 type APIResponse struct {
-	OK          bool            `json:"ok"`
-	Result      json.RawMessage `json:"result,omitempty"`
-	ErrorCode   int             `json:"error_code,omitempty"`
-	Description string          `json:"description,omitempty"`
+	OK          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
 }
 
 // Message represents Telegram message
 type Message struct {
 	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
 	Chat      Chat   `json:"chat"`
 	Text      string `json:"text"`
 	Date      int64  `json:"date"`
@@ -95,7 +120,12 @@ func (b *Bot) SendMessage(chatID int64, text string) (*Message, error) {
 
 // SendMessageWithOptions sends a message with custom options
 func (b *Bot) SendMessageWithOptions(req SendMessageRequest) (*Message, error) {
-	resp, err := b.makeRequest("sendMessage", req)
+	return b.SendMessageContext(context.Background(), req)
+}
+
+// SendMessageContext sends a message with custom options, giving up when ctx is cancelled
+func (b *Bot) SendMessageContext(ctx context.Context, req SendMessageRequest) (*Message, error) {
+	resp, err := b.makeRequestContext(ctx, "sendMessage", req)
 	if err != nil {
 		return nil, err
 	}
@@ -108,39 +138,101 @@ func (b *Bot) SendMessageWithOptions(req SendMessageRequest) (*Message, error) {
 	return &msg, nil
 }
 
-// SendReminderMessage sends a reminder notification with Mini App button
-func (b *Bot) SendReminderMessage(chatID int64, text string, appURL string, itemID string) (*Message, error) {
-	keyboard := InlineKeyboardMarkup{
+// Callback data actions of reminder buttons. Callback data has the form
+// "<action>:<item id>" or "snooze:<item id>:<option>" and is limited to 64 bytes.
+const (
+	CallbackDone   = "done"
+	CallbackSnooze = "snooze"
+)
+
+// SendReminderMessage sends a reminder notification with action buttons
+func (b *Bot) SendReminderMessage(chatID int64, text string, appURL string, itemID string, lang i18n.Lang) (*Message, error) {
+	return b.SendMessageWithOptions(ReminderMessage(chatID, text, appURL, itemID, lang))
+}
+
+// ReminderMessage builds a reminder notification with action buttons
+func ReminderMessage(chatID int64, text string, appURL string, itemID string, lang i18n.Lang) SendMessageRequest {
+	return SendMessageRequest{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   "HTML",
+		ReplyMarkup: ReminderKeyboard(appURL, itemID, lang),
+	}
+}
+
+// ReminderKeyboard builds the Done / Snooze / Open buttons of a reminder
+func ReminderKeyboard(appURL string, itemID string, lang i18n.Lang) InlineKeyboardMarkup {
+	return InlineKeyboardMarkup{
 		InlineKeyboard: [][]InlineKeyboardButton{
 			{
-				{
-					Text: "Open Task",
-					WebApp: &WebAppInfo{
-						URL: fmt.Sprintf("%s?item=%s", appURL, itemID),
-					},
-				},
+				{Text: i18n.T(lang, "button.done"), CallbackData: CallbackDone + ":" + itemID},
+			},
+			{
+				{Text: i18n.T(lang, "button.snooze_15m"), CallbackData: CallbackSnooze + ":" + itemID + ":15m"},
+				{Text: i18n.T(lang, "button.snooze_1h"), CallbackData: CallbackSnooze + ":" + itemID + ":1h"},
+				{Text: i18n.T(lang, "button.snooze_tomorrow"), CallbackData: CallbackSnooze + ":" + itemID + ":tomorrow"},
 			},
+			OpenTaskRow(appURL, itemID, lang),
 		},
 	}
+}
 
-	return b.SendMessageWithOptions(SendMessageRequest{
-		ChatID:      chatID,
-		Text:        text,
-		ParseMode:   "HTML",
-		ReplyMarkup: keyboard,
-	})
+// OpenTaskRow is a keyboard row with a single button opening the item in the Mini App
+func OpenTaskRow(appURL string, itemID string, lang i18n.Lang) []InlineKeyboardButton {
+	return []InlineKeyboardButton{
+		{
+			Text: i18n.T(lang, "button.open_task"),
+			WebApp: &WebAppInfo{
+				URL: fmt.Sprintf("%s?item=%s", appURL, itemID),
+			},
+		},
+	}
+}
+
+// EditMessageTextRequest represents Telegram editMessageText request
+type EditMessageTextRequest struct {
+	ChatID      int64       `json:"chat_id"`
+	MessageID   int64       `json:"message_id"`
+	Text        string      `json:"text"`
+	ParseMode   string      `json:"parse_mode,omitempty"`
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
+
+func (r EditMessageTextRequest) targetChatID() int64 { return r.ChatID }
+
+// EditMessageText replaces the text (and optionally the buttons) of a sent message
+func (b *Bot) EditMessageText(req EditMessageTextRequest) error {
+	_, err := b.makeRequest("editMessageText", req)
+	return err
+}
+
+// AnswerCallbackQueryRequest represents Telegram answerCallbackQuery request
+type AnswerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+}
+
+// AnswerCallbackQuery stops the loading indicator of an inline button, optionally showing a notice
+func (b *Bot) AnswerCallbackQuery(req AnswerCallbackQueryRequest) error {
+	_, err := b.makeRequest("answerCallbackQuery", req)
+	return err
 }
 
 // SendInactivityReminder sends a gentle reminder for inactive users
-func (b *Bot) SendInactivityReminder(chatID int64, appURL string) (*Message, error) {
-	text := "Hey! It's been a while since you checked your tasks. " +
-		"Take a moment to review your progress and stay on track!"
+func (b *Bot) SendInactivityReminder(chatID int64, appURL string, lang i18n.Lang) (*Message, error) {
+	return b.SendMessageWithOptions(InactivityReminderMessage(chatID, appURL, lang))
+}
+
+// InactivityReminderMessage builds a gentle reminder for inactive users
+func InactivityReminderMessage(chatID int64, appURL string, lang i18n.Lang) SendMessageRequest {
+	text := i18n.T(lang, "inactivity.message")
 
 	keyboard := InlineKeyboardMarkup{
 		InlineKeyboard: [][]InlineKeyboardButton{
 			{
 				{
-					Text: "Open Task Manager",
+					Text: i18n.T(lang, "button.open_app"),
 					WebApp: &WebAppInfo{
 						URL: appURL,
 					},
@@ -149,29 +241,34 @@ func (b *Bot) SendInactivityReminder(chatID int64, appURL string) (*Message, err
 		},
 	}
 
-	return b.SendMessageWithOptions(SendMessageRequest{
+	return SendMessageRequest{
 		ChatID:              chatID,
 		Text:                text,
 		ParseMode:           "HTML",
 		DisableNotification: true, // Gentle reminder, no sound
 		ReplyMarkup:         keyboard,
-	})
+	}
 }
 
 // SendOverdueTasksNotification notifies about overdue tasks
-func (b *Bot) SendOverdueTasksNotification(chatID int64, tasks []OverdueTaskInfo, appURL string) (*Message, error) {
+func (b *Bot) SendOverdueTasksNotification(chatID int64, tasks []OverdueTaskInfo, appURL string, lang i18n.Lang) (*Message, error) {
+	return b.SendMessageWithOptions(OverdueTasksMessage(chatID, tasks, appURL, lang))
+}
+
+// OverdueTasksMessage builds the notification about overdue tasks
+func OverdueTasksMessage(chatID int64, tasks []OverdueTaskInfo, appURL string, lang i18n.Lang) SendMessageRequest {
 	var text string
 	if len(tasks) == 1 {
-		text = fmt.Sprintf("You have an overdue task:\n\n<b>%s</b>\nIn board: %s",
-			tasks[0].Title, tasks[0].BoardName)
+		text = i18n.T(lang, "overdue.single",
+			html.EscapeString(tasks[0].Title), html.EscapeString(tasks[0].BoardName))
 	} else {
-		text = fmt.Sprintf("You have %d overdue tasks:\n\n", len(tasks))
+		text = i18n.N(lang, "overdue.count", len(tasks))
 		for i, task := range tasks {
 			if i >= 5 {
-				text += fmt.Sprintf("\n...and %d more", len(tasks)-5)
+				text += "\n" + i18n.T(lang, "list.more", len(tasks)-5)
 				break
 			}
-			text += fmt.Sprintf("- <b>%s</b> (%s)\n", task.Title, task.BoardName)
+			text += i18n.T(lang, "overdue.item", html.EscapeString(task.Title), html.EscapeString(task.BoardName))
 		}
 	}
 
@@ -179,7 +276,7 @@ func (b *Bot) SendOverdueTasksNotification(chatID int64, tasks []OverdueTaskInfo
 		InlineKeyboard: [][]InlineKeyboardButton{
 			{
 				{
-					Text: "View Tasks",
+					Text: i18n.T(lang, "button.view_tasks"),
 					WebApp: &WebAppInfo{
 						URL: appURL,
 					},
@@ -188,12 +285,67 @@ func (b *Bot) SendOverdueTasksNotification(chatID int64, tasks []OverdueTaskInfo
 		},
 	}
 
-	return b.SendMessageWithOptions(SendMessageRequest{
+	return SendMessageRequest{
 		ChatID:      chatID,
 		Text:        text,
 		ParseMode:   "HTML",
 		ReplyMarkup: keyboard,
-	})
+	}
+}
+
+// DigestMessage builds the morning digest from its rendered HTML text
+func DigestMessage(chatID int64, text string, appURL string, lang i18n.Lang) SendMessageRequest {
+	keyboard := InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{
+			{
+				{
+					Text: i18n.T(lang, "button.open_app"),
+					WebApp: &WebAppInfo{
+						URL: appURL,
+					},
+				},
+			},
+		},
+	}
+
+	return SendMessageRequest{
+		ChatID:                chatID,
+		Text:                  text,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+		ReplyMarkup:           keyboard,
+	}
+}
+
+// WeeklyReportMessage builds the weekly productivity report from its rendered HTML text
+func WeeklyReportMessage(chatID int64, text string, appURL string, lang i18n.Lang) SendMessageRequest {
+	keyboard := InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{
+			{
+				{
+					Text: i18n.T(lang, "button.view_analytics"),
+					WebApp: &WebAppInfo{
+						URL: appURL,
+					},
+				},
+			},
+		},
+	}
+
+	return SendMessageRequest{
+		ChatID:              chatID,
+		Text:                text,
+		ParseMode:           "HTML",
+		DisableNotification: true, // Sent on Sunday evenings, no need for sound
+		ReplyMarkup:         keyboard,
+	}
+}
+
+// StartAppLink returns a link that opens the Mini App with the start parameter,
+// for use in message text where web_app buttons are not available.
+// appLink is the direct link of the Mini App, e.g. https://t.me/bot/app.
+func StartAppLink(appLink string, startParam string) string {
+	return appLink + "?startapp=" + url.QueryEscape(startParam)
 }
 
 // OverdueTaskInfo contains info for overdue task notification
@@ -205,14 +357,44 @@ type OverdueTaskInfo struct {
 
 // makeRequest makes a request to Telegram Bot API
 func (b *Bot) makeRequest(method string, payload interface{}) (*APIResponse, error) {
-	url := fmt.Sprintf("%s/%s", b.baseURL, method)
+	return b.makeRequestContext(context.Background(), method, payload)
+}
 
+// makeRequestContext makes a request to Telegram Bot API that is cancelled with ctx.
+// Messages to a chat wait for the rate limiter; 429 responses are retried after
+// retry_after and 5xx responses with exponential backoff.
+func (b *Bot) makeRequestContext(ctx context.Context, method string, payload interface{}) (*APIResponse, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	for attempt := 1; ; attempt++ {
+		if msg, ok := payload.(chatMessage); ok {
+			if err := b.limiter.Wait(ctx, msg.targetChatID()); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := b.doRequest(ctx, method, jsonData)
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, retry := retryDelay(err, attempt)
+		if !retry {
+			return nil, err
+		}
+		if !sleepContext(ctx, delay) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (b *Bot) doRequest(ctx context.Context, method string, jsonData []byte) (*APIResponse, error) {
+	url := fmt.Sprintf("%s/%s", b.baseURL, method)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -232,16 +414,44 @@ func (b *Bot) makeRequest(method string, payload interface{}) (*APIResponse, err
 
 	var apiResp APIResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
+		// Proxies in front of the API may answer 5xx with an HTML page
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, &APIError{Code: resp.StatusCode, Description: resp.Status}
+		}
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if !apiResp.OK {
-		return nil, fmt.Errorf("telegram API error: %s (code: %d)", apiResp.Description, apiResp.ErrorCode)
+		apiErr := &APIError{Code: apiResp.ErrorCode, Description: apiResp.Description}
+		if apiResp.Parameters != nil {
+			apiErr.RetryAfter = time.Duration(apiResp.Parameters.RetryAfter) * time.Second
+		}
+		return nil, apiErr
 	}
 
 	return &apiResp, nil
 }
 
+// retryDelay decides whether a failed request is retried and after how long
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || attempt >= maxAttempts {
+		return 0, false
+	}
+
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests:
+		if apiErr.RetryAfter > maxRetryAfter {
+			return 0, false
+		}
+		return max(apiErr.RetryAfter, time.Second), true
+	case apiErr.Code >= http.StatusInternalServerError:
+		return min(retryBaseDelay<<(attempt-1), maxRetryDelay), true
+	}
+
+	return 0, false
+}
+
 // GetMe returns bot information
 func (b *Bot) GetMe() (*BotInfo, error) {
 	resp, err := b.makeRequest("getMe", nil)
@@ -0,0 +1,44 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is an unsuccessful Bot API response
+type APIError struct {
+	Code        int
+	Description string
+
+	// RetryAfter is set on 429 Too Many Requests
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error: %s (code: %d)", e.Description, e.Code)
+}
+
+// ResponseParameters carries details of a failed request
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
+}
+
+// IsBlocked reports whether err means the user blocked the bot or deleted
+// their account, so nothing can be delivered to them anymore
+func IsBlocked(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
+
+// IsPermanent reports whether retrying the request cannot succeed: the bot
+// was blocked or the API rejected the request itself (400, e.g. chat not found)
+func IsPermanent(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == http.StatusBadRequest || apiErr.Code == http.StatusForbidden
+}
@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bot API broadcast limits: about 30 messages per second in total and one
+// message per second to the same chat
+const (
+	GlobalRateLimit = 30
+	ChatRateLimit   = 1
+
+	// chatBucketTTL is how long an idle chat bucket is kept
+	chatBucketTTL = time.Minute
+)
+
+// rateLimiter queues outgoing messages with a global and a per-chat token bucket
+type rateLimiter struct {
+	mu          sync.Mutex
+	global      *bucket
+	chats       map[int64]*bucket
+	lastCleanup time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	now := time.Now()
+	return &rateLimiter{
+		global:      newBucket(GlobalRateLimit, GlobalRateLimit, now),
+		chats:       make(map[int64]*bucket),
+		lastCleanup: now,
+	}
+}
+
+// Wait blocks until a message may be sent to chatID. The per-chat slot is
+// taken first, so a busy chat does not hold back messages to other chats.
+func (l *rateLimiter) Wait(ctx context.Context, chatID int64) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.cleanup(now)
+	b, ok := l.chats[chatID]
+	if !ok {
+		b = newBucket(ChatRateLimit, 1, now)
+		l.chats[chatID] = b
+	}
+	delay := b.reserve(now)
+	l.mu.Unlock()
+
+	if !sleepContext(ctx, delay) {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	delay = l.global.reserve(time.Now())
+	l.mu.Unlock()
+
+	if !sleepContext(ctx, delay) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (l *rateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < chatBucketTTL {
+		return
+	}
+	for chatID, b := range l.chats {
+		if now.Sub(b.last) > chatBucketTTL {
+			delete(l.chats, chatID)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// bucket is a token bucket that hands out reservations: tokens may go
+// negative, and the caller waits until its token would have been refilled
+type bucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate, burst float64, now time.Time) *bucket {
+	return &bucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// PollTimeout is the long polling timeout passed to getUpdates, in seconds.
+	// It stays below APITimeout so the HTTP client does not give up first.
+	PollTimeout = 25
+
+	pollLimit      = 100
+	pollMinBackoff = time.Second
+	pollMaxBackoff = 30 * time.Second
+)
+
+// UpdateHandler processes a single update
+type UpdateHandler func(ctx context.Context, update *Update)
+
+// OffsetStore persists the getUpdates offset so a restart neither loses nor
+// repeats updates. LoadOffset returns 0 when nothing was stored yet.
+type OffsetStore interface {
+	LoadOffset(ctx context.Context, botID int64) (int64, error)
+	SaveOffset(ctx context.Context, botID int64, offset int64) error
+}
+
+// Poller receives updates with getUpdates long polling, for deployments
+// without a public HTTPS endpoint for the webhook
+type Poller struct {
+	bot     *Bot
+	botID   int64
+	store   OffsetStore
+	handler UpdateHandler
+	logger  *slog.Logger
+}
+
+func NewPoller(bot *Bot, botID int64, store OffsetStore, handler UpdateHandler, logger *slog.Logger) *Poller {
+	return &Poller{
+		bot:     bot,
+		botID:   botID,
+		store:   store,
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// Run polls for updates until ctx is cancelled. API and database errors are
+// logged and retried with backoff. An update that is being handled when ctx
+// is cancelled is finished before Run returns.
+func (p *Poller) Run(ctx context.Context) {
+	offset, ok := p.start(ctx)
+	if !ok {
+		return
+	}
+
+	p.logger.Info("telegram long polling started", "offset", offset)
+
+	backoff := pollMinBackoff
+	for {
+		updates, err := p.bot.GetUpdates(ctx, GetUpdatesRequest{
+			Offset:         offset,
+			Limit:          pollLimit,
+			Timeout:        PollTimeout,
+			AllowedUpdates: AllowedUpdates,
+		})
+		if ctx.Err() != nil {
+			p.logger.Info("telegram long polling stopped")
+			return
+		}
+		if err != nil {
+			p.logger.Error("failed to get telegram updates", "error", err, "retry_in", backoff)
+			if !sleepContext(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, pollMaxBackoff)
+			continue
+		}
+		backoff = pollMinBackoff
+
+		for i := range updates {
+			// Let the update finish even if shutdown starts meanwhile
+			p.handler(context.WithoutCancel(ctx), &updates[i])
+
+			offset = updates[i].UpdateID + 1
+			if err := p.store.SaveOffset(context.WithoutCancel(ctx), p.botID, offset); err != nil {
+				p.logger.Error("failed to save telegram update offset", "offset", offset, "error", err)
+			}
+
+			if ctx.Err() != nil {
+				p.logger.Info("telegram long polling stopped")
+				return
+			}
+		}
+	}
+}
+
+// start removes the webhook, which blocks getUpdates, and loads the stored offset
+func (p *Poller) start(ctx context.Context) (int64, bool) {
+	backoff := pollMinBackoff
+	for {
+		err := p.bot.DeleteWebhook(false)
+		if err == nil {
+			var offset int64
+			if offset, err = p.store.LoadOffset(ctx, p.botID); err == nil {
+				return offset, true
+			}
+		}
+
+		p.logger.Error("failed to start telegram long polling", "error", err, "retry_in", backoff)
+		if !sleepContext(ctx, backoff) {
+			return 0, false
+		}
+		backoff = min(backoff*2, pollMaxBackoff)
+	}
+}
+
+// sleepContext waits for d and reports false if ctx was cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
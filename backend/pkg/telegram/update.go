@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Update represents an incoming update, delivered by webhook or getUpdates
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// CallbackQuery represents a press on an inline keyboard button
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    *User    `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// User represents the sender of a message or callback query
+type User struct {
+	ID           int64  `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// GetUpdatesRequest represents Telegram getUpdates request
+type GetUpdatesRequest struct {
+	Offset         int64    `json:"offset,omitempty"`
+	Limit          int      `json:"limit,omitempty"`
+	Timeout        int      `json:"timeout,omitempty"` // Long polling timeout in seconds
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// GetUpdates receives pending updates, waiting up to req.Timeout seconds for new ones
+func (b *Bot) GetUpdates(ctx context.Context, req GetUpdatesRequest) ([]Update, error) {
+	resp, err := b.makeRequestContext(ctx, "getUpdates", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []Update
+	if err := json.Unmarshal(resp.Result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to parse updates: %w", err)
+	}
+
+	return updates, nil
+}
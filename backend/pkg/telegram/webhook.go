@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+)
+
+// SecretTokenHeader carries the secret_token passed to setWebhook on every webhook request
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// AllowedUpdates lists the update types the bot handles
+var AllowedUpdates = []string{"message", "callback_query"}
+
+// SetWebhookRequest represents Telegram setWebhook request
+type SetWebhookRequest struct {
+	URL                string   `json:"url"`
+	SecretToken        string   `json:"secret_token,omitempty"`
+	AllowedUpdates     []string `json:"allowed_updates"`
+	MaxConnections     int      `json:"max_connections,omitempty"`
+	DropPendingUpdates bool     `json:"drop_pending_updates,omitempty"`
+}
+
+// WebhookInfo represents the result of getWebhookInfo
+type WebhookInfo struct {
+	URL                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int      `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address,omitempty"`
+	LastErrorDate                int64    `json:"last_error_date,omitempty"`
+	LastErrorMessage             string   `json:"last_error_message,omitempty"`
+	LastSynchronizationErrorDate int64    `json:"last_synchronization_error_date,omitempty"`
+	MaxConnections               int      `json:"max_connections,omitempty"`
+	AllowedUpdates               []string `json:"allowed_updates,omitempty"`
+}
+
+// SetWebhook tells Telegram where to deliver updates
+func (b *Bot) SetWebhook(req SetWebhookRequest) error {
+	_, err := b.makeRequest("setWebhook", req)
+	return err
+}
+
+// DeleteWebhook removes the webhook, e.g. before switching to getUpdates
+func (b *Bot) DeleteWebhook(dropPendingUpdates bool) error {
+	_, err := b.makeRequest("deleteWebhook", map[string]bool{
+		"drop_pending_updates": dropPendingUpdates,
+	})
+	return err
+}
+
+// GetWebhookInfo returns the current webhook status
+func (b *Bot) GetWebhookInfo() (*WebhookInfo, error) {
+	resp, err := b.makeRequest("getWebhookInfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info WebhookInfo
+	if err := json.Unmarshal(resp.Result, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook info: %w", err)
+	}
+
+	return &info, nil
+}
+
+// RegisterWebhook sets the webhook and reads it back. The returned info carries
+// the last delivery error reported by Telegram, if any.
+func (b *Bot) RegisterWebhook(req SetWebhookRequest) (*WebhookInfo, error) {
+	if req.AllowedUpdates == nil {
+		req.AllowedUpdates = AllowedUpdates
+	}
+
+	if err := b.SetWebhook(req); err != nil {
+		return nil, err
+	}
+
+	info, err := b.GetWebhookInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.URL != req.URL {
+		return info, fmt.Errorf("webhook URL mismatch: registered %q, got %q", req.URL, info.URL)
+	}
+
+	return info, nil
+}
+
+// VerifySecretToken compares the secret token header of a webhook request in constant time
+func VerifySecretToken(header, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(header), []byte(secret)) == 1
+}
@@ -0,0 +1,14 @@
+#!/bin/sh
+#
+# Database Init Script for Telegram Task Manager
+# Applies the up migrations to a fresh development database. Mounted into
+# /docker-entrypoint-initdb.d, which would otherwise run every *.sql file
+# alphabetically, rollbacks included.
+#
+
+set -e
+
+for migration in /migrations/*.up.sql; do
+    echo "Applying $(basename "$migration")"
+    psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" -f "$migration"
+done
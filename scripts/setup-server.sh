@@ -184,6 +184,7 @@ log_info "Creating environment configuration..."
 if [ ! -f "$APP_DIR/.env" ]; then
     # Generate secure secrets
     JWT_SECRET=$(openssl rand -hex 32)
+    TELEGRAM_WEBHOOK_SECRET=$(openssl rand -hex 32)
     POSTGRES_PASSWORD=$(openssl rand -hex 16)
 
     cat > "$APP_DIR/.env" << EOF
@@ -208,6 +209,7 @@ API_PORT=8080
 
 # Telegram (MUST BE FILLED MANUALLY)
 TELEGRAM_BOT_TOKEN=YOUR_BOT_TOKEN_HERE
+TELEGRAM_WEBHOOK_SECRET=${TELEGRAM_WEBHOOK_SECRET}
 
 # Docker Registry
 DOCKER_REGISTRY=ghcr.io/YOUR_GITHUB_USERNAME